@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,11 +22,24 @@ type CosmosClient interface {
 	QueryClient() *grpc.ClientConn
 	SyncBroadcastMsg(msgs ...sdk.Msg) (*sdk.TxResponse, error)
 	AsyncBroadcastMsg(msgs ...sdk.Msg) (*sdk.TxResponse, error)
-	QueueBroadcastMsg(msgs ...sdk.Msg) error
+	QueueBroadcastMsg(msgs ...sdk.Msg) ([]*TxFuture, error)
+	GasUsage() []MsgGasUsage
 	ClientContext() client.Context
 	Close()
 }
 
+// NewQueryConn connects to the gRPC server at protoAddr, without any key to sign transactions, so it can only be used
+// for queries. protoAddr must be in form "tcp://127.0.0.1:8080" or "unix:///tmp/test.sock", protocol is required.
+func NewQueryConn(protoAddr string) (*grpc.ClientConn, error) {
+	conn, err := grpc.Dial(protoAddr, grpc.WithInsecure(), grpc.WithContextDialer(dialerFunc))
+	if err != nil {
+		err := errors.Wrapf(err, "failed to connect to the gRPC: %s", protoAddr)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
 // NewCosmosClient creates a new gRPC client that communicates with gRPC server at protoAddr.
 // protoAddr must be in form "tcp://127.0.0.1:8080" or "unix:///tmp/test.sock", protocol is required.
 func NewCosmosClient(
@@ -36,9 +48,8 @@ func NewCosmosClient(
 	protoAddr string,
 	options ...CosmosClientOption,
 ) (CosmosClient, error) {
-	conn, err := grpc.Dial(protoAddr, grpc.WithInsecure(), grpc.WithContextDialer(dialerFunc))
+	conn, err := NewQueryConn(protoAddr)
 	if err != nil {
-		err := errors.Wrapf(err, "failed to connect to the gRPC: %s", protoAddr)
 		return nil, err
 	}
 
@@ -55,6 +66,10 @@ func NewCosmosClient(
 		txFactory = txFactory.WithGasPrices(opts.GasPrices)
 	}
 
+	if opts.GasAdjustment > 0 {
+		txFactory = txFactory.WithGasAdjustment(opts.GasAdjustment)
+	}
+
 	cc := &cosmosClient{
 		ctx:  ctx,
 		opts: opts,
@@ -65,8 +80,13 @@ func NewCosmosClient(
 		txFactory: txFactory,
 		canSign:   ctx.Keyring != nil,
 		syncMux:   new(sync.Mutex),
-		msgC:      make(chan sdk.Msg, msgCommitBatchSizeLimit),
+		msgC:      make(chan queuedMsg, msgCommitBatchSizeLimit),
 		doneC:     make(chan bool, 1),
+		gasStats:  newGasStats(),
+		blockMaxGas: &blockMaxGasCache{
+			ttl: blockMaxGasTTL,
+			now: time.Now,
+		},
 	}
 
 	if cc.canSign {
@@ -85,7 +105,10 @@ func NewCosmosClient(
 }
 
 type cosmosClientOptions struct {
-	GasPrices string
+	GasPrices     string
+	GasAdjustment float64
+
+	InsufficientFundsHandler func(error)
 }
 
 func defaultCosmosClientOptions() *cosmosClientOptions {
@@ -107,6 +130,27 @@ func OptionGasPrices(gasPrices string) CosmosClientOption {
 	}
 }
 
+// OptionGasAdjustment sets the multiplier applied to the simulated gas of every tx.
+func OptionGasAdjustment(gasAdjustment float64) CosmosClientOption {
+	return func(opts *cosmosClientOptions) error {
+		if gasAdjustment < 1 {
+			return errors.Errorf("gas adjustment must be at least 1, got %f", gasAdjustment)
+		}
+
+		opts.GasAdjustment = gasAdjustment
+		return nil
+	}
+}
+
+// OptionInsufficientFundsHandler sets a function called with the error every time a tx is rejected because the account
+// can't pay for it, e.g. to stop rather than keep failing.
+func OptionInsufficientFundsHandler(handler func(error)) CosmosClientOption {
+	return func(opts *cosmosClientOptions) error {
+		opts.InsufficientFundsHandler = handler
+		return nil
+	}
+}
+
 func (c *cosmosClient) syncNonce() {
 	num, seq, err := c.txFactory.AccountRetriever().GetAccountNumberSequence(c.ctx, c.ctx.GetFromAddress())
 	if err != nil {
@@ -130,12 +174,17 @@ type cosmosClient struct {
 	txFactory tx.Factory
 
 	doneC   chan bool
-	msgC    chan sdk.Msg
+	msgC    chan queuedMsg
 	syncMux *sync.Mutex
 
 	accNum uint64
 	accSeq uint64
 
+	// feeEscalations is the number of times the gas prices have been escalated after txs rejected for insufficient fees.
+	feeEscalations int
+	gasStats       *gasStats
+	blockMaxGas    *blockMaxGasCache
+
 	closed  int64
 	canSign bool
 }
@@ -171,26 +220,16 @@ func (c *cosmosClient) SyncBroadcastMsg(msgs ...sdk.Msg) (*sdk.TxResponse, error
 	c.syncMux.Lock()
 	defer c.syncMux.Unlock()
 
-	c.txFactory = c.txFactory.WithSequence(c.accSeq)
-	c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-	res, err := c.broadcastTx(c.ctx, c.txFactory, true, msgs...)
-	if err != nil {
-		if strings.Contains(err.Error(), "account sequence mismatch") {
-			c.syncNonce()
-			c.txFactory = c.txFactory.WithSequence(c.accSeq)
-			c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-			c.logger.Debug().Uint64("nonce", c.accSeq).Msg("retrying broadcastTx with nonce")
-			res, err = c.broadcastTx(c.ctx, c.txFactory, true, msgs...)
-		}
-		if err != nil {
-			resJSON, _ := json.MarshalIndent(res, "", "\t")
-			c.logger.Err(err).Int("size", len(msgs)).RawJSON("tx_response", resJSON).Msg("failed to (sync) broadcast tx")
-			return nil, err
-		}
+	res, err := c.broadcastMsgs(true, msgs...)
+	if errors.Is(err, ErrGasLimitExceeded) {
+		// not a failure as such, the caller is expected to split the msgs
+		return nil, err
+	} else if err != nil {
+		resJSON, _ := json.MarshalIndent(res, "", "\t")
+		c.logger.Err(err).Int("size", len(msgs)).RawJSON("tx_response", resJSON).Msg("failed to (sync) broadcast tx")
+		return nil, err
 	}
 
-	c.accSeq++
-
 	return res, nil
 }
 
@@ -201,29 +240,150 @@ func (c *cosmosClient) AsyncBroadcastMsg(msgs ...sdk.Msg) (*sdk.TxResponse, erro
 	c.syncMux.Lock()
 	defer c.syncMux.Unlock()
 
-	c.txFactory = c.txFactory.WithSequence(c.accSeq)
-	c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-	res, err := c.broadcastTx(c.ctx, c.txFactory, false, msgs...)
+	res, err := c.broadcastMsgs(false, msgs...)
 	if err != nil {
-		if strings.Contains(err.Error(), "account sequence mismatch") {
+		resJSON, _ := json.MarshalIndent(res, "", "\t")
+		c.logger.Err(err).Int("size", len(msgs)).RawJSON("tx_response", resJSON).Msg("failed to (async) broadcast tx")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// broadcastMsgs broadcasts msgs in a single tx using the current account sequence. If the sequence is out of date it's
+// synced and the tx is sent again, if the tx is rejected for insufficient fees the gas prices are escalated and the tx
+// is sent again. A tx the account can't pay for fails with ErrInsufficientFunds. The caller must hold syncMux.
+func (c *cosmosClient) broadcastMsgs(await bool, msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+	var (
+		res      *sdk.TxResponse
+		err      error
+		resynced bool
+	)
+
+	for {
+		c.txFactory = c.txFactory.WithSequence(c.accSeq)
+		c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
+		c.logger.Debug().Uint64("nonce", c.accSeq).Msg("broadcastTx with nonce")
+
+		res, err = c.broadcastTx(c.ctx, c.txFactory, await, msgs...)
+
+		if isSequenceMismatch(res, err) && !resynced {
 			c.syncNonce()
-			c.txFactory = c.txFactory.WithSequence(c.accSeq)
-			c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-			c.logger.Debug().Uint64("nonce", c.accSeq).Msg("retrying broadcastTx with nonce")
-			res, err = c.broadcastTx(c.ctx, c.txFactory, false, msgs...)
+			resynced = true
+			continue
 		}
-		if err != nil {
-			resJSON, _ := json.MarshalIndent(res, "", "\t")
-			c.logger.Err(err).Int("size", len(msgs)).RawJSON("tx_response", resJSON).Msg("failed to (async) broadcast tx")
-			return nil, err
+
+		if err == nil && isInsufficientFee(res) && c.escalateFees() {
+			continue
 		}
+
+		break
 	}
 
-	c.accSeq++
+	if err != nil {
+		if isInsufficientFunds(nil, err) {
+			err = c.insufficientFunds(err.Error())
+		}
+
+		return res, err
+	}
+
+	// The sequence is only consumed if the tx passed CheckTx, or it got into a block.
+	if res.Code == 0 || res.Height > 0 {
+		c.accSeq++
+	}
+
+	if res.Code == 0 {
+		c.relaxFees()
+	}
+
+	if res.Height > 0 {
+		c.gasStats.record(msgs, uint64(res.GasUsed))
+		c.logger.Debug().
+			Str("tx_hash", res.TxHash).
+			Int64("gas_wanted", res.GasWanted).
+			Int64("gas_used", res.GasUsed).
+			Int("msgs", len(msgs)).
+			Msg("tx committed")
+	}
+
+	if isInsufficientFunds(res, nil) {
+		return res, c.insufficientFunds(res.RawLog)
+	}
 
 	return res, nil
 }
 
+// insufficientFunds returns ErrInsufficientFunds with the reason the tx was rejected, after passing it to the
+// insufficient funds handler.
+func (c *cosmosClient) insufficientFunds(reason string) error {
+	err := errors.Wrap(ErrInsufficientFunds, reason)
+	if c.opts.InsufficientFundsHandler != nil {
+		c.opts.InsufficientFundsHandler(err)
+	}
+
+	return err
+}
+
+// escalateFees bumps the gas prices used for the txs by feeEscalationFactor. It returns false if no gas prices have
+// been configured or if they have already been escalated maxFeeEscalations times.
+func (c *cosmosClient) escalateFees() bool {
+	if c.feeEscalations >= maxFeeEscalations {
+		return false
+	}
+
+	gasPrices, ok := c.setFeeEscalations(c.feeEscalations + 1)
+	if !ok {
+		return false
+	}
+
+	c.logger.Warn().
+		Str("gas_prices", gasPrices.String()).
+		Int("escalations", c.feeEscalations).
+		Msg("tx rejected for insufficient fees; escalating gas prices")
+
+	return true
+}
+
+// relaxFees brings the escalated gas prices one step back down towards the configured ones once a tx was accepted, so
+// a burst of rejections doesn't keep us overpaying for good.
+func (c *cosmosClient) relaxFees() {
+	if c.feeEscalations == 0 {
+		return
+	}
+
+	if gasPrices, ok := c.setFeeEscalations(c.feeEscalations - 1); ok {
+		c.logger.Debug().
+			Str("gas_prices", gasPrices.String()).
+			Int("escalations", c.feeEscalations).
+			Msg("tx accepted; relaxing gas prices")
+	}
+}
+
+// setFeeEscalations sets the gas prices used for the txs to the configured ones escalated the given number of times.
+// It returns false if no gas prices have been configured.
+func (c *cosmosClient) setFeeEscalations(escalations int) (sdk.DecCoins, bool) {
+	if len(c.opts.GasPrices) == 0 {
+		return nil, false
+	}
+
+	gasPrices, err := sdk.ParseDecCoins(c.opts.GasPrices)
+	if err != nil {
+		return nil, false
+	}
+
+	c.feeEscalations = escalations
+	gasPrices = escalateGasPrices(gasPrices, escalations)
+	c.txFactory = c.txFactory.WithGasPrices(gasPrices.String())
+
+	return gasPrices, true
+}
+
+// GasUsage returns the gas used so far by the committed txs, per message type.
+func (c *cosmosClient) GasUsage() []MsgGasUsage {
+	return c.gasStats.report()
+}
+
 const (
 	defaultBroadcastStatusPoll = 100 * time.Millisecond
 	defaultBroadcastTimeout    = 60 * time.Second
@@ -249,6 +409,18 @@ func (c *cosmosClient) broadcastTx(
 			return nil, err
 		}
 
+		maxGas, err := c.blockMaxGas.get(func() (uint64, error) {
+			return blockMaxGas(clientCtx)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if maxGas > 0 && adjusted > maxGas {
+			err = errors.Wrapf(ErrGasLimitExceeded, "%d msgs need %d gas, block max gas is %d", len(msgs), adjusted, maxGas)
+			return nil, err
+		}
+
 		txf = txf.WithGas(adjusted)
 	}
 
@@ -272,7 +444,8 @@ func (c *cosmosClient) broadcastTx(
 	}
 
 	res, err := clientCtx.BroadcastTxSync(txBytes)
-	if !await || err != nil {
+	if !await || err != nil || res.Code != 0 {
+		// a tx rejected by CheckTx will never make it into a block, there's nothing to wait for
 		return res, err
 	}
 
@@ -345,24 +518,32 @@ func (c *cosmosClient) prepareFactory(clientCtx client.Context, txf tx.Factory)
 
 // QueueBroadcastMsg enqueues a list of messages. Messages will added to the queue
 // and grouped into Txns in chunks. Use this method to mass broadcast Txns with efficiency.
-func (c *cosmosClient) QueueBroadcastMsg(msgs ...sdk.Msg) error {
+// It returns a future per message, that resolves once the tx including it is committed.
+// If not all the messages can be enqueued an error is returned along with the futures of
+// the ones that were.
+func (c *cosmosClient) QueueBroadcastMsg(msgs ...sdk.Msg) ([]*TxFuture, error) {
 	if !c.canSign {
-		return ErrReadOnly
+		return nil, ErrReadOnly
 	} else if atomic.LoadInt64(&c.closed) == 1 {
-		return ErrQueueClosed
+		return nil, ErrQueueClosed
 	}
 
+	futures := make([]*TxFuture, 0, len(msgs))
+
 	t := time.NewTimer(10 * time.Second)
 	for _, msg := range msgs {
+		future := newTxFuture()
+
 		select {
 		case <-t.C:
-			return ErrEnqueueTimeout
-		case c.msgC <- msg:
+			return futures, ErrEnqueueTimeout
+		case c.msgC <- queuedMsg{msg: msg, future: future}:
+			futures = append(futures, future)
 		}
 	}
 	t.Stop()
 
-	return nil
+	return futures, nil
 }
 
 func (c *cosmosClient) Close() {
@@ -388,43 +569,50 @@ const (
 
 func (c *cosmosClient) runBatchBroadcast() {
 	expirationTimer := time.NewTimer(msgCommitBatchTimeLimit)
-	msgBatch := make([]sdk.Msg, 0, msgCommitBatchSizeLimit)
+	msgBatch := make([]queuedMsg, 0, msgCommitBatchSizeLimit)
+
+	var submitBatch func(toSubmit []queuedMsg)
+	submitBatch = func(toSubmit []queuedMsg) {
+		msgs := make([]sdk.Msg, len(toSubmit))
+		for i, qm := range toSubmit {
+			msgs[i] = qm.msg
+		}
 
-	submitBatch := func(toSubmit []sdk.Msg) {
 		c.syncMux.Lock()
-		defer c.syncMux.Unlock()
+		res, err := c.broadcastMsgs(true, msgs...)
+		c.syncMux.Unlock()
 
-		c.txFactory = c.txFactory.WithSequence(c.accSeq)
-		c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-		c.logger.Debug().Uint64("nonce", c.accSeq).Msg("broadcastTx with nonce")
-		res, err := c.broadcastTx(c.ctx, c.txFactory, true, toSubmit...)
-		if err != nil {
-			if strings.Contains(err.Error(), "account sequence mismatch") {
-				c.syncNonce()
-				c.txFactory = c.txFactory.WithSequence(c.accSeq)
-				c.txFactory = c.txFactory.WithAccountNumber(c.accNum)
-				c.logger.Debug().Uint64("nonce", c.accSeq).Msg("retrying broadcastTx with nonce")
-				res, err = c.broadcastTx(c.ctx, c.txFactory, true, toSubmit...)
-			}
-			if err != nil {
-				resJSON, _ := json.MarshalIndent(res, "", "\t")
-				c.logger.Err(err).
-					Int("size", len(toSubmit)).
-					RawJSON("tx_response", resJSON).
-					Msg("failed to (sync) broadcast batch tx")
-				return
-			}
+		if errors.Is(err, ErrGasLimitExceeded) && len(toSubmit) > 1 {
+			c.logger.Debug().Int("size", len(toSubmit)).Msg("batch tx exceeds block max gas; splitting it")
+
+			half := len(toSubmit) / 2
+			submitBatch(toSubmit[:half])
+			submitBatch(toSubmit[half:])
+			return
 		}
 
-		if res.Code != 0 {
-			err = errors.Errorf("error %d (%s): %s", res.Code, res.Codespace, res.RawLog)
+		var txHash string
+
+		switch {
+		case err != nil:
+			resJSON, _ := json.MarshalIndent(res, "", "\t")
+			c.logger.Err(err).
+				Int("size", len(toSubmit)).
+				RawJSON("tx_response", resJSON).
+				Msg("failed to (sync) broadcast batch tx")
+
+		case res.Code != 0:
+			err = newTxError(res)
 			c.logger.Err(err).Str("tx_hash", res.TxHash).Msg("failed to (sync) broadcast batch tx")
-		} else {
+
+		default:
+			txHash = res.TxHash
 			c.logger.Debug().Str("tx_hash", res.TxHash).Msg("batch tx committed successfully")
 		}
 
-		c.accSeq++
-		c.logger.Debug().Uint64("nonce", c.accSeq).Msg("nonce incremented")
+		for _, qm := range toSubmit {
+			qm.future.resolve(txHash, err)
+		}
 	}
 
 	for {
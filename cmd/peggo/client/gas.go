@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxFeeEscalations caps how many times the gas prices are escalated over the configured ones.
+	maxFeeEscalations = 3
+
+	// blockMaxGasTTL is how long the block max gas of the consensus params is cached for, it rarely changes.
+	blockMaxGasTTL = 10 * time.Minute
+)
+
+var (
+	// ErrGasLimitExceeded is returned when the simulated gas of a tx doesn't fit in a block. The caller is expected to
+	// split the messages in smaller txs.
+	ErrGasLimitExceeded = errors.New("tx gas exceeds block max gas")
+
+	// ErrInsufficientFunds is returned when a tx is rejected because the account, or its fee granter, can't pay for it.
+	ErrInsufficientFunds = errors.New("insufficient funds to pay for the tx")
+
+	// feeEscalationFactor is what the gas prices are multiplied by every time a tx is rejected for insufficient fees.
+	feeEscalationFactor = sdk.NewDecWithPrec(15, 1)
+)
+
+// blockMaxGas returns the maximum gas allowed in a block by the consensus params, or zero if there's no limit. With no
+// limit, txs are only bounded by the number of messages their callers put in them (e.g. maxClaimsPerTx for the claims).
+func blockMaxGas(clientCtx client.Context) (uint64, error) {
+	if clientCtx.Client == nil {
+		return 0, nil
+	}
+
+	res, err := clientCtx.Client.ConsensusParams(context.Background(), nil)
+	if err != nil {
+		err = errors.Wrap(err, "failed to query consensus params")
+		return 0, err
+	}
+
+	if res.ConsensusParams.Block.MaxGas <= 0 {
+		return 0, nil
+	}
+
+	return uint64(res.ConsensusParams.Block.MaxGas), nil
+}
+
+// blockMaxGasCache keeps the block max gas for ttl, so it's not queried for every tx.
+type blockMaxGasCache struct {
+	mtx       sync.Mutex
+	ttl       time.Duration
+	maxGas    uint64
+	fetchedAt time.Time
+	fetched   bool
+
+	now func() time.Time
+}
+
+// get returns the cached block max gas, calling fetch if it's missing or older than ttl.
+func (c *blockMaxGasCache) get(fetch func() (uint64, error)) (uint64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.fetched && c.now().Sub(c.fetchedAt) < c.ttl {
+		return c.maxGas, nil
+	}
+
+	maxGas, err := fetch()
+	if err != nil {
+		return 0, err
+	}
+
+	c.maxGas = maxGas
+	c.fetchedAt = c.now()
+	c.fetched = true
+
+	return maxGas, nil
+}
+
+// isInsufficientFee returns true if the tx was rejected by CheckTx because its fees are below the node's minimum.
+func isInsufficientFee(res *sdk.TxResponse) bool {
+	return res != nil &&
+		res.Codespace == sdkerrors.RootCodespace &&
+		res.Code == sdkerrors.ErrInsufficientFee.ABCICode()
+}
+
+// isSequenceMismatch returns true if the tx was rejected because the account sequence we used is out of date.
+func isSequenceMismatch(res *sdk.TxResponse, err error) bool {
+	if err != nil {
+		return errors.Is(err, sdkerrors.ErrWrongSequence) || strings.Contains(err.Error(), "account sequence mismatch")
+	}
+
+	return res != nil &&
+		res.Codespace == sdkerrors.RootCodespace &&
+		res.Code == sdkerrors.ErrWrongSequence.ABCICode()
+}
+
+// isInsufficientFunds returns true if the tx was rejected because the account can't pay its fees, or its fee granter
+// doesn't allow them.
+func isInsufficientFunds(res *sdk.TxResponse, err error) bool {
+	if err != nil {
+		return errors.Is(err, sdkerrors.ErrInsufficientFunds) ||
+			errors.Is(err, feegrant.ErrFeeLimitExceeded) ||
+			errors.Is(err, feegrant.ErrFeeLimitExpired) ||
+			errors.Is(err, feegrant.ErrNoAllowance) ||
+			strings.Contains(err.Error(), "insufficient funds")
+	}
+
+	if res == nil {
+		return false
+	}
+
+	switch res.Codespace {
+	case sdkerrors.RootCodespace:
+		return res.Code == sdkerrors.ErrInsufficientFunds.ABCICode()
+	case feegrant.DefaultCodespace:
+		return res.Code == feegrant.ErrFeeLimitExceeded.ABCICode() ||
+			res.Code == feegrant.ErrFeeLimitExpired.ABCICode() ||
+			res.Code == feegrant.ErrNoAllowance.ABCICode()
+	}
+
+	return false
+}
+
+// escalateGasPrices multiplies all the gas prices by feeEscalationFactor^escalations.
+func escalateGasPrices(gasPrices sdk.DecCoins, escalations int) sdk.DecCoins {
+	factor := sdk.OneDec()
+	for i := 0; i < escalations; i++ {
+		factor = factor.Mul(feeEscalationFactor)
+	}
+
+	escalated := make(sdk.DecCoins, len(gasPrices))
+	for i, gp := range gasPrices {
+		escalated[i] = sdk.NewDecCoinFromDec(gp.Denom, gp.Amount.Mul(factor))
+	}
+
+	return escalated
+}
+
+// MsgGasUsage is the gas used by the txs including a given message type. The gas of a tx including several message
+// types is attributed to each one of them proportionally to its number of messages.
+type MsgGasUsage struct {
+	MsgType string
+	Msgs    uint64
+	GasUsed uint64
+}
+
+// AvgGasPerMsg returns the average gas used per message of this type.
+func (u MsgGasUsage) AvgGasPerMsg() uint64 {
+	if u.Msgs == 0 {
+		return 0
+	}
+
+	return u.GasUsed / u.Msgs
+}
+
+// gasStats accumulates the gas used by the committed txs per message type.
+type gasStats struct {
+	mtx   sync.Mutex
+	usage map[string]*MsgGasUsage
+}
+
+func newGasStats() *gasStats {
+	return &gasStats{usage: map[string]*MsgGasUsage{}}
+}
+
+// record attributes the gas used by a tx to the types of the messages it included.
+func (s *gasStats) record(msgs []sdk.Msg, gasUsed uint64) {
+	if len(msgs) == 0 {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	gasPerMsg := gasUsed / uint64(len(msgs))
+
+	for _, msg := range msgs {
+		msgType := sdk.MsgTypeURL(msg)
+
+		u, ok := s.usage[msgType]
+		if !ok {
+			u = &MsgGasUsage{MsgType: msgType}
+			s.usage[msgType] = u
+		}
+
+		u.Msgs++
+		u.GasUsed += gasPerMsg
+	}
+}
+
+// report returns the gas usage of every message type seen so far, sorted by type.
+func (s *gasStats) report() []MsgGasUsage {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	report := make([]MsgGasUsage, 0, len(s.usage))
+	for _, u := range s.usage {
+		report = append(report, *u)
+	}
+
+	sort.Slice(report, func(i, j int) bool { return report[i].MsgType < report[j].MsgType })
+
+	return report
+}
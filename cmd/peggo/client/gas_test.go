@@ -0,0 +1,110 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscalateGasPrices(t *testing.T) {
+	gasPrices := sdk.NewDecCoins(sdk.NewDecCoinFromDec("uumee", sdk.NewDecWithPrec(4, 2)))
+
+	assert.Equal(t, "0.040000000000000000uumee", escalateGasPrices(gasPrices, 0).String())
+	assert.Equal(t, "0.060000000000000000uumee", escalateGasPrices(gasPrices, 1).String())
+	assert.Equal(t, "0.090000000000000000uumee", escalateGasPrices(gasPrices, 2).String())
+}
+
+func TestFeeEscalations(t *testing.T) {
+	c := &cosmosClient{
+		opts:   &cosmosClientOptions{GasPrices: "0.04uumee"},
+		logger: zerolog.Nop(),
+	}
+
+	for i := 0; i < maxFeeEscalations; i++ {
+		assert.True(t, c.escalateFees())
+	}
+	assert.False(t, c.escalateFees())
+	assert.Equal(t, "0.135000000000000000uumee", c.txFactory.GasPrices().String())
+
+	// every accepted tx brings the gas prices one step back down
+	c.relaxFees()
+	assert.Equal(t, 2, c.feeEscalations)
+	assert.Equal(t, "0.090000000000000000uumee", c.txFactory.GasPrices().String())
+
+	c.relaxFees()
+	c.relaxFees()
+	c.relaxFees()
+	assert.Zero(t, c.feeEscalations)
+	assert.Equal(t, "0.040000000000000000uumee", c.txFactory.GasPrices().String())
+}
+
+func TestBlockMaxGasCache(t *testing.T) {
+	now := time.Now()
+	cache := &blockMaxGasCache{ttl: time.Minute, now: func() time.Time { return now }}
+
+	fetches := 0
+	fetch := func() (uint64, error) {
+		fetches++
+		return uint64(1000 * fetches), nil
+	}
+
+	maxGas, err := cache.get(fetch)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), maxGas)
+
+	maxGas, err = cache.get(fetch)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), maxGas)
+
+	now = now.Add(time.Minute)
+	maxGas, err = cache.get(fetch)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2000), maxGas)
+
+	// errors aren't cached
+	now = now.Add(time.Minute)
+	_, err = cache.get(func() (uint64, error) { return 0, errors.New("rpc error") })
+	assert.Error(t, err)
+	assert.Equal(t, 2, fetches)
+}
+
+func TestBroadcastErrors(t *testing.T) {
+	assert.True(t, isInsufficientFee(&sdk.TxResponse{Codespace: "sdk", Code: 13}))
+	assert.False(t, isInsufficientFee(&sdk.TxResponse{Codespace: "gravity", Code: 13}))
+	assert.False(t, isInsufficientFee(nil))
+
+	assert.True(t, isSequenceMismatch(&sdk.TxResponse{Codespace: "sdk", Code: 32}, nil))
+	assert.True(t, isSequenceMismatch(nil, errors.New("account sequence mismatch, expected 10, got 9")))
+	assert.True(t, isSequenceMismatch(nil, errors.Wrap(sdkerrors.ErrWrongSequence, "simulation failed")))
+	assert.False(t, isSequenceMismatch(&sdk.TxResponse{}, nil))
+
+	assert.True(t, isInsufficientFunds(&sdk.TxResponse{Codespace: "sdk", Code: 5}, nil))
+	assert.True(t, isInsufficientFunds(&sdk.TxResponse{Codespace: "feegrant", Code: 2}, nil))
+	assert.True(t, isInsufficientFunds(nil, errors.New("0uumee is smaller than 200uumee: insufficient funds")))
+	assert.True(t, isInsufficientFunds(nil, errors.Wrap(feegrant.ErrNoAllowance, "simulation failed")))
+	assert.False(t, isInsufficientFunds(&sdk.TxResponse{Codespace: "feegrant", Code: 7}, nil))
+	assert.False(t, isInsufficientFunds(&sdk.TxResponse{}, nil))
+}
+
+func TestGasStats(t *testing.T) {
+	stats := newGasStats()
+
+	stats.record([]sdk.Msg{&types.MsgSendToCosmosClaim{}, &types.MsgSendToCosmosClaim{}}, 200000)
+	stats.record([]sdk.Msg{&types.MsgSendToCosmosClaim{}, &types.MsgBatchSendToEthClaim{}}, 300000)
+	stats.record(nil, 100000)
+
+	assert.Equal(t, []MsgGasUsage{
+		{MsgType: "/gravity.v1.MsgBatchSendToEthClaim", Msgs: 1, GasUsed: 150000},
+		{MsgType: "/gravity.v1.MsgSendToCosmosClaim", Msgs: 3, GasUsed: 350000},
+	}, stats.report())
+
+	assert.Equal(t, uint64(116666), stats.report()[1].AvgGasPerMsg())
+}
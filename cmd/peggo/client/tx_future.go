@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// TxError is what a queued message resolves to when the tx including it was rejected or failed on delivery.
+type TxError struct {
+	TxHash    string
+	Codespace string
+	Code      uint32
+	RawLog    string
+}
+
+func newTxError(res *sdk.TxResponse) *TxError {
+	return &TxError{
+		TxHash:    res.TxHash,
+		Codespace: res.Codespace,
+		Code:      res.Code,
+		RawLog:    res.RawLog,
+	}
+}
+
+func (e *TxError) Error() string {
+	return fmt.Sprintf("tx %s failed with code %d (%s): %s", e.TxHash, e.Code, e.Codespace, e.RawLog)
+}
+
+// TxFuture is the result of a message queued with QueueBroadcastMsg. It resolves once the tx including the message has
+// been committed, or with an error if the message couldn't be broadcast or the tx failed.
+type TxFuture struct {
+	done   chan struct{}
+	txHash string
+	err    error
+}
+
+func newTxFuture() *TxFuture {
+	return &TxFuture{done: make(chan struct{})}
+}
+
+// NewResolvedTxFuture returns a TxFuture that is already resolved with the given tx hash and error.
+func NewResolvedTxFuture(txHash string, err error) *TxFuture {
+	f := newTxFuture()
+	f.resolve(txHash, err)
+
+	return f
+}
+
+// resolve must be called exactly once.
+func (f *TxFuture) resolve(txHash string, err error) {
+	f.txHash = txHash
+	f.err = err
+	close(f.done)
+}
+
+// Done returns a channel that is closed when the future resolves.
+func (f *TxFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await blocks until the future resolves, or ctx is done, and returns the hash of the tx including the message.
+func (f *TxFuture) Await(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-f.done:
+		return f.txHash, f.err
+	}
+}
+
+// AwaitAll waits for all the futures and returns the first error found, if any.
+func AwaitAll(ctx context.Context, futures []*TxFuture) error {
+	for _, f := range futures {
+		if _, err := f.Await(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type queuedMsg struct {
+	msg    sdk.Msg
+	future *TxFuture
+}
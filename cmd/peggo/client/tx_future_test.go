@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxFuture(t *testing.T) {
+	t.Run("resolved", func(t *testing.T) {
+		f := newTxFuture()
+
+		go f.resolve("ABCD", nil)
+
+		txHash, err := f.Await(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "ABCD", txHash)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		txErr := &TxError{TxHash: "ABCD", Codespace: "sdk", Code: 13, RawLog: "insufficient fees"}
+		futures := []*TxFuture{NewResolvedTxFuture("1234", nil), NewResolvedTxFuture("", txErr)}
+
+		err := AwaitAll(context.Background(), futures)
+		assert.Equal(t, txErr, err)
+		assert.EqualError(t, err, "tx ABCD failed with code 13 (sdk): insufficient fees")
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		_, err := newTxFuture().Await(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -2,14 +2,21 @@
 package peggo
 
 import (
+	"time"
+
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/spf13/pflag"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
 )
 
 const (
 	logLevelJSON = "json"
 	logLevelText = "text"
 
+	gasPriceEstimatorAdjustment = "adjustment"
+	gasPriceEstimatorFeeHistory = "fee-history"
+
 	flagLogLevel                = "log-level"
 	flagLogFormat               = "log-format"
 	flagSvcWaitTimeout          = "svc-wait-timeout"
@@ -17,6 +24,7 @@ const (
 	flagCosmosGRPC              = "cosmos-grpc"
 	flagTendermintRPC           = "tendermint-rpc"
 	flagCosmosGasPrices         = "cosmos-gas-prices"
+	flagCosmosGasAdjustment     = "cosmos-gas-adjustment"
 	flagCosmosKeyring           = "cosmos-keyring"
 	flagCosmosKeyringDir        = "cosmos-keyring-dir"
 	flagCosmosKeyringApp        = "cosmos-keyring-app"
@@ -33,7 +41,32 @@ const (
 	flagEthRPC                  = "eth-rpc"
 	flagEthGasAdjustment        = "eth-gas-price-adjustment"
 	flagEthGasLimitAdjustment   = "eth-gas-limit-adjustment"
+	flagEthGasPriceEstimator    = "eth-gas-price-estimator"
+	flagEthFeeHistoryBlocks     = "eth-fee-history-blocks"
+	flagEthFeeHistoryPercentile = "eth-fee-history-percentile"
+	flagEthFeeHistoryUrgent     = "eth-fee-history-urgent-percentile"
 	flagEthAlchemyWS            = "eth-alchemy-ws"
+	flagEthPrivateRelay         = "eth-private-relay"
+	flagEthPrivateRelayMethod   = "eth-private-relay-method"
+	flagEthPrivateRelayBlocks   = "eth-private-relay-blocks"
+	flagEthPrivateRelayTimeout  = "eth-private-relay-timeout"
+	flagEthPrivateRelayKey      = "eth-private-relay-key"
+	flagEthPoolPKs              = "eth-pool-pks"
+	flagEthPoolFrom             = "eth-pool-from"
+	flagEthLowBalanceWarning    = "eth-low-balance-warning"
+	flagEthBalanceReserve       = "eth-balance-reserve"
+	flagCosmosMinBalance        = "cosmos-min-balance"
+	flagBalanceCheckInterval    = "balance-check-interval"
+	flagEthMaxGasPrice          = "eth-max-gas-price"
+	flagEthMaxBaseFeeMultiplier = "eth-max-base-fee-multiplier"
+	flagEthMaxTxCost            = "eth-max-tx-cost"
+	flagEthHourlyBudget         = "eth-hourly-budget"
+	flagEthDailyBudget          = "eth-daily-budget"
+	flagEthSpendStateFile       = "eth-spend-state-file"
+	flagEthWS                   = "eth-ws"
+	flagEthPendingTxBackend     = "eth-pending-tx-backend"
+	flagEthTxPoolPollInterval   = "eth-txpool-poll-interval"
+	flagEventDriven             = "event-driven"
 	flagRelayValsets            = "relay-valsets"
 	flagRelayBatches            = "relay-batches"
 	flagCoinGeckoAPI            = "coingecko-api"
@@ -41,10 +74,21 @@ const (
 	flagEthGasLimit             = "eth-gas-limit"
 	flagAutoApprove             = "auto-approve"
 	flagEthBlocksPerLoop        = "eth-blocks-per-loop"
+	flagEthCatchUpParallelism   = "eth-catch-up-parallelism"
 	flagEthPendingTXWait        = "eth-pending-tx-wait"
 	flagProfitMultiplier        = "profit-multiplier"
 	flagRelayerLoopMultiplier   = "relayer-loop-multiplier"
+	flagRelayerBatchGasBudget   = "relayer-batch-gas-budget"
+	flagBatchTimeoutWarning     = "relayer-batch-timeout-warning"
+	flagBatchUrgencyBlocks      = "relayer-batch-urgency-blocks"
+	flagUrgentProfitMultiplier  = "relayer-urgent-profit-multiplier"
 	flagRequesterLoopMultiplier = "requester-loop-multiplier"
+	flagRequesterMinFeeUSD      = "requester-min-fee-usd"
+	flagRequesterMinFees        = "requester-min-fees"
+	flagRequesterMaxWait        = "requester-max-wait"
+	flagRequesterDenoms         = "requester-denoms"
+	flagRequesterExcludeDenoms  = "requester-exclude-denoms"
+	flagRoles                   = "roles"
 )
 
 func cosmosFlagSet() *pflag.FlagSet {
@@ -54,6 +98,7 @@ func cosmosFlagSet() *pflag.FlagSet {
 	fs.String(flagCosmosGRPC, "tcp://localhost:9090", "The gRPC endpoint of a cosmos node")
 	fs.String(flagTendermintRPC, "http://localhost:26657", "The Tendermint RPC endpoint of a Cosmos node")
 	fs.String(flagCosmosGasPrices, "", "The gas prices to use for Cosmos transaction fees")
+	fs.Float64(flagCosmosGasAdjustment, 1.5, "The multiplier applied to the simulated gas of Cosmos transactions")
 
 	return fs
 }
@@ -89,6 +134,46 @@ func ethereumOptsFlagSet() *pflag.FlagSet {
 	fs.String(flagEthRPC, "http://localhost:8545", "Specify the RPC address of an Ethereum node")
 	fs.Float64(flagEthGasAdjustment, float64(1.3), "Specify a gas price adjustment for Ethereum transactions")
 	fs.Float64(flagEthGasLimitAdjustment, float64(1.2), "Specify a gas limit adjustment for Ethereum transactions")
+	fs.String(flagEthGasPriceEstimator, gasPriceEstimatorAdjustment, "How the gas price of Ethereum transactions is estimated (adjustment|fee-history); adjustment multiplies the node's suggestion by --eth-gas-price-adjustment")
+	fs.Int64(flagEthFeeHistoryBlocks, 20, "Number of Ethereum blocks the fee-history gas price estimator looks back")
+	fs.Float64(flagEthFeeHistoryPercentile, 50, "Percentile of the priority fees paid over the fee history used for transactions that can wait a few blocks (e.g. batches)")
+	fs.Float64(flagEthFeeHistoryUrgent, 90, "Percentile of the priority fees paid over the fee history used for urgent transactions (valset updates and batches about to time out)")
+
+	return fs
+}
+
+func relayerFlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+
+	fs.Bool(flagRelayValsets, false, "Relay validator set updates to Ethereum")
+	fs.Bool(flagRelayBatches, false, "Relay transaction batches to Ethereum")
+	fs.String(flagCoinGeckoAPI, "https://api.coingecko.com/api/v3", "Specify the coingecko API endpoint")
+	fs.Duration(flagEthPendingTXWait, 20*time.Minute, "Time for a pending tx to be considered stale")
+	fs.String(flagEthAlchemyWS, "", "Specify the Alchemy websocket endpoint")
+	fs.String(flagEthPrivateRelay, "", "Specify a private relay endpoint (e.g. Flashbots) to send Ethereum txs to instead of the public mempool")
+	fs.String(flagEthPrivateRelayMethod, string(committer.PrivateRelayBundle), "Method used to send txs to the private relay (bundle|private)")
+	fs.Int64(flagEthPrivateRelayBlocks, 5, "Number of blocks targeted when sending a tx to the private relay")
+	fs.Duration(flagEthPrivateRelayTimeout, 2*time.Minute, "Time to wait for a privately sent tx to be mined before broadcasting it publicly")
+	fs.String(flagEthPrivateRelayKey, "", "Provide the private key (hex) signing the private relay requests, so the relay keeps identifying the relayer across restarts; a random key is used if empty")
+	fs.String(flagEthPoolPKs, "", "Comma separated Ethereum private keys (hex) of additional accounts to relay from, so a stuck tx doesn't hold up the others")
+	fs.String(flagEthPoolFrom, "", "Comma separated addresses of additional accounts in --eth-keystore-dir to relay from, unlocked with --eth-passphrase")
+	fs.Float64(flagEthLowBalanceWarning, 0, "ETH balance below which the accounts relayed from are reported to be topped up; 0 disables it")
+	fs.Float64(flagEthBalanceReserve, 0, "ETH balance the accounts relayed from must keep; txs that would spend it aren't sent. 0 disables it")
+	fs.Duration(flagBalanceCheckInterval, 5*time.Minute, "Interval between checks of the balances of the accounts paying for txs")
+	fs.Float64(flagEthMaxGasPrice, 0, "Gas price (in gwei) above which txs are deferred instead of sent; 0 disables it")
+	fs.Float64(flagEthMaxBaseFeeMultiplier, 0, "Multiple of the base fee above which the gas price of txs is deferred instead of sent; 0 disables it")
+	fs.Float64(flagEthMaxTxCost, 0, "Maximum cost (in ETH) of a tx, i.e. gas limit times gas price, above which it's deferred instead of sent; 0 disables it")
+	fs.Float64(flagEthHourlyBudget, 0, "ETH that can be spent on txs per hour, the txs beyond it being deferred; 0 disables it")
+	fs.Float64(flagEthDailyBudget, 0, "ETH that can be spent on txs per day, the txs beyond it being deferred; 0 disables it")
+	fs.String(flagEthSpendStateFile, "peggo-eth-spend.json", "File the ETH spent over the last day is kept in, so the budgets hold across restarts")
+	fs.String(flagEthPendingTxBackend, string(gravity.PendingTxBackendAlchemy), "Source of the pending Gravity txs used to avoid relaying duplicates (alchemy|subscribe|txpool); subscribe uses --eth-ws and txpool polls --eth-rpc")
+	fs.Duration(flagEthTxPoolPollInterval, 2*time.Second, "Interval between txpool_content polls with the txpool pending tx backend")
+	fs.Float64(flagProfitMultiplier, 1.0, "Multiplier to apply to relayer profit")
+	fs.Float64(flagRelayerLoopMultiplier, 3.0, "Multiplier for the relayer loop duration (in ETH blocks)")
+	fs.Int64(flagRelayerBatchGasBudget, 0, "Maximum Ethereum gas spent relaying batches on each relayer loop; 0 means no limit")
+	fs.Int64(flagBatchTimeoutWarning, 600, "Number of Ethereum blocks before its timeout from which an unrelayed batch is reported")
+	fs.Int64(flagBatchUrgencyBlocks, 0, "Number of Ethereum blocks before their timeout in which batches are relayed at a reduced profit multiplier; 0 disables it")
+	fs.Float64(flagUrgentProfitMultiplier, 0.0, "Profit multiplier applied to batches about to time out, reached linearly within the urgency window")
 
 	return fs
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/knadh/koanf"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
 	"github.com/umee-network/peggo/orchestrator/ethereum/keystore"
 	"golang.org/x/term"
 )
@@ -337,6 +338,77 @@ func initEthereumAccountsManager(
 	}
 }
 
+// initEthereumSenderPool loads the additional accounts Ethereum txs can be sent from, given as private keys or as
+// addresses in the keystore.
+func initEthereumSenderPool(logger zerolog.Logger, ethChainID uint64, konfig *koanf.Koanf) ([]committer.Sender, error) {
+	var senders []committer.Sender
+
+	for _, ethPrivKey := range splitList(konfig.String(flagEthPoolPKs)) {
+		ethPk, err := ethcrypto.HexToECDSA(ethPrivKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hex-decode Ethereum ECDSA Private Key: %w", err)
+		}
+
+		txOpts, err := bind.NewKeyedTransactorWithChainID(ethPk, new(big.Int).SetUint64(ethChainID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to init NewKeyedTransactorWithChainID: %w", err)
+		}
+
+		senders = append(senders, committer.Sender{Address: txOpts.From, Signer: txOpts.Signer})
+	}
+
+	ethPoolFrom := splitList(konfig.String(flagEthPoolFrom))
+	if len(ethPoolFrom) == 0 {
+		return senders, nil
+	}
+
+	ethKeystoreDir := konfig.String(flagEthKeystoreDir)
+	if len(ethKeystoreDir) == 0 {
+		return nil, fmt.Errorf("cannot use --%s without --%s", flagEthPoolFrom, flagEthKeystoreDir)
+	}
+
+	ks, err := keystore.New(logger, ethKeystoreDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Ethereum keystore: %w", err)
+	}
+
+	pass := konfig.String(flagEthPassphrase)
+	if len(pass) == 0 {
+		pass, err = ethPassFromStdin()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, from := range ethPoolFrom {
+		address := ethcmn.HexToAddress(from)
+		if address == (ethcmn.Address{}) {
+			return nil, fmt.Errorf("failed to parse Ethereum from address: %s", from)
+		}
+
+		signerFn, err := ks.SignerFn(ethChainID, address, pass)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key for %s: %w", address, err)
+		}
+
+		senders = append(senders, committer.Sender{Address: address, Signer: signerFn})
+	}
+
+	return senders, nil
+}
+
+// splitList splits a comma separated list, ignoring empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 func ethPassFromStdin() (string, error) {
 	fmt.Fprintln(os.Stderr, "Passphrase for Ethereum account: ")
 	bytePassword, err := term.ReadPassword(syscall.Stdin)
@@ -6,25 +6,26 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	gravitytypes "github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcmn "github.com/ethereum/go-ethereum/common"
-	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/knadh/koanf"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
 	"github.com/umee-network/peggo/cmd/peggo/client"
 	"github.com/umee-network/peggo/orchestrator"
-	"github.com/umee-network/peggo/orchestrator/coingecko"
 	"github.com/umee-network/peggo/orchestrator/cosmos"
-	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
-	gravity "github.com/umee-network/peggo/orchestrator/ethereum/gravity"
-	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
+	"github.com/umee-network/peggo/orchestrator/ethereum/keystore"
 	"github.com/umee-network/peggo/orchestrator/relayer"
-	wrappers "github.com/umee-network/peggo/solwrappers/Gravity.sol"
+	"github.com/umee-network/peggo/orchestrator/scheduler"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 )
@@ -51,41 +52,50 @@ func getOrchestratorCmd() *cobra.Command {
 				return fmt.Errorf("cannot use Ledger for orchestrator")
 			}
 
-			valAddress, cosmosKeyring, err := initCosmosKeyring(konfig)
+			roles, err := orchestrator.ParseRoles(konfig.String(flagRoles))
 			if err != nil {
-				return fmt.Errorf("failed to initialize Cosmos keyring: %w", err)
+				return err
 			}
 
-			cosmosChainID := konfig.String(flagCosmosChainID)
-			clientCtx, err := client.NewClientContext(cosmosChainID, valAddress.String(), cosmosKeyring)
-			if err != nil {
+			if err := validateSingleBridge(args[0], konfig); err != nil {
 				return err
 			}
 
-			tmRPCEndpoint := konfig.String(flagTendermintRPC)
-			cosmosGRPC := konfig.String(flagCosmosGRPC)
-			cosmosGasPrices := konfig.String(flagCosmosGasPrices)
+			if err := validateRoles(logger, konfig, roles); err != nil {
+				return err
+			}
 
-			tmRPC, err := rpchttp.New(tmRPCEndpoint, "/websocket")
+			cosmosMinBalance, err := sdk.ParseCoinsNormalized(konfig.String(flagCosmosMinBalance))
 			if err != nil {
-				return fmt.Errorf("failed to create Tendermint RPC client: %w", err)
+				return fmt.Errorf("failed to parse Cosmos min balance: %w", err)
 			}
 
-			fmt.Fprintf(os.Stderr, "Connected to Tendermint RPC: %s\n", tmRPCEndpoint)
+			// Everything stops once an account runs out of funds, its accounts are set once known.
+			balanceMonitor, err := newBalanceMonitor(logger, konfig)
+			if err != nil {
+				return err
+			}
 
-			var feeGranter sdk.AccAddress
-			if v := konfig.String(flagCosmosFeeGranter); len(v) > 0 {
-				feeGranter, err = sdk.AccAddressFromBech32(v)
+			// Only the oracle, signer and requester send txs to Cosmos, the relayer just queries it.
+			var (
+				daemonClient client.CosmosClient
+				tmRPC        *rpchttp.HTTP
+				valAddress   sdk.AccAddress
+				gRPCConn     *grpc.ClientConn
+			)
+
+			if orchestrator.HasRole(roles, orchestrator.RoleOracle, orchestrator.RoleSigner, orchestrator.RoleRequester) {
+				daemonClient, tmRPC, valAddress, err = newCosmosClient(logger, konfig, balanceMonitor.ReportInsufficientFunds)
 				if err != nil {
-					return fmt.Errorf("failed to parse fee granter address: %w", err)
+					return err
 				}
-			}
 
-			clientCtx = clientCtx.WithClient(tmRPC).WithNodeURI(tmRPCEndpoint).WithFeeGranterAddress(feeGranter)
-
-			daemonClient, err := client.NewCosmosClient(clientCtx, logger, cosmosGRPC, client.OptionGasPrices(cosmosGasPrices))
-			if err != nil {
-				return err
+				gRPCConn = daemonClient.QueryClient()
+			} else {
+				gRPCConn, err = client.NewQueryConn(konfig.String(flagCosmosGRPC))
+				if err != nil {
+					return err
+				}
 			}
 
 			// TODO: Clean this up to be more ergonomic and clean. We can probably
@@ -99,7 +109,6 @@ func getOrchestratorCmd() *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 			defer cancel()
 
-			gRPCConn := daemonClient.QueryClient()
 			waitForService(ctx, gRPCConn)
 
 			gravityQuerier := gravitytypes.NewQueryClient(gRPCConn)
@@ -109,88 +118,92 @@ func getOrchestratorCmd() *cobra.Command {
 				return fmt.Errorf("failed to query for Gravity params: %w", err)
 			}
 
-			ethChainID := gravityParams.BridgeChainId
-			ethKeyFromAddress, signerFn, personalSignFn, err := initEthereumAccountsManager(logger, ethChainID, konfig)
+			gravityAddr, err := validateBridgeTarget(args[0], gravityParams)
 			if err != nil {
-				return fmt.Errorf("failed to initialize Ethereum account: %w", err)
+				return err
 			}
 
-			ethRPCEndpoint := konfig.String(flagEthRPC)
-			ethRPC, err := ethrpc.Dial(ethRPCEndpoint)
-			if err != nil {
-				return fmt.Errorf("failed to dial Ethereum RPC node: %w", err)
-			}
+			// Only the signer and the relayer use the Ethereum key.
+			var (
+				ethKeyFromAddress ethcmn.Address
+				signerFn          bind.SignerFn
+				personalSignFn    keystore.PersonalSignFn
+			)
 
-			fmt.Fprintf(os.Stderr, "Connected to Ethereum RPC: %s\n", ethRPCEndpoint)
-			ethProvider := provider.NewEVMProvider(ethRPC)
+			if orchestrator.HasRole(roles, orchestrator.RoleSigner, orchestrator.RoleRelayer) {
+				ethChainID := gravityParams.BridgeChainId
+				ethKeyFromAddress, signerFn, personalSignFn, err = initEthereumAccountsManager(logger, ethChainID, konfig)
+				if err != nil {
+					return fmt.Errorf("failed to initialize Ethereum account: %w", err)
+				}
+			}
 
-			ethGasPriceAdjustment := konfig.Float64(flagEthGasAdjustment)
-			ethGasLimitAdjustment := konfig.Float64(flagEthGasLimitAdjustment)
-			ethCommitter, err := committer.NewEthCommitter(
-				logger,
-				ethKeyFromAddress,
-				ethGasPriceAdjustment,
-				ethGasLimitAdjustment,
-				signerFn,
-				ethProvider,
-			)
-			if err != nil && err != grpc.ErrServerStopped {
-				return fmt.Errorf("failed to create Ethereum committer: %w", err)
+			var gravityBroadcaster cosmos.GravityBroadcastClient
+			if daemonClient != nil {
+				gravityBroadcaster = cosmos.NewGravityBroadcastClient(
+					logger,
+					gravityQuerier,
+					daemonClient,
+					signerFn,
+					personalSignFn,
+				)
 			}
 
-			gravityBroadcaster := cosmos.NewGravityBroadcastClient(
+			gravityContract, err := newGravityContract(
 				logger,
-				gravityQuerier,
-				daemonClient,
+				konfig,
+				gravityParams.BridgeChainId,
+				gravityAddr,
+				ethKeyFromAddress,
 				signerFn,
-				personalSignFn,
 			)
-
-			gravityAddr := ethcmn.HexToAddress(args[0])
-
-			ethGravity, err := wrappers.NewGravity(gravityAddr, ethCommitter.Provider())
-			if err != nil {
-				return fmt.Errorf("failed to create a new instance of Gravity: %w", err)
-			}
-
-			gravityContract, err := gravity.NewGravityContract(logger, ethCommitter, gravityAddr, ethGravity)
 			if err != nil {
-				return fmt.Errorf("failed to create Ethereum committer: %w", err)
+				return err
 			}
 
-			coingeckoAPI := konfig.String(flagCoinGeckoAPI)
-			coingeckoFeed := coingecko.NewCoingeckoPriceFeed(logger, 100, &coingecko.Config{
-				BaseURL: coingeckoAPI,
-			})
-
 			// gravityParams.AverageBlockTime and gravityParams.AverageEthereumBlockTime are in milliseconds.
 			averageCosmosBlockTime := time.Duration(gravityParams.AverageBlockTime) * time.Millisecond
 			averageEthBlockTime := time.Duration(gravityParams.AverageEthereumBlockTime) * time.Millisecond
 
-			// We multiply the relayer loop multiplier by the ETH block time.
-			// gravityParams.AverageEthereumBlockTime is in milliseconds.
-			ethBlockTimeF64 := float64(averageEthBlockTime.Milliseconds())
-			relayerLoopMultiplier := konfig.Float64(flagRelayerLoopMultiplier)
+			loggerCtx := logger.With().Strs("roles", rolesToStrings(roles))
+			if daemonClient != nil {
+				loggerCtx = loggerCtx.Str("relayer_validator_addr", sdk.ValAddress(valAddress).String())
+			}
+			if signerFn != nil {
+				loggerCtx = loggerCtx.Str("relayer_ethereum_addr", ethKeyFromAddress.String())
+			}
+			logger = loggerCtx.Logger()
+
+			var gravityRelayer relayer.GravityRelayer
+			if orchestrator.HasRole(roles, orchestrator.RoleRelayer) {
+				gravityRelayer, err = newGravityRelayer(
+					logger,
+					konfig,
+					gravityQuerier,
+					gravityContract,
+					averageEthBlockTime,
+					balanceMonitor.ReportInsufficientFunds,
+				)
+				if err != nil {
+					return err
+				}
 
-			// Here we cast the float64 to a Duration (int64); as we are dealing with ms, we'll lose as much as 1ms.
-			relayerLoopDuration := time.Duration(ethBlockTimeF64*relayerLoopMultiplier) * time.Millisecond
+				balanceMonitor.SetEthAccounts(gravityContract.Provider(), ethLowBalance(konfig), gravityContract.SenderAddresses()...)
+			}
 
-			relayer := relayer.NewGravityRelayer(
-				logger,
-				gravityQuerier,
-				gravityContract,
-				konfig.Bool(flagRelayValsets),
-				konfig.Bool(flagRelayBatches),
-				relayerLoopDuration,
-				konfig.Duration(flagEthPendingTXWait),
-				konfig.Float64(flagProfitMultiplier),
-				relayer.SetPriceFeeder(coingeckoFeed),
-			)
+			// The fees are paid by the fee granter if there's one.
+			if daemonClient != nil {
+				payer := daemonClient.ClientContext().GetFeeGranterAddress()
+				if payer.Empty() {
+					payer = valAddress
+				}
 
-			logger = logger.With().
-				Str("relayer_validator_addr", sdk.ValAddress(valAddress).String()).
-				Str("relayer_ethereum_addr", ethKeyFromAddress.String()).
-				Logger()
+				balanceMonitor.SetCosmosAccounts(banktypes.NewQueryClient(gRPCConn), cosmosMinBalance, payer)
+
+				if granter := daemonClient.ClientContext().GetFeeGranterAddress(); !granter.Empty() {
+					balanceMonitor.SetFeeAllowance(feegrant.NewQueryClient(gRPCConn), granter, valAddress)
+				}
+			}
 
 			// Run the requester loop every approximately 60 Cosmos blocks (around 5m by default) to allow time to
 			// receive new transactions. Running this faster will cause a lot of small batches and lots of messages
@@ -203,6 +216,22 @@ func getOrchestratorCmd() *cobra.Command {
 			// Here we cast the float64 to a Duration (int64); as we are dealing with ms, we'll lose as much as 1ms.
 			batchRequesterLoopDuration := time.Duration(cosmosBlockTimeF64*requesterLoopMultiplier) * time.Millisecond
 
+			batchRequestPolicy, err := newBatchRequestPolicy(konfig)
+			if err != nil {
+				return err
+			}
+
+			// Only the oracle and the signer can be woken up by events.
+			var eventScheduler *scheduler.EventScheduler
+			if konfig.Bool(flagEventDriven) && orchestrator.HasRole(roles, orchestrator.RoleOracle, orchestrator.RoleSigner) {
+				// the websocket client needs to be running to subscribe to events
+				if err := tmRPC.Start(); err != nil {
+					return fmt.Errorf("failed to start Tendermint RPC client: %w", err)
+				}
+
+				eventScheduler = scheduler.NewEventScheduler(logger)
+			}
+
 			orch := orchestrator.NewGravityOrchestrator(
 				logger,
 				gravityQuerier,
@@ -211,11 +240,16 @@ func getOrchestratorCmd() *cobra.Command {
 				ethKeyFromAddress,
 				signerFn,
 				personalSignFn,
-				relayer,
+				gravityRelayer,
 				averageCosmosBlockTime,
 				averageEthBlockTime,
 				batchRequesterLoopDuration,
 				konfig.Int64(flagEthBlocksPerLoop),
+				orchestrator.SetEthCatchUpParallelism(konfig.Int(flagEthCatchUpParallelism)),
+				orchestrator.SetEventScheduler(eventScheduler),
+				orchestrator.SetRoles(roles...),
+				orchestrator.SetPriceFeeder(newPriceFeed(logger, konfig)),
+				orchestrator.SetBatchRequestPolicy(batchRequestPolicy),
 			)
 
 			ctx, cancel = context.WithCancel(context.Background())
@@ -225,31 +259,62 @@ func getOrchestratorCmd() *cobra.Command {
 				return startOrchestrator(errCtx, logger, orch)
 			})
 
-			// If we have the alchemy WS endpoint, start listening for txs against the Gravity Bridge contract.
-			alchemyWS := konfig.String(flagEthAlchemyWS)
-			if alchemyWS != "" {
+			g.Go(func() error {
+				return balanceMonitor.Start(errCtx)
+			})
+
+			// Wake up the loops on new Ethereum blocks and Gravity events instead of only relying on timers.
+			if eventScheduler != nil {
+				// loops keep running on their timers while we can't subscribe
 				g.Go(func() error {
-					return gravityContract.SubscribeToPendingTxs(errCtx, alchemyWS)
+					return eventScheduler.SubscribeCosmosEvents(errCtx, tmRPC)
 				})
+
+				if ethWS := konfig.String(flagEthWS); ethWS != "" {
+					g.Go(func() error {
+						return eventScheduler.SubscribeEthereumHeads(errCtx, ethWS)
+					})
+				}
+			}
+
+			if gravityRelayer != nil {
+				startPendingTxBackend(errCtx, g, konfig, gravityContract)
 			}
 
 			// listen for and trap any OS signal to gracefully shutdown and exit
 			trapSignal(cancel)
 
-			return g.Wait()
+			err = g.Wait()
+
+			if daemonClient != nil {
+				for _, u := range daemonClient.GasUsage() {
+					logger.Info().
+						Str("msg_type", u.MsgType).
+						Uint64("msgs", u.Msgs).
+						Uint64("gas_used", u.GasUsed).
+						Uint64("avg_gas_per_msg", u.AvgGasPerMsg()).
+						Msg("Cosmos gas usage")
+				}
+			}
+
+			return err
 		},
 	}
 
-	cmd.Flags().Bool(flagRelayValsets, false, "Relay validator set updates to Ethereum")
-	cmd.Flags().Bool(flagRelayBatches, false, "Relay transaction batches to Ethereum")
+	cmd.Flags().String(flagRoles, strings.Join(rolesToStrings(orchestrator.AllRoles), ","), "Comma separated roles run by this process (oracle|signer|requester|relayer), to split them across processes or hosts")
 	cmd.Flags().Int64(flagEthBlocksPerLoop, 2000, "Number of Ethereum blocks to process per orchestrator loop")
-	cmd.Flags().String(flagCoinGeckoAPI, "https://api.coingecko.com/api/v3", "Specify the coingecko API endpoint")
-	cmd.Flags().Duration(flagEthPendingTXWait, 20*time.Minute, "Time for a pending tx to be considered stale")
-	cmd.Flags().String(flagEthAlchemyWS, "", "Specify the Alchemy websocket endpoint")
-	cmd.Flags().Float64(flagProfitMultiplier, 1.0, "Multiplier to apply to relayer profit")
-	cmd.Flags().Float64(flagRelayerLoopMultiplier, 3.0, "Multiplier for the relayer loop duration (in ETH blocks)")
+	cmd.Flags().Int(flagEthCatchUpParallelism, 4, "Number of Ethereum block windows fetched concurrently when the oracle is catching up; 1 disables it")
+	cmd.Flags().Bool(flagEventDriven, false, "Wake up the oracle and signer loops on new Ethereum blocks and Gravity events, keeping timers as a fallback")
+	cmd.Flags().String(flagEthWS, "", "Specify the websocket endpoint of an Ethereum node used to subscribe to new blocks (requires --event-driven) and pending txs (with the subscribe pending tx backend)")
 	cmd.Flags().Float64(flagRequesterLoopMultiplier, 60.0, "Multiplier for the batch requester loop duration (in Cosmos blocks)")
+	cmd.Flags().Float64(flagRequesterMinFeeUSD, 0, "Value in USD (from --coingecko-api) the unbatched fees of a token must reach for a batch to be requested; 0 disables it")
+	cmd.Flags().String(flagRequesterMinFees, "", "Comma separated amounts of unbatched fees the tokens must reach for a batch to be requested, per denom, instead of --requester-min-fee-usd (e.g. 1000000uumee)")
+	cmd.Flags().Duration(flagRequesterMaxWait, 0, "Time after which a batch is requested for the unbatched fees of a token whatever their value; 0 disables it")
+	cmd.Flags().String(flagRequesterDenoms, "", "Comma separated denoms batches are requested for; all of them if empty")
+	cmd.Flags().String(flagRequesterExcludeDenoms, "", "Comma separated denoms batches are never requested for")
 	cmd.Flags().String(flagCosmosFeeGranter, "", "Set an (optional) fee granter address that will pay for Cosmos fees (feegrant must exist)")
+	cmd.Flags().String(flagCosmosMinBalance, "", "Cosmos balance (e.g. 1000000uumee) below which the account paying for the fees is reported to be topped up")
+	cmd.Flags().AddFlagSet(relayerFlagSet())
 	cmd.Flags().AddFlagSet(cosmosFlagSet())
 	cmd.Flags().AddFlagSet(cosmosKeyringFlagSet())
 	cmd.Flags().AddFlagSet(ethereumKeyOptsFlagSet())
@@ -258,6 +323,153 @@ func getOrchestratorCmd() *cobra.Command {
 	return cmd
 }
 
+// newBatchRequestPolicy returns the batch request policy set by the requester flags.
+func newBatchRequestPolicy(konfig *koanf.Koanf) (orchestrator.BatchRequestPolicy, error) {
+	minFees, err := sdk.ParseCoinsNormalized(konfig.String(flagRequesterMinFees))
+	if err != nil {
+		return orchestrator.BatchRequestPolicy{}, fmt.Errorf("failed to parse requester min fees: %w", err)
+	}
+
+	minFeeUSD := konfig.Float64(flagRequesterMinFeeUSD)
+	if minFeeUSD < 0 {
+		return orchestrator.BatchRequestPolicy{}, fmt.Errorf("invalid requester min fee: %f", minFeeUSD)
+	}
+
+	maxWait := konfig.Duration(flagRequesterMaxWait)
+	if maxWait < 0 {
+		return orchestrator.BatchRequestPolicy{}, fmt.Errorf("invalid requester max wait: %s", maxWait)
+	}
+
+	return orchestrator.BatchRequestPolicy{
+		MinFeeUSD:     minFeeUSD,
+		MinFees:       minFees,
+		MaxWait:       maxWait,
+		AllowedDenoms: splitList(konfig.String(flagRequesterDenoms)),
+		DeniedDenoms:  splitList(konfig.String(flagRequesterExcludeDenoms)),
+	}, nil
+}
+
+// newCosmosClient returns a client able to send txs to Cosmos from the validator key, along with the Tendermint RPC
+// client it uses and the validator address. insufficientFundsHandler is called when a tx can't be paid for.
+func newCosmosClient(
+	logger zerolog.Logger,
+	konfig *koanf.Koanf,
+	insufficientFundsHandler func(error),
+) (client.CosmosClient, *rpchttp.HTTP, sdk.AccAddress, error) {
+	valAddress, cosmosKeyring, err := initCosmosKeyring(konfig)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize Cosmos keyring: %w", err)
+	}
+
+	cosmosChainID := konfig.String(flagCosmosChainID)
+	clientCtx, err := client.NewClientContext(cosmosChainID, valAddress.String(), cosmosKeyring)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tmRPCEndpoint := konfig.String(flagTendermintRPC)
+	cosmosGRPC := konfig.String(flagCosmosGRPC)
+	cosmosGasPrices := konfig.String(flagCosmosGasPrices)
+
+	tmRPC, err := rpchttp.New(tmRPCEndpoint, "/websocket")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create Tendermint RPC client: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Connected to Tendermint RPC: %s\n", tmRPCEndpoint)
+
+	var feeGranter sdk.AccAddress
+	if v := konfig.String(flagCosmosFeeGranter); len(v) > 0 {
+		feeGranter, err = sdk.AccAddressFromBech32(v)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to parse fee granter address: %w", err)
+		}
+	}
+
+	clientCtx = clientCtx.WithClient(tmRPC).WithNodeURI(tmRPCEndpoint).WithFeeGranterAddress(feeGranter)
+
+	daemonClient, err := client.NewCosmosClient(
+		clientCtx,
+		logger,
+		cosmosGRPC,
+		client.OptionGasPrices(cosmosGasPrices),
+		client.OptionGasAdjustment(konfig.Float64(flagCosmosGasAdjustment)),
+		client.OptionInsufficientFundsHandler(insufficientFundsHandler),
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return daemonClient, tmRPC, valAddress, nil
+}
+
+// validateRoles checks the settings needed by the selected roles are set, and warns about the endpoints that can't be
+// reached, since the endpoints have defaults and an unset one only shows up as failing loops. A node that isn't up
+// yet is left to the retries of the loops. The keys are checked when they're loaded.
+func validateRoles(logger zerolog.Logger, konfig *koanf.Koanf, roles []orchestrator.Role) error {
+	required := []struct {
+		flag     string
+		endpoint bool
+		roles    []orchestrator.Role
+	}{
+		{flagCosmosGRPC, true, orchestrator.AllRoles},
+		{flagCosmosChainID, false, []orchestrator.Role{
+			orchestrator.RoleOracle, orchestrator.RoleSigner, orchestrator.RoleRequester,
+		}},
+		{flagTendermintRPC, true, []orchestrator.Role{
+			orchestrator.RoleOracle, orchestrator.RoleSigner, orchestrator.RoleRequester,
+		}},
+		// the requester gets the token decimals from Ethereum to value the unbatched fees
+		{flagEthRPC, true, orchestrator.AllRoles},
+	}
+
+	for _, r := range required {
+		var role orchestrator.Role
+		for _, selected := range roles {
+			if orchestrator.HasRole(r.roles, selected) {
+				role = selected
+				break
+			}
+		}
+
+		if role == "" {
+			continue
+		}
+
+		value := konfig.String(r.flag)
+		if value == "" {
+			return fmt.Errorf("the %s role requires --%s", role, r.flag)
+		}
+
+		if !r.endpoint {
+			continue
+		}
+
+		if err := orchestrator.CheckEndpoint(value); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("role", string(role)).
+				Str("endpoint", value).
+				Msgf("--%s can't be reached; the role fails until it's up", r.flag)
+		}
+	}
+
+	if orchestrator.HasRole(roles, orchestrator.RoleRelayer) {
+		return validatePendingTxBackend(konfig)
+	}
+
+	return nil
+}
+
+func rolesToStrings(roles []orchestrator.Role) []string {
+	s := make([]string, len(roles))
+	for i, role := range roles {
+		s[i] = string(role)
+	}
+
+	return s
+}
+
 func trapSignal(cancel context.CancelFunc) {
 	var sigCh = make(chan os.Signal, 1)
 
@@ -31,6 +31,7 @@ flag (e.g. PEGGO_COSMOS_PK).`,
 
 	cmd.AddCommand(
 		getOrchestratorCmd(),
+		getRelayerCmd(),
 		getBridgeCommand(),
 		getQueryCmd(),
 		getTxCmd(),
@@ -0,0 +1,471 @@
+// nolint: lll
+package peggo
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	gravitytypes "github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/knadh/koanf"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"github.com/umee-network/peggo/cmd/peggo/client"
+	"github.com/umee-network/peggo/orchestrator/balance"
+	"github.com/umee-network/peggo/orchestrator/coingecko"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
+	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
+	"github.com/umee-network/peggo/orchestrator/relayer"
+	wrappers "github.com/umee-network/peggo/solwrappers/Gravity.sol"
+	"golang.org/x/sync/errgroup"
+)
+
+func getRelayerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relayer [gravity-addr]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Starts a standalone relayer",
+		Long: `Starts a standalone relayer, relaying validator set updates and batches to Ethereum
+to earn their rewards and fees.
+
+Unlike the orchestrator, it doesn't need validator keys: only an Ethereum key is used
+to send the txs, and Cosmos is only queried through gRPC.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			konfig, err := parseServerConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			logger, err := getLogger(cmd)
+			if err != nil {
+				return err
+			}
+
+			if konfig.Bool(flagEthUseLedger) {
+				return fmt.Errorf("cannot use Ledger for relayer")
+			}
+
+			if !konfig.Bool(flagRelayValsets) && !konfig.Bool(flagRelayBatches) {
+				return fmt.Errorf("nothing to relay; set --%s and/or --%s", flagRelayValsets, flagRelayBatches)
+			}
+
+			if err := validatePendingTxBackend(konfig); err != nil {
+				return err
+			}
+
+			if err := validateSingleBridge(args[0], konfig); err != nil {
+				return err
+			}
+
+			gRPCConn, err := client.NewQueryConn(konfig.String(flagCosmosGRPC))
+			if err != nil {
+				return err
+			}
+
+			defer gRPCConn.Close()
+
+			fmt.Fprintln(os.Stderr, "Waiting for cosmos gRPC service...")
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			waitForService(ctx, gRPCConn)
+
+			gravityQuerier := gravitytypes.NewQueryClient(gRPCConn)
+
+			gravityParams, err := getGravityParams(gRPCConn)
+			if err != nil {
+				return fmt.Errorf("failed to query for Gravity params: %w", err)
+			}
+
+			gravityAddr, err := validateBridgeTarget(args[0], gravityParams)
+			if err != nil {
+				return err
+			}
+
+			ethKeyFromAddress, signerFn, _, err := initEthereumAccountsManager(logger, gravityParams.BridgeChainId, konfig)
+			if err != nil {
+				return fmt.Errorf("failed to initialize Ethereum account: %w", err)
+			}
+
+			gravityContract, err := newGravityContract(
+				logger,
+				konfig,
+				gravityParams.BridgeChainId,
+				gravityAddr,
+				ethKeyFromAddress,
+				signerFn,
+			)
+			if err != nil {
+				return err
+			}
+
+			// gravityParams.AverageEthereumBlockTime is in milliseconds.
+			averageEthBlockTime := time.Duration(gravityParams.AverageEthereumBlockTime) * time.Millisecond
+
+			logger = logger.With().
+				Str("relayer_ethereum_addr", ethKeyFromAddress.String()).
+				Logger()
+
+			// there's no Cosmos account to pay for, the relayer only queries it
+			balanceMonitor, err := newBalanceMonitor(logger, konfig)
+			if err != nil {
+				return err
+			}
+
+			balanceMonitor.SetEthAccounts(gravityContract.Provider(), ethLowBalance(konfig), gravityContract.SenderAddresses()...)
+
+			gravityRelayer, err := newGravityRelayer(
+				logger,
+				konfig,
+				gravityQuerier,
+				gravityContract,
+				averageEthBlockTime,
+				balanceMonitor.ReportInsufficientFunds,
+			)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel = context.WithCancel(context.Background())
+			g, errCtx := errgroup.WithContext(ctx)
+
+			g.Go(func() error {
+				logger.Info().Msg("starting relayer...")
+				return gravityRelayer.Start(errCtx)
+			})
+
+			// stops everything once the relayer runs out of funds
+			g.Go(func() error {
+				return balanceMonitor.Start(errCtx)
+			})
+
+			startPendingTxBackend(errCtx, g, konfig, gravityContract)
+
+			// listen for and trap any OS signal to gracefully shutdown and exit
+			trapSignal(cancel)
+
+			return g.Wait()
+		},
+	}
+
+	cmd.Flags().String(flagCosmosGRPC, "tcp://localhost:9090", "The gRPC endpoint of a cosmos node")
+	cmd.Flags().String(flagEthWS, "", "Specify the websocket endpoint of an Ethereum node used to subscribe to pending txs (with the subscribe pending tx backend)")
+	cmd.Flags().AddFlagSet(relayerFlagSet())
+	cmd.Flags().AddFlagSet(ethereumKeyOptsFlagSet())
+	cmd.Flags().AddFlagSet(ethereumOptsFlagSet())
+
+	return cmd
+}
+
+// validatePendingTxBackend checks the pending tx backend is known and has the endpoint it needs.
+func validatePendingTxBackend(konfig *koanf.Koanf) error {
+	switch pendingTxBackend := gravity.PendingTxBackend(konfig.String(flagEthPendingTxBackend)); pendingTxBackend {
+	case gravity.PendingTxBackendAlchemy, gravity.PendingTxBackendTxPool:
+	case gravity.PendingTxBackendSubscribe:
+		if konfig.String(flagEthWS) == "" {
+			return fmt.Errorf("the %s pending tx backend requires --%s", pendingTxBackend, flagEthWS)
+		}
+	default:
+		return fmt.Errorf("invalid pending tx backend: %s", pendingTxBackend)
+	}
+
+	return nil
+}
+
+// validateSingleBridge checks the process targets a single Gravity contract through a single Ethereum endpoint. The
+// Gravity module only bridges one EVM chain: its claims carry no chain and its valsets and batches are signed for its
+// only gravity ID, so each chain needs its own module and process.
+func validateSingleBridge(gravityAddr string, konfig *koanf.Koanf) error {
+	if strings.ContainsAny(gravityAddr, ", ") || strings.Contains(konfig.String(flagEthRPC), ",") {
+		return fmt.Errorf("a single bridge can be targeted per process; run one process per Gravity contract")
+	}
+
+	if !ethcmn.IsHexAddress(gravityAddr) {
+		return fmt.Errorf("invalid Gravity contract address: %s", gravityAddr)
+	}
+
+	return nil
+}
+
+// validateBridgeTarget checks the Gravity contract is the one the Gravity module bridges, and returns its address.
+func validateBridgeTarget(gravityAddr string, params *gravitytypes.Params) (ethcmn.Address, error) {
+	addr := ethcmn.HexToAddress(gravityAddr)
+
+	// the module doesn't know the contract until it's set in its params
+	if bridgeAddr := ethcmn.HexToAddress(params.BridgeEthereumAddress); bridgeAddr != (ethcmn.Address{}) && bridgeAddr != addr {
+		return ethcmn.Address{}, fmt.Errorf("the Gravity module bridges the contract at %s, not %s", bridgeAddr, addr)
+	}
+
+	return addr, nil
+}
+
+// newGravityContract connects to the Ethereum node and returns the Gravity contract, sending txs from the given
+// account.
+func newGravityContract(
+	logger zerolog.Logger,
+	konfig *koanf.Koanf,
+	ethChainID uint64,
+	gravityAddr ethcmn.Address,
+	ethKeyFromAddress ethcmn.Address,
+	signerFn bind.SignerFn,
+) (gravity.Contract, error) {
+	ethRPCEndpoint := konfig.String(flagEthRPC)
+	ethRPC, err := ethrpc.Dial(ethRPCEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial Ethereum RPC node: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Connected to Ethereum RPC: %s\n", ethRPCEndpoint)
+	ethProvider := provider.NewEVMProvider(ethRPC)
+
+	var committerOpts []committer.EVMCommitterOption
+	if privateRelay := konfig.String(flagEthPrivateRelay); privateRelay != "" {
+		privateRelayBlocks := konfig.Int64(flagEthPrivateRelayBlocks)
+		if privateRelayBlocks <= 0 {
+			return nil, fmt.Errorf("invalid private relay blocks: %d", privateRelayBlocks)
+		}
+
+		var privateRelayKey *ecdsa.PrivateKey
+		if keyHex := konfig.String(flagEthPrivateRelayKey); keyHex != "" {
+			if privateRelayKey, err = ethcrypto.HexToECDSA(keyHex); err != nil {
+				return nil, fmt.Errorf("failed to hex-decode private relay key: %w", err)
+			}
+		}
+
+		committerOpts = append(committerOpts, committer.OptionPrivateRelay(
+			privateRelay,
+			committer.PrivateRelayMethod(konfig.String(flagEthPrivateRelayMethod)),
+			uint64(privateRelayBlocks),
+			konfig.Duration(flagEthPrivateRelayTimeout),
+			privateRelayKey,
+		))
+	}
+
+	senders, err := initEthereumSenderPool(logger, ethChainID, konfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Ethereum sender pool: %w", err)
+	}
+
+	if len(senders) > 0 {
+		committerOpts = append(committerOpts, committer.OptionSenderPool(senders...))
+	}
+
+	if lowBalance := ethLowBalance(konfig); lowBalance != nil {
+		committerOpts = append(committerOpts, committer.OptionLowBalanceWarning(lowBalance))
+	}
+
+	if reserve := konfig.Float64(flagEthBalanceReserve); reserve > 0 {
+		committerOpts = append(committerOpts, committer.OptionBalanceReserve(ethToWei(reserve)))
+	}
+
+	switch estimator := konfig.String(flagEthGasPriceEstimator); estimator {
+	case gasPriceEstimatorAdjustment:
+	case gasPriceEstimatorFeeHistory:
+		feeHistoryBlocks := konfig.Int64(flagEthFeeHistoryBlocks)
+		if feeHistoryBlocks <= 0 {
+			return nil, fmt.Errorf("invalid fee history blocks: %d", feeHistoryBlocks)
+		}
+
+		feeHistoryEstimator, err := gasprice.NewFeeHistoryEstimator(
+			ethProvider,
+			uint64(feeHistoryBlocks),
+			konfig.Float64(flagEthFeeHistoryPercentile),
+			konfig.Float64(flagEthFeeHistoryUrgent),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		committerOpts = append(committerOpts, committer.OptionGasPriceEstimator(feeHistoryEstimator))
+	default:
+		return nil, fmt.Errorf("invalid gas price estimator: %s", estimator)
+	}
+
+	spendOpts, err := spendLimitOptions(konfig)
+	if err != nil {
+		return nil, err
+	}
+
+	committerOpts = append(committerOpts, spendOpts...)
+
+	ethCommitter, err := committer.NewEthCommitter(
+		logger,
+		ethKeyFromAddress,
+		konfig.Float64(flagEthGasAdjustment),
+		konfig.Float64(flagEthGasLimitAdjustment),
+		signerFn,
+		ethProvider,
+		committerOpts...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Ethereum committer: %w", err)
+	}
+
+	ethGravity, err := wrappers.NewGravity(gravityAddr, ethCommitter.Provider())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a new instance of Gravity: %w", err)
+	}
+
+	gravityContract, err := gravity.NewGravityContract(logger, ethCommitter, gravityAddr, ethGravity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Ethereum committer: %w", err)
+	}
+
+	return gravityContract, nil
+}
+
+// spendLimitOptions returns the committer options bounding the ETH spent on txs.
+func spendLimitOptions(konfig *koanf.Koanf) ([]committer.EVMCommitterOption, error) {
+	var opts []committer.EVMCommitterOption
+
+	for _, flag := range []string{
+		flagEthMaxGasPrice, flagEthMaxBaseFeeMultiplier, flagEthMaxTxCost, flagEthHourlyBudget, flagEthDailyBudget,
+	} {
+		if konfig.Float64(flag) < 0 {
+			return nil, fmt.Errorf("invalid --%s: %f", flag, konfig.Float64(flag))
+		}
+	}
+
+	if maxGasPrice := konfig.Float64(flagEthMaxGasPrice); maxGasPrice > 0 {
+		maxGasPriceWei, _ := new(big.Float).Mul(big.NewFloat(maxGasPrice), big.NewFloat(params.GWei)).Int(nil)
+		opts = append(opts, committer.OptionMaxGasPrice(maxGasPriceWei))
+	}
+
+	if multiplier := konfig.Float64(flagEthMaxBaseFeeMultiplier); multiplier > 0 {
+		opts = append(opts, committer.OptionMaxBaseFeeMultiplier(multiplier))
+	}
+
+	if maxTxCost := konfig.Float64(flagEthMaxTxCost); maxTxCost > 0 {
+		opts = append(opts, committer.OptionMaxTxCost(ethToWei(maxTxCost)))
+	}
+
+	var hourly, daily *big.Int
+	if budget := konfig.Float64(flagEthHourlyBudget); budget > 0 {
+		hourly = ethToWei(budget)
+	}
+
+	if budget := konfig.Float64(flagEthDailyBudget); budget > 0 {
+		daily = ethToWei(budget)
+	}
+
+	if hourly != nil || daily != nil {
+		opts = append(opts, committer.OptionSpendBudget(hourly, daily, konfig.String(flagEthSpendStateFile)))
+	}
+
+	return opts, nil
+}
+
+// ethToWei converts an amount given in ETH by a flag to wei.
+func ethToWei(eth float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(eth), big.NewFloat(params.Ether)).Int(nil)
+	return wei
+}
+
+// newBalanceMonitor returns a balance monitor without any account, they're set once known.
+func newBalanceMonitor(logger zerolog.Logger, konfig *koanf.Koanf) (*balance.Monitor, error) {
+	interval := konfig.Duration(flagBalanceCheckInterval)
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid balance check interval: %s", interval)
+	}
+
+	return balance.NewMonitor(logger, interval), nil
+}
+
+// ethLowBalance returns the ETH balance (in wei) below which an account is reported to be topped up, nil if disabled.
+func ethLowBalance(konfig *koanf.Koanf) *big.Int {
+	if lowBalance := konfig.Float64(flagEthLowBalanceWarning); lowBalance > 0 {
+		return ethToWei(lowBalance)
+	}
+
+	return nil
+}
+
+// newPriceFeed returns the price feed querying the CoinGecko API set by the flags.
+func newPriceFeed(logger zerolog.Logger, konfig *koanf.Koanf) *coingecko.PriceFeed {
+	return coingecko.NewCoingeckoPriceFeed(logger, 100, &coingecko.Config{
+		BaseURL: konfig.String(flagCoinGeckoAPI),
+	})
+}
+
+// newGravityRelayer returns the relayer configured by the relayer flags, stopping when it runs out of funds.
+func newGravityRelayer(
+	logger zerolog.Logger,
+	konfig *koanf.Koanf,
+	gravityQuerier gravitytypes.QueryClient,
+	gravityContract gravity.Contract,
+	averageEthBlockTime time.Duration,
+	insufficientFundsHandler func(error),
+) (relayer.GravityRelayer, error) {
+	// We multiply the relayer loop multiplier by the ETH block time.
+	ethBlockTimeF64 := float64(averageEthBlockTime.Milliseconds())
+	relayerLoopMultiplier := konfig.Float64(flagRelayerLoopMultiplier)
+
+	// Here we cast the float64 to a Duration (int64); as we are dealing with ms, we'll lose as much as 1ms.
+	relayerLoopDuration := time.Duration(ethBlockTimeF64*relayerLoopMultiplier) * time.Millisecond
+
+	batchGasBudget := konfig.Int64(flagRelayerBatchGasBudget)
+	if batchGasBudget < 0 {
+		return nil, fmt.Errorf("invalid batch gas budget: %d", batchGasBudget)
+	}
+
+	batchTimeoutWarning := konfig.Int64(flagBatchTimeoutWarning)
+	if batchTimeoutWarning < 0 {
+		return nil, fmt.Errorf("invalid batch timeout warning: %d", batchTimeoutWarning)
+	}
+
+	batchUrgencyBlocks := konfig.Int64(flagBatchUrgencyBlocks)
+	if batchUrgencyBlocks < 0 {
+		return nil, fmt.Errorf("invalid batch urgency blocks: %d", batchUrgencyBlocks)
+	}
+
+	return relayer.NewGravityRelayer(
+		logger,
+		gravityQuerier,
+		gravityContract,
+		konfig.Bool(flagRelayValsets),
+		konfig.Bool(flagRelayBatches),
+		relayerLoopDuration,
+		konfig.Duration(flagEthPendingTXWait),
+		konfig.Float64(flagProfitMultiplier),
+		relayer.SetPriceFeeder(newPriceFeed(logger, konfig)),
+		relayer.SetBatchGasBudget(uint64(batchGasBudget)),
+		relayer.SetBatchTimeoutWarning(uint64(batchTimeoutWarning)),
+		relayer.SetBatchUrgency(uint64(batchUrgencyBlocks), konfig.Float64(flagUrgentProfitMultiplier)),
+		relayer.SetInsufficientFundsHandler(insufficientFundsHandler),
+	), nil
+}
+
+// startPendingTxBackend listens for pending txs against the Gravity Bridge contract in g, so we don't relay what's
+// already been sent.
+func startPendingTxBackend(ctx context.Context, g *errgroup.Group, konfig *koanf.Koanf, gravityContract gravity.Contract) {
+	switch gravity.PendingTxBackend(konfig.String(flagEthPendingTxBackend)) {
+	case gravity.PendingTxBackendAlchemy:
+		if alchemyWS := konfig.String(flagEthAlchemyWS); alchemyWS != "" {
+			g.Go(func() error {
+				return gravityContract.SubscribeToPendingTxs(ctx, alchemyWS)
+			})
+		}
+
+	case gravity.PendingTxBackendSubscribe:
+		g.Go(func() error {
+			return gravityContract.SubscribeToNewPendingTxs(ctx, konfig.String(flagEthWS))
+		})
+
+	case gravity.PendingTxBackendTxPool:
+		g.Go(func() error {
+			return gravityContract.PollTxPool(ctx, konfig.String(flagEthRPC), konfig.Duration(flagEthTxPoolPollInterval))
+		})
+	}
+}
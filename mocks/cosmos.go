@@ -10,6 +10,7 @@ import (
 	client "github.com/cosmos/cosmos-sdk/client"
 	types "github.com/cosmos/cosmos-sdk/types"
 	gomock "github.com/golang/mock/gomock"
+	client0 "github.com/umee-network/peggo/cmd/peggo/client"
 	grpc "google.golang.org/grpc"
 )
 
@@ -109,6 +110,20 @@ func (mr *MockCosmosClientMockRecorder) FromAddress() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FromAddress", reflect.TypeOf((*MockCosmosClient)(nil).FromAddress))
 }
 
+// GasUsage mocks base method.
+func (m *MockCosmosClient) GasUsage() []client0.MsgGasUsage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasUsage")
+	ret0, _ := ret[0].([]client0.MsgGasUsage)
+	return ret0
+}
+
+// GasUsage indicates an expected call of GasUsage.
+func (mr *MockCosmosClientMockRecorder) GasUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasUsage", reflect.TypeOf((*MockCosmosClient)(nil).GasUsage))
+}
+
 // QueryClient mocks base method.
 func (m *MockCosmosClient) QueryClient() *grpc.ClientConn {
 	m.ctrl.T.Helper()
@@ -124,15 +139,16 @@ func (mr *MockCosmosClientMockRecorder) QueryClient() *gomock.Call {
 }
 
 // QueueBroadcastMsg mocks base method.
-func (m *MockCosmosClient) QueueBroadcastMsg(arg0 ...types.Msg) error {
+func (m *MockCosmosClient) QueueBroadcastMsg(arg0 ...types.Msg) ([]*client0.TxFuture, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{}
 	for _, a := range arg0 {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "QueueBroadcastMsg", varargs...)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]*client0.TxFuture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // QueueBroadcastMsg indicates an expected call of QueueBroadcastMsg.
@@ -13,6 +13,7 @@ import (
 	common "github.com/ethereum/go-ethereum/common"
 	types "github.com/ethereum/go-ethereum/core/types"
 	gomock "github.com/golang/mock/gomock"
+	provider "github.com/umee-network/peggo/orchestrator/ethereum/provider"
 )
 
 // MockEVMProviderWithRet is a mock of EVMProviderWithRet interface.
@@ -38,6 +39,21 @@ func (m *MockEVMProviderWithRet) EXPECT() *MockEVMProviderWithRetMockRecorder {
 	return m.recorder
 }
 
+// BalanceAt mocks base method.
+func (m *MockEVMProviderWithRet) BalanceAt(arg0 context.Context, arg1 common.Address, arg2 *big.Int) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceAt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAt indicates an expected call of BalanceAt.
+func (mr *MockEVMProviderWithRetMockRecorder) BalanceAt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAt", reflect.TypeOf((*MockEVMProviderWithRet)(nil).BalanceAt), arg0, arg1, arg2)
+}
+
 // CallContract mocks base method.
 func (m *MockEVMProviderWithRet) CallContract(arg0 context.Context, arg1 ethereum.CallMsg, arg2 *big.Int) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -83,6 +99,21 @@ func (mr *MockEVMProviderWithRetMockRecorder) EstimateGas(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateGas", reflect.TypeOf((*MockEVMProviderWithRet)(nil).EstimateGas), arg0, arg1)
 }
 
+// FeeHistory mocks base method.
+func (m *MockEVMProviderWithRet) FeeHistory(arg0 context.Context, arg1 uint64, arg2 *big.Int, arg3 []float64) (*provider.FeeHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FeeHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*provider.FeeHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FeeHistory indicates an expected call of FeeHistory.
+func (mr *MockEVMProviderWithRetMockRecorder) FeeHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeeHistory", reflect.TypeOf((*MockEVMProviderWithRet)(nil).FeeHistory), arg0, arg1, arg2, arg3)
+}
+
 // FilterLogs mocks base method.
 func (m *MockEVMProviderWithRet) FilterLogs(arg0 context.Context, arg1 ethereum.FilterQuery) ([]types.Log, error) {
 	m.ctrl.T.Helper()
@@ -113,6 +144,36 @@ func (mr *MockEVMProviderWithRetMockRecorder) HeaderByNumber(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeaderByNumber", reflect.TypeOf((*MockEVMProviderWithRet)(nil).HeaderByNumber), arg0, arg1)
 }
 
+// NonceAt mocks base method.
+func (m *MockEVMProviderWithRet) NonceAt(arg0 context.Context, arg1 common.Address, arg2 *big.Int) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NonceAt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NonceAt indicates an expected call of NonceAt.
+func (mr *MockEVMProviderWithRetMockRecorder) NonceAt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NonceAt", reflect.TypeOf((*MockEVMProviderWithRet)(nil).NonceAt), arg0, arg1, arg2)
+}
+
+// PendingCallContract mocks base method.
+func (m *MockEVMProviderWithRet) PendingCallContract(arg0 context.Context, arg1 ethereum.CallMsg) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingCallContract", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingCallContract indicates an expected call of PendingCallContract.
+func (mr *MockEVMProviderWithRetMockRecorder) PendingCallContract(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingCallContract", reflect.TypeOf((*MockEVMProviderWithRet)(nil).PendingCallContract), arg0, arg1)
+}
+
 // PendingCodeAt mocks base method.
 func (m *MockEVMProviderWithRet) PendingCodeAt(arg0 context.Context, arg1 common.Address) ([]byte, error) {
 	m.ctrl.T.Helper()
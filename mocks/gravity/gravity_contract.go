@@ -13,6 +13,7 @@ import (
 	types "github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
 	common "github.com/ethereum/go-ethereum/common"
 	gomock "github.com/golang/mock/gomock"
+	gasprice "github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
 	gravity "github.com/umee-network/peggo/orchestrator/ethereum/gravity"
 	provider "github.com/umee-network/peggo/orchestrator/ethereum/provider"
 )
@@ -85,9 +86,9 @@ func (mr *MockContractMockRecorder) EncodeValsetUpdate(arg0, arg1, arg2, arg3 in
 }
 
 // EstimateGas mocks base method.
-func (m *MockContract) EstimateGas(arg0 context.Context, arg1 common.Address, arg2 []byte) (uint64, *big.Int, error) {
+func (m *MockContract) EstimateGas(arg0 context.Context, arg1 common.Address, arg2 []byte, arg3 gasprice.Urgency) (uint64, *big.Int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EstimateGas", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "EstimateGas", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(*big.Int)
 	ret2, _ := ret[2].(error)
@@ -95,9 +96,23 @@ func (m *MockContract) EstimateGas(arg0 context.Context, arg1 common.Address, ar
 }
 
 // EstimateGas indicates an expected call of EstimateGas.
-func (mr *MockContractMockRecorder) EstimateGas(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockContractMockRecorder) EstimateGas(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateGas", reflect.TypeOf((*MockContract)(nil).EstimateGas), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateGas", reflect.TypeOf((*MockContract)(nil).EstimateGas), arg0, arg1, arg2, arg3)
+}
+
+// EvictPendingTxs mocks base method.
+func (m *MockContract) EvictPendingTxs(arg0 context.Context, arg1 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvictPendingTxs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EvictPendingTxs indicates an expected call of EvictPendingTxs.
+func (mr *MockContractMockRecorder) EvictPendingTxs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictPendingTxs", reflect.TypeOf((*MockContract)(nil).EvictPendingTxs), arg0, arg1)
 }
 
 // FromAddress mocks base method.
@@ -114,6 +129,21 @@ func (mr *MockContractMockRecorder) FromAddress() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FromAddress", reflect.TypeOf((*MockContract)(nil).FromAddress))
 }
 
+// GasPrice mocks base method.
+func (m *MockContract) GasPrice(arg0 context.Context, arg1 gasprice.Urgency) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasPrice", arg0, arg1)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GasPrice indicates an expected call of GasPrice.
+func (mr *MockContractMockRecorder) GasPrice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasPrice", reflect.TypeOf((*MockContract)(nil).GasPrice), arg0, arg1)
+}
+
 // GetERC20Decimals mocks base method.
 func (m *MockContract) GetERC20Decimals(arg0 context.Context, arg1, arg2 common.Address) (byte, error) {
 	m.ctrl.T.Helper()
@@ -188,6 +218,21 @@ func (mr *MockContractMockRecorder) GetTxBatchNonce(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxBatchNonce", reflect.TypeOf((*MockContract)(nil).GetTxBatchNonce), arg0, arg1, arg2)
 }
 
+// GetValsetCheckpoint mocks base method.
+func (m *MockContract) GetValsetCheckpoint(arg0 context.Context, arg1 common.Address, arg2 *big.Int) (common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValsetCheckpoint", arg0, arg1, arg2)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValsetCheckpoint indicates an expected call of GetValsetCheckpoint.
+func (mr *MockContractMockRecorder) GetValsetCheckpoint(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValsetCheckpoint", reflect.TypeOf((*MockContract)(nil).GetValsetCheckpoint), arg0, arg1, arg2)
+}
+
 // GetValsetNonce mocks base method.
 func (m *MockContract) GetValsetNonce(arg0 context.Context, arg1 common.Address) (*big.Int, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +262,20 @@ func (mr *MockContractMockRecorder) IsPendingTxInput(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPendingTxInput", reflect.TypeOf((*MockContract)(nil).IsPendingTxInput), arg0, arg1)
 }
 
+// PollTxPool mocks base method.
+func (m *MockContract) PollTxPool(arg0 context.Context, arg1 string, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollTxPool", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PollTxPool indicates an expected call of PollTxPool.
+func (mr *MockContractMockRecorder) PollTxPool(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollTxPool", reflect.TypeOf((*MockContract)(nil).PollTxPool), arg0, arg1, arg2)
+}
+
 // Provider mocks base method.
 func (m *MockContract) Provider() provider.EVMProvider {
 	m.ctrl.T.Helper()
@@ -246,6 +305,48 @@ func (mr *MockContractMockRecorder) SendTx(arg0, arg1, arg2, arg3, arg4 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTx", reflect.TypeOf((*MockContract)(nil).SendTx), arg0, arg1, arg2, arg3, arg4)
 }
 
+// SenderAddresses mocks base method.
+func (m *MockContract) SenderAddresses() []common.Address {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SenderAddresses")
+	ret0, _ := ret[0].([]common.Address)
+	return ret0
+}
+
+// SenderAddresses indicates an expected call of SenderAddresses.
+func (mr *MockContractMockRecorder) SenderAddresses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SenderAddresses", reflect.TypeOf((*MockContract)(nil).SenderAddresses))
+}
+
+// SimulateTx mocks base method.
+func (m *MockContract) SimulateTx(arg0 context.Context, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SimulateTx indicates an expected call of SimulateTx.
+func (mr *MockContractMockRecorder) SimulateTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateTx", reflect.TypeOf((*MockContract)(nil).SimulateTx), arg0, arg1)
+}
+
+// SubscribeToNewPendingTxs mocks base method.
+func (m *MockContract) SubscribeToNewPendingTxs(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeToNewPendingTxs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeToNewPendingTxs indicates an expected call of SubscribeToNewPendingTxs.
+func (mr *MockContractMockRecorder) SubscribeToNewPendingTxs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeToNewPendingTxs", reflect.TypeOf((*MockContract)(nil).SubscribeToNewPendingTxs), arg0, arg1)
+}
+
 // SubscribeToPendingTxs mocks base method.
 func (m *MockContract) SubscribeToPendingTxs(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,401 @@
+package balance
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/umee-network/peggo/orchestrator/loops"
+)
+
+// ErrInsufficientFunds is returned by Start once an account was reported to be out of funds.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// allowanceExpiryWarning is how long before its expiration a fee allowance is reported to be renewed.
+const allowanceExpiryWarning = 24 * time.Hour
+
+// EthBalanceReader returns the ETH balance of an account, e.g. an Ethereum provider.
+type EthBalanceReader interface {
+	BalanceAt(ctx context.Context, account ethcmn.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// Balances is a snapshot of the balances of the monitored accounts.
+type Balances struct {
+	// Eth holds the ETH balances in wei, by account.
+	Eth map[ethcmn.Address]*big.Int
+	// Cosmos holds the Cosmos balances, by bech32 account address.
+	Cosmos map[string]sdk.Coins
+	// FeeAllowance is the fee allowance the Cosmos fees are paid with, nil if there's none or it wasn't checked yet.
+	FeeAllowance *FeeAllowance
+	// UpdatedAt is when the balances were last checked, zero if they never were.
+	UpdatedAt time.Time
+}
+
+// FeeAllowance is what's left of a feegrant allowance.
+type FeeAllowance struct {
+	// SpendLimit is what can still be spent, in the current period for a periodic allowance. It's nil if the
+	// allowance has no limit, and empty once it's spent.
+	SpendLimit sdk.Coins
+	// Expiration is when the allowance expires, nil if it doesn't.
+	Expiration *time.Time
+}
+
+// Monitor periodically checks the balances of the accounts paying for the txs on both chains, warning when they're
+// below their thresholds so they can be topped up before running out. Once an account is reported to be out of funds,
+// Start returns so the process stops instead of failing the same txs over and over.
+type Monitor struct {
+	logger   zerolog.Logger
+	interval time.Duration
+
+	ethBalances   EthBalanceReader
+	ethAccounts   []ethcmn.Address
+	ethMinBalance *big.Int
+
+	bankQuerier      banktypes.QueryClient
+	cosmosAccounts   []sdk.AccAddress
+	cosmosMinBalance sdk.Coins
+
+	feegrantQuerier   feegrant.QueryClient
+	feeGranter        sdk.AccAddress
+	feeGrantee        sdk.AccAddress
+	interfaceRegistry codectypes.InterfaceRegistry
+
+	mtx      sync.RWMutex
+	balances Balances
+	fundsErr error
+	wakeUp   chan struct{}
+}
+
+type MonitorOption func(*Monitor)
+
+func SetEthAccounts(ethBalances EthBalanceReader, minBalance *big.Int, accounts ...ethcmn.Address) MonitorOption {
+	return func(m *Monitor) { m.SetEthAccounts(ethBalances, minBalance, accounts...) }
+}
+
+// SetEthAccounts monitors the ETH balances of accounts, warning when they're below minBalance (in wei). A nil
+// minBalance disables the warning. It must be called before Start.
+func (m *Monitor) SetEthAccounts(ethBalances EthBalanceReader, minBalance *big.Int, accounts ...ethcmn.Address) {
+	m.ethBalances = ethBalances
+	m.ethMinBalance = minBalance
+	m.ethAccounts = accounts
+}
+
+func SetCosmosAccounts(
+	bankQuerier banktypes.QueryClient,
+	minBalance sdk.Coins,
+	accounts ...sdk.AccAddress,
+) MonitorOption {
+	return func(m *Monitor) { m.SetCosmosAccounts(bankQuerier, minBalance, accounts...) }
+}
+
+// SetCosmosAccounts monitors the Cosmos balances of accounts, warning when they hold less than any of the minBalance
+// coins. An empty minBalance disables the warning. It must be called before Start.
+func (m *Monitor) SetCosmosAccounts(
+	bankQuerier banktypes.QueryClient,
+	minBalance sdk.Coins,
+	accounts ...sdk.AccAddress,
+) {
+	m.bankQuerier = bankQuerier
+	m.cosmosMinBalance = minBalance
+	m.cosmosAccounts = accounts
+}
+
+func SetFeeAllowance(feegrantQuerier feegrant.QueryClient, granter, grantee sdk.AccAddress) MonitorOption {
+	return func(m *Monitor) { m.SetFeeAllowance(feegrantQuerier, granter, grantee) }
+}
+
+// SetFeeAllowance monitors the fee allowance granted by granter to grantee, warning when what can still be spent is
+// below the Cosmos min balance or when it's about to expire. It must be called before Start.
+func (m *Monitor) SetFeeAllowance(feegrantQuerier feegrant.QueryClient, granter, grantee sdk.AccAddress) {
+	m.feegrantQuerier = feegrantQuerier
+	m.feeGranter = granter
+	m.feeGrantee = grantee
+
+	// the allowances come packed in Any, they're unpacked with the feegrant types
+	m.interfaceRegistry = codectypes.NewInterfaceRegistry()
+	feegrant.RegisterInterfaces(m.interfaceRegistry)
+}
+
+func NewMonitor(logger zerolog.Logger, interval time.Duration, options ...MonitorOption) *Monitor {
+	m := &Monitor{
+		logger:   logger.With().Str("module", "balance_monitor").Logger(),
+		interval: interval,
+		wakeUp:   make(chan struct{}, 1),
+		balances: Balances{
+			Eth:    map[ethcmn.Address]*big.Int{},
+			Cosmos: map[string]sdk.Coins{},
+		},
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+// Start checks the balances every interval until ctx is done or an account is reported to be out of funds, in which
+// case it returns ErrInsufficientFunds.
+func (m *Monitor) Start(ctx context.Context) error {
+	return loops.RunLoopWithWakeUp(ctx, m.logger, m.interval, m.wakeUp, func() error {
+		m.mtx.RLock()
+		fundsErr := m.fundsErr
+		m.mtx.RUnlock()
+
+		if fundsErr != nil {
+			m.logger.Error().Err(fundsErr).Msg("out of funds; stopping, top up the account and restart")
+			return errors.Wrap(ErrInsufficientFunds, fundsErr.Error())
+		}
+
+		m.check(ctx)
+		return nil
+	})
+}
+
+// ReportInsufficientFunds makes Start return, err being the failure caused by the lack of funds. Only the first report
+// is kept.
+func (m *Monitor) ReportInsufficientFunds(err error) {
+	m.mtx.Lock()
+	if m.fundsErr == nil {
+		m.fundsErr = err
+	}
+	m.mtx.Unlock()
+
+	select {
+	case m.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+// Balances returns a copy of the balances found by the last check.
+func (m *Monitor) Balances() Balances {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	balances := Balances{
+		Eth:       make(map[ethcmn.Address]*big.Int, len(m.balances.Eth)),
+		Cosmos:    make(map[string]sdk.Coins, len(m.balances.Cosmos)),
+		UpdatedAt: m.balances.UpdatedAt,
+	}
+
+	for account, balance := range m.balances.Eth {
+		balances.Eth[account] = new(big.Int).Set(balance)
+	}
+
+	for account, balance := range m.balances.Cosmos {
+		balances.Cosmos[account] = balance
+	}
+
+	if m.balances.FeeAllowance != nil {
+		allowance := *m.balances.FeeAllowance
+		balances.FeeAllowance = &allowance
+	}
+
+	return balances
+}
+
+// check updates the balances of all the accounts. An account whose balance can't be queried keeps its last known one.
+func (m *Monitor) check(ctx context.Context) {
+	for _, account := range m.ethAccounts {
+		balance, err := m.ethBalances.BalanceAt(ctx, account, nil)
+		if err != nil {
+			m.logger.Err(err).Str("account", account.Hex()).Msg("failed to get ETH balance")
+			continue
+		}
+
+		m.mtx.Lock()
+		m.balances.Eth[account] = balance
+		m.mtx.Unlock()
+
+		logger := m.logger.With().Str("account", account.Hex()).Str("balance", balance.String()).Logger()
+		if m.ethMinBalance != nil && balance.Cmp(m.ethMinBalance) < 0 {
+			logger.Warn().Str("min_balance", m.ethMinBalance.String()).Msg("ETH balance is low; top it up")
+		} else {
+			logger.Debug().Msg("ETH balance")
+		}
+	}
+
+	for _, account := range m.cosmosAccounts {
+		res, err := m.bankQuerier.AllBalances(ctx, &banktypes.QueryAllBalancesRequest{Address: account.String()})
+		if err != nil {
+			m.logger.Err(err).Str("account", account.String()).Msg("failed to get Cosmos balance")
+			continue
+		}
+
+		m.mtx.Lock()
+		m.balances.Cosmos[account.String()] = res.Balances
+		m.mtx.Unlock()
+
+		logger := m.logger.With().Str("account", account.String()).Str("balance", res.Balances.String()).Logger()
+		if !m.cosmosMinBalance.Empty() && !res.Balances.IsAllGTE(m.cosmosMinBalance) {
+			logger.Warn().Str("min_balance", m.cosmosMinBalance.String()).Msg("Cosmos balance is low; top it up")
+		} else {
+			logger.Debug().Msg("Cosmos balance")
+		}
+	}
+
+	if m.feegrantQuerier != nil {
+		m.checkFeeAllowance(ctx)
+	}
+
+	m.mtx.Lock()
+	m.balances.UpdatedAt = time.Now()
+	m.mtx.Unlock()
+
+	m.logBalances()
+}
+
+// checkFeeAllowance updates the fee allowance, warning when it's low or about to expire.
+func (m *Monitor) checkFeeAllowance(ctx context.Context) {
+	logger := m.logger.With().
+		Str("granter", m.feeGranter.String()).
+		Str("grantee", m.feeGrantee.String()).
+		Logger()
+
+	res, err := m.feegrantQuerier.Allowance(ctx, &feegrant.QueryAllowanceRequest{
+		Granter: m.feeGranter.String(),
+		Grantee: m.feeGrantee.String(),
+	})
+	if err != nil {
+		logger.Err(err).Msg("failed to get fee allowance")
+		return
+	}
+
+	if res.Allowance == nil {
+		logger.Warn().Msg("no fee allowance; the Cosmos fees can't be paid by the granter")
+		return
+	}
+
+	if err := res.Allowance.UnpackInterfaces(m.interfaceRegistry); err != nil {
+		logger.Err(err).Msg("failed to decode fee allowance")
+		return
+	}
+
+	grant, err := res.Allowance.GetGrant()
+	if err != nil {
+		logger.Err(err).Msg("failed to decode fee allowance")
+		return
+	}
+
+	allowance, err := remainingAllowance(grant, time.Now())
+	if err != nil {
+		logger.Err(err).Msg("failed to decode fee allowance")
+		return
+	}
+
+	m.mtx.Lock()
+	m.balances.FeeAllowance = &allowance
+	m.mtx.Unlock()
+
+	if allowance.SpendLimit != nil {
+		logger = logger.With().Str("spend_limit", allowance.SpendLimit.String()).Logger()
+	}
+
+	if allowance.Expiration != nil {
+		logger = logger.With().Time("expiration", *allowance.Expiration).Logger()
+	}
+
+	switch {
+	case allowance.SpendLimit != nil && !m.cosmosMinBalance.Empty() &&
+		!allowance.SpendLimit.IsAllGTE(m.cosmosMinBalance):
+		logger.Warn().Str("min_balance", m.cosmosMinBalance.String()).Msg("fee allowance is low; top it up")
+
+	case allowance.Expiration != nil && time.Until(*allowance.Expiration) < allowanceExpiryWarning:
+		logger.Warn().Msg("fee allowance is about to expire; renew it")
+
+	default:
+		logger.Debug().Msg("fee allowance")
+	}
+}
+
+// remainingAllowance returns what's left of a fee allowance at now.
+func remainingAllowance(grant feegrant.FeeAllowanceI, now time.Time) (FeeAllowance, error) {
+	switch a := grant.(type) {
+	case *feegrant.BasicAllowance:
+		return FeeAllowance{SpendLimit: nilIfEmpty(a.SpendLimit), Expiration: a.Expiration}, nil
+
+	case *feegrant.PeriodicAllowance:
+		if a.PeriodSpendLimit.Empty() {
+			return FeeAllowance{SpendLimit: nilIfEmpty(a.Basic.SpendLimit), Expiration: a.Basic.Expiration}, nil
+		}
+
+		// the period limit is restored once the period is over
+		canSpend := a.PeriodCanSpend
+		if !now.Before(a.PeriodReset) {
+			canSpend = a.PeriodSpendLimit
+		}
+
+		// the period can't spend more than the overall limit, and an empty limit is a spent one
+		spendLimit := sdk.Coins{}
+		for _, coin := range canSpend {
+			if !a.Basic.SpendLimit.Empty() {
+				coin.Amount = sdk.MinInt(coin.Amount, a.Basic.SpendLimit.AmountOf(coin.Denom))
+			}
+
+			if coin.IsPositive() {
+				spendLimit = append(spendLimit, coin)
+			}
+		}
+
+		return FeeAllowance{SpendLimit: spendLimit, Expiration: a.Basic.Expiration}, nil
+
+	case *feegrant.AllowedMsgAllowance:
+		inner, err := a.GetAllowance()
+		if err != nil {
+			return FeeAllowance{}, err
+		}
+
+		return remainingAllowance(inner, now)
+
+	default:
+		return FeeAllowance{}, errors.Errorf("unknown fee allowance type %T", grant)
+	}
+}
+
+func nilIfEmpty(coins sdk.Coins) sdk.Coins {
+	if coins.Empty() {
+		return nil
+	}
+
+	return coins
+}
+
+// logBalances reports the balances found by the last check, so they can be followed without debug logs.
+func (m *Monitor) logBalances() {
+	balances := m.Balances()
+
+	eth := zerolog.Dict()
+	for account, balance := range balances.Eth {
+		eth.Str(account.Hex(), balance.String())
+	}
+
+	cosmos := zerolog.Dict()
+	for account, balance := range balances.Cosmos {
+		cosmos.Str(account, balance.String())
+	}
+
+	event := m.logger.Info().Dict("eth", eth).Dict("cosmos", cosmos)
+
+	if balances.FeeAllowance != nil {
+		allowance := zerolog.Dict()
+		if balances.FeeAllowance.SpendLimit != nil {
+			allowance.Str("spend_limit", balances.FeeAllowance.SpendLimit.String())
+		}
+		if balances.FeeAllowance.Expiration != nil {
+			allowance.Time("expiration", *balances.FeeAllowance.Expiration)
+		}
+
+		event = event.Dict("fee_allowance", allowance)
+	}
+
+	event.Msg("balances")
+}
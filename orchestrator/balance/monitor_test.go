@@ -0,0 +1,185 @@
+package balance
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type fakeEthBalances map[ethcmn.Address]*big.Int
+
+func (f fakeEthBalances) BalanceAt(_ context.Context, account ethcmn.Address, _ *big.Int) (*big.Int, error) {
+	balance, ok := f[account]
+	if !ok {
+		return nil, errors.New("unknown account")
+	}
+
+	return balance, nil
+}
+
+type fakeBankQuerier struct {
+	banktypes.QueryClient
+
+	balances map[string]sdk.Coins
+}
+
+func (f fakeBankQuerier) AllBalances(
+	_ context.Context,
+	req *banktypes.QueryAllBalancesRequest,
+	_ ...grpc.CallOption,
+) (*banktypes.QueryAllBalancesResponse, error) {
+	return &banktypes.QueryAllBalancesResponse{Balances: f.balances[req.Address]}, nil
+}
+
+type fakeFeegrantQuerier struct {
+	feegrant.QueryClient
+
+	t         *testing.T
+	allowance feegrant.FeeAllowanceI
+}
+
+func (f fakeFeegrantQuerier) Allowance(
+	_ context.Context,
+	req *feegrant.QueryAllowanceRequest,
+	_ ...grpc.CallOption,
+) (*feegrant.QueryAllowanceResponse, error) {
+	granter, err := sdk.AccAddressFromBech32(req.Granter)
+	require.NoError(f.t, err)
+	grantee, err := sdk.AccAddressFromBech32(req.Grantee)
+	require.NoError(f.t, err)
+
+	grant, err := feegrant.NewGrant(granter, grantee, f.allowance)
+	require.NoError(f.t, err)
+
+	// drop the cached value so that the allowance comes packed, as it does over gRPC
+	grant.Allowance = &codectypes.Any{TypeUrl: grant.Allowance.TypeUrl, Value: grant.Allowance.Value}
+
+	return &feegrant.QueryAllowanceResponse{Allowance: &grant}, nil
+}
+
+func TestMonitorBalances(t *testing.T) {
+	var (
+		ethAccount    = ethcmn.HexToAddress("0x0a")
+		unknown       = ethcmn.HexToAddress("0x0b")
+		cosmosAccount = sdk.AccAddress("cosmos_account______")
+		coins         = sdk.NewCoins(sdk.NewInt64Coin("uumee", 100))
+	)
+
+	m := NewMonitor(
+		zerolog.Nop(),
+		time.Minute,
+		SetEthAccounts(fakeEthBalances{ethAccount: big.NewInt(10)}, big.NewInt(20), ethAccount, unknown),
+		SetCosmosAccounts(
+			fakeBankQuerier{balances: map[string]sdk.Coins{cosmosAccount.String(): coins}},
+			sdk.NewCoins(sdk.NewInt64Coin("uumee", 50)),
+			cosmosAccount,
+		),
+	)
+
+	assert.True(t, m.Balances().UpdatedAt.IsZero())
+
+	m.check(context.Background())
+
+	balances := m.Balances()
+	assert.False(t, balances.UpdatedAt.IsZero())
+	assert.Equal(t, map[ethcmn.Address]*big.Int{ethAccount: big.NewInt(10)}, balances.Eth)
+	assert.Equal(t, map[string]sdk.Coins{cosmosAccount.String(): coins}, balances.Cosmos)
+
+	// the snapshot is a copy
+	balances.Eth[ethAccount].SetInt64(0)
+	assert.Equal(t, big.NewInt(10), m.Balances().Eth[ethAccount])
+}
+
+func TestMonitorInsufficientFunds(t *testing.T) {
+	m := NewMonitor(zerolog.Nop(), time.Hour)
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- m.Start(context.Background())
+	}()
+
+	m.ReportInsufficientFunds(errors.New("out of gas money"))
+	m.ReportInsufficientFunds(errors.New("ignored"))
+
+	select {
+	case err := <-errC:
+		require.ErrorIs(t, err, ErrInsufficientFunds)
+		assert.Contains(t, err.Error(), "out of gas money")
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitor didn't stop")
+	}
+}
+
+func TestMonitorFeeAllowance(t *testing.T) {
+	var (
+		granter    = sdk.AccAddress("granter_____________")
+		grantee    = sdk.AccAddress("grantee_____________")
+		expiration = time.Now().Add(time.Hour)
+	)
+
+	periodic, err := feegrant.NewAllowedMsgAllowance(&feegrant.PeriodicAllowance{
+		Basic:            feegrant.BasicAllowance{Expiration: &expiration},
+		Period:           time.Hour,
+		PeriodSpendLimit: sdk.NewCoins(sdk.NewInt64Coin("uumee", 100)),
+		PeriodCanSpend:   sdk.NewCoins(sdk.NewInt64Coin("uumee", 30)),
+		PeriodReset:      expiration,
+	}, []string{"/gravity.v1.MsgSendToCosmosClaim"})
+	require.NoError(t, err)
+
+	m := NewMonitor(
+		zerolog.Nop(),
+		time.Minute,
+		SetCosmosAccounts(fakeBankQuerier{}, sdk.NewCoins(sdk.NewInt64Coin("uumee", 50)), granter),
+		SetFeeAllowance(fakeFeegrantQuerier{t: t, allowance: periodic}, granter, grantee),
+	)
+
+	assert.Nil(t, m.Balances().FeeAllowance)
+
+	m.check(context.Background())
+
+	allowance := m.Balances().FeeAllowance
+	require.NotNil(t, allowance)
+	assert.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uumee", 30)), allowance.SpendLimit)
+	assert.True(t, expiration.Equal(*allowance.Expiration))
+}
+
+func TestRemainingAllowance(t *testing.T) {
+	now := time.Now()
+	limit := sdk.NewCoins(sdk.NewInt64Coin("uumee", 80))
+
+	allowance, err := remainingAllowance(&feegrant.BasicAllowance{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, FeeAllowance{}, allowance)
+
+	allowance, err = remainingAllowance(&feegrant.BasicAllowance{SpendLimit: limit, Expiration: &now}, now)
+	require.NoError(t, err)
+	assert.Equal(t, FeeAllowance{SpendLimit: limit, Expiration: &now}, allowance)
+
+	periodic := &feegrant.PeriodicAllowance{
+		Basic:            feegrant.BasicAllowance{SpendLimit: limit},
+		PeriodSpendLimit: sdk.NewCoins(sdk.NewInt64Coin("uumee", 100)),
+		PeriodReset:      now.Add(time.Minute),
+	}
+
+	// the period's limit is spent
+	allowance, err = remainingAllowance(periodic, now)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.Coins{}, allowance.SpendLimit)
+
+	// once the period is over, its limit is back, but capped by the overall one
+	allowance, err = remainingAllowance(periodic, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, limit, allowance.SpendLimit)
+}
@@ -0,0 +1,163 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+// BatchRequestPolicy decides which of the tokens with unbatched transfers the requester requests a batch for, so
+// there aren't many small batches no relayer finds worth relaying. The zero value requests a batch for every token.
+type BatchRequestPolicy struct {
+	// MinFeeUSD is the value in USD the unbatched fees of a token must reach, zero disables it. It needs the price
+	// feeder of the orchestrator and only applies to the denoms without MinFees.
+	MinFeeUSD float64
+	// MinFees is the amount of unbatched fees the tokens must reach, per denom, e.g. for the tokens without a price.
+	// It replaces MinFeeUSD for its denoms.
+	MinFees sdk.Coins
+	// MaxWait is how long the fees of a token can wait before a batch is requested whatever their value, zero disables
+	// it.
+	MaxWait time.Duration
+	// AllowedDenoms are the only denoms batches are requested for, when not empty.
+	AllowedDenoms []string
+	// DeniedDenoms are denoms batches are never requested for.
+	DeniedDenoms []string
+}
+
+// allows returns true if the allow and deny lists let batches of denom be requested.
+func (p BatchRequestPolicy) allows(denom string) bool {
+	for _, d := range p.DeniedDenoms {
+		if d == denom {
+			return false
+		}
+	}
+
+	if len(p.AllowedDenoms) == 0 {
+		return true
+	}
+
+	for _, d := range p.AllowedDenoms {
+		if d == denom {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shouldRequestBatch applies the batch request policy to the unbatched fees of a token. The fees are tracked from the
+// first time they're seen, until a batch is requested for them.
+func (p *gravityOrchestrator) shouldRequestBatch(
+	ctx context.Context,
+	logger zerolog.Logger,
+	fees types.BatchFees,
+	denom string,
+) bool {
+	policy := p.batchRequestPolicy
+
+	if !policy.allows(denom) {
+		logger.Debug().Str("denom", denom).Msg("batches of denom aren't requested; skipping it")
+		return false
+	}
+
+	p.mtx.Lock()
+	if p.pendingFeesSince == nil {
+		p.pendingFeesSince = map[string]time.Time{}
+	}
+
+	since, ok := p.pendingFeesSince[denom]
+	if !ok {
+		since = time.Now()
+		p.pendingFeesSince[denom] = since
+	}
+	p.mtx.Unlock()
+
+	if policy.MaxWait > 0 && time.Since(since) >= policy.MaxWait {
+		logger.Info().
+			Str("denom", denom).
+			Dur("waited", time.Since(since)).
+			Msg("unbatched fees waited for too long; forcing a batch")
+
+		return true
+	}
+
+	// a per-denom minimum is enough, so the tokens without a price can be batched
+	if minFees := policy.MinFees.AmountOf(denom); minFees.IsPositive() {
+		if fees.TotalFees.LT(minFees) {
+			logger.Debug().
+				Str("denom", denom).
+				Str("total_fees", fees.TotalFees.String()).
+				Str("min_fees", minFees.String()).
+				Msg("not enough unbatched fees; skipping batch request")
+
+			return false
+		}
+
+		return true
+	}
+
+	if policy.MinFeeUSD > 0 && p.priceFeeder != nil {
+		feesUSD, err := p.batchFeesUSD(ctx, fees)
+		if err != nil {
+			// the fees are still batched once they've waited for too long
+			logger.Err(err).Str("denom", denom).Msg("failed to price unbatched fees; skipping batch request")
+			return false
+		}
+
+		if feesUSD.LessThan(decimal.NewFromFloat(policy.MinFeeUSD)) {
+			logger.Debug().
+				Str("denom", denom).
+				Str("total_fees_usd", feesUSD.String()).
+				Float64("min_fee_usd", policy.MinFeeUSD).
+				Msg("not enough unbatched fees; skipping batch request")
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// batchFeesUSD returns the value in USD of the unbatched fees of a token.
+func (p *gravityOrchestrator) batchFeesUSD(ctx context.Context, fees types.BatchFees) (decimal.Decimal, error) {
+	tokenAddr := ethcmn.HexToAddress(fees.Token)
+
+	decimals, err := p.gravityContract.GetERC20Decimals(ctx, tokenAddr, p.ethFrom)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "failed to get token decimals")
+	}
+
+	usdPrice, err := p.priceFeeder.QueryUSDPrice(tokenAddr)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "failed to get token price")
+	}
+
+	return decimal.NewFromBigInt(fees.TotalFees.BigInt(), -int32(decimals)).Mul(decimal.NewFromFloat(usdPrice)), nil
+}
+
+// batchRequested stops tracking the unbatched fees of denom once a batch was requested for them.
+func (p *gravityOrchestrator) batchRequested(denom string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	delete(p.pendingFeesSince, denom)
+}
+
+// forgetBatchedFees stops tracking the fees of the denoms that don't have unbatched fees anymore, e.g. because
+// another validator requested a batch for them.
+func (p *gravityOrchestrator) forgetBatchedFees(pendingDenoms map[string]struct{}) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for denom := range p.pendingFeesSince {
+		if _, ok := pendingDenoms[denom]; !ok {
+			delete(p.pendingFeesSince, denom)
+		}
+	}
+}
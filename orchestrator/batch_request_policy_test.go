@@ -0,0 +1,148 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	gravityMocks "github.com/umee-network/peggo/mocks/gravity"
+	"github.com/umee-network/peggo/orchestrator/coingecko"
+)
+
+func TestBatchRequestPolicyAllows(t *testing.T) {
+	assert.True(t, BatchRequestPolicy{}.allows("uumee"))
+
+	policy := BatchRequestPolicy{DeniedDenoms: []string{"uatom"}}
+	assert.True(t, policy.allows("uumee"))
+	assert.False(t, policy.allows("uatom"))
+
+	policy = BatchRequestPolicy{AllowedDenoms: []string{"uumee", "uatom"}, DeniedDenoms: []string{"uatom"}}
+	assert.True(t, policy.allows("uumee"))
+	assert.False(t, policy.allows("uatom"))
+	assert.False(t, policy.allows("ujuno"))
+}
+
+func TestShouldRequestBatch(t *testing.T) {
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+	token := "0xdac17f958d2ee523a2206206994597c13d831ec7"
+
+	t.Run("min fees", func(t *testing.T) {
+		orch := gravityOrchestrator{
+			batchRequestPolicy: BatchRequestPolicy{
+				MinFees: sdk.NewCoins(sdk.NewInt64Coin("uumee", 1000)),
+			},
+		}
+
+		fees := types.BatchFees{Token: token, TotalFees: sdk.NewInt(999)}
+		assert.False(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uumee"))
+
+		fees.TotalFees = sdk.NewInt(1000)
+		assert.True(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uumee"))
+
+		// there's no minimum for other denoms
+		fees.TotalFees = sdk.NewInt(1)
+		assert.True(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uatom"))
+	})
+
+	t.Run("max wait", func(t *testing.T) {
+		orch := gravityOrchestrator{
+			batchRequestPolicy: BatchRequestPolicy{
+				MinFees: sdk.NewCoins(sdk.NewInt64Coin("uumee", 1000)),
+				MaxWait: time.Hour,
+			},
+		}
+
+		fees := types.BatchFees{Token: token, TotalFees: sdk.NewInt(1)}
+		assert.False(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uumee"))
+
+		orch.pendingFeesSince["uumee"] = time.Now().Add(-2 * time.Hour)
+		assert.True(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uumee"))
+
+		// the fees are tracked again once requested
+		orch.batchRequested("uumee")
+		assert.False(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uumee"))
+
+		orch.forgetBatchedFees(map[string]struct{}{})
+		assert.Empty(t, orch.pendingFeesSince)
+	})
+
+	t.Run("min fee USD", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"%s":{"usd":2}}`, token)
+		}))
+		defer svr.Close()
+
+		ethFrom := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+
+		gravityContract := gravityMocks.NewMockContract(mockCtrl)
+		gravityContract.EXPECT().
+			GetERC20Decimals(gomock.Any(), ethcmn.HexToAddress(token), ethFrom).
+			Return(uint8(6), nil).
+			Times(2)
+
+		orch := gravityOrchestrator{
+			gravityContract:    gravityContract,
+			ethFrom:            ethFrom,
+			priceFeeder:        coingecko.NewCoingeckoPriceFeed(logger, 100, &coingecko.Config{BaseURL: svr.URL}),
+			batchRequestPolicy: BatchRequestPolicy{MinFeeUSD: 10},
+		}
+
+		// 4.5 tokens at $2
+		fees := types.BatchFees{Token: token, TotalFees: sdk.NewInt(4500000)}
+		assert.False(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uusdt"))
+
+		fees.TotalFees = sdk.NewInt(5000000)
+		assert.True(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uusdt"))
+	})
+
+	t.Run("min fees over min fee USD", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		// the token has no price
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer svr.Close()
+
+		ethFrom := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+
+		gravityContract := gravityMocks.NewMockContract(mockCtrl)
+		gravityContract.EXPECT().
+			GetERC20Decimals(gomock.Any(), ethcmn.HexToAddress(token), ethFrom).
+			Return(uint8(6), nil).
+			Times(1)
+
+		orch := gravityOrchestrator{
+			gravityContract: gravityContract,
+			ethFrom:         ethFrom,
+			priceFeeder:     coingecko.NewCoingeckoPriceFeed(logger, 100, &coingecko.Config{BaseURL: svr.URL}),
+			batchRequestPolicy: BatchRequestPolicy{
+				MinFeeUSD: 10,
+				MinFees:   sdk.NewCoins(sdk.NewInt64Coin("uusdt", 1000)),
+			},
+		}
+
+		fees := types.BatchFees{Token: token, TotalFees: sdk.NewInt(999)}
+		assert.False(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uusdt"))
+
+		fees.TotalFees = sdk.NewInt(1000)
+		assert.True(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uusdt"))
+
+		// the denoms without a minimum still need a price
+		assert.False(t, orch.shouldRequestBatch(context.Background(), logger, fees, "uother"))
+	})
+}
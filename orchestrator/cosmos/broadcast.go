@@ -19,7 +19,8 @@ import (
 type GravityBroadcastClient interface {
 	AccFromAddress() sdk.AccAddress
 
-	// SendValsetConfirm broadcasts in a confirmation for a specific validator set for a specific block height.
+	// SendValsetConfirm broadcasts in a confirmation for a specific validator set for a specific block height. It
+	// returns once the confirmation has been committed.
 	SendValsetConfirm(
 		ctx context.Context,
 		ethFrom ethcmn.Address,
@@ -28,7 +29,8 @@ type GravityBroadcastClient interface {
 	) error
 
 	// SendBatchConfirm broadcasts in a confirmation for a specific transaction batch set for a specific block height
-	// since transaction batches also include validator sets this has all the arguments
+	// since transaction batches also include validator sets this has all the arguments. It returns once the
+	// confirmation has been committed.
 	SendBatchConfirm(
 		ctx context.Context,
 		ethFrom ethcmn.Address,
@@ -53,6 +55,10 @@ type GravityBroadcastClient interface {
 	) error
 }
 
+// maxClaimsPerTx bounds the number of claims sent in a single tx, txs are split further if they don't fit in a block.
+// On chains without a block max gas it's the only bound on the size of the claims txs.
+const maxClaimsPerTx = 100
+
 type (
 	gravityBroadcastClient struct {
 		logger            zerolog.Logger
@@ -128,11 +134,18 @@ func (s *gravityBroadcastClient) SendValsetConfirm(
 		Nonce:        valset.Nonce,
 		Signature:    ethcmn.Bytes2Hex(signature),
 	}
-	if err = s.broadcastClient.QueueBroadcastMsg(msg); err != nil {
+	futures, err := s.broadcastClient.QueueBroadcastMsg(msg)
+	if err != nil {
 		err = errors.Wrap(err, "broadcasting MsgValsetConfirm failed")
 		return err
 	}
 
+	// wait for the confirmation to be committed, so failures are retried right away
+	if err := client.AwaitAll(ctx, futures); err != nil {
+		err = errors.Wrap(err, "MsgValsetConfirm not committed")
+		return err
+	}
+
 	return nil
 }
 
@@ -165,11 +178,18 @@ func (s *gravityBroadcastClient) SendBatchConfirm(
 		EthSigner:     ethFrom.Hex(),
 		TokenContract: batch.TokenContract,
 	}
-	if err = s.broadcastClient.QueueBroadcastMsg(msg); err != nil {
+	futures, err := s.broadcastClient.QueueBroadcastMsg(msg)
+	if err != nil {
 		err = errors.Wrap(err, "broadcasting MsgConfirmBatch failed")
 		return err
 	}
 
+	// wait for the confirmation to be committed, so failures are retried right away
+	if err := client.AwaitAll(ctx, futures); err != nil {
+		err = errors.Wrap(err, "MsgConfirmBatch not committed")
+		return err
+	}
+
 	return nil
 }
 
@@ -243,7 +263,8 @@ func (s *gravityBroadcastClient) SendRequestBatch(
 		Denom:  denom,
 		Sender: s.AccFromAddress().String(),
 	}
-	if err := s.broadcastClient.QueueBroadcastMsg(msg); err != nil {
+	// the batch request is best effort, no need to wait for it to be committed
+	if _, err := s.broadcastClient.QueueBroadcastMsg(msg); err != nil {
 		err = errors.Wrap(err, "broadcasting MsgRequestBatch failed")
 		return err
 	}
@@ -336,35 +357,54 @@ func (s *gravityBroadcastClient) broadcastEthereumEvents(events []sortableEvent)
 		Int("num_total_claims", len(events)).
 		Msg("oracle observed events; sending claims")
 
-	// We send the messages in batches of 10, so that we don't hit any limits
-	msgSets := splitMsgs(msgs, 10)
+	// We send the claims in as few txs as possible, halving the txs that don't fit in a block. Once a size fits, it's
+	// kept for the following txs.
+	size := maxClaimsPerTx
+	for len(msgs) > 0 {
+		if size > len(msgs) {
+			size = len(msgs)
+		}
+
+		var (
+			txResponse *sdk.TxResponse
+			err        error
+		)
+
+		for {
+			txResponse, err = s.broadcastClient.SyncBroadcastMsg(msgs[:size]...)
+			if errors.Is(err, client.ErrGasLimitExceeded) && size > 1 {
+				size /= 2
+				continue
+			}
+
+			break
+		}
 
-	for _, msgSet := range msgSets {
-		txResponse, err := s.broadcastClient.SyncBroadcastMsg(msgSet...)
 		if err != nil {
 			s.logger.Err(err).Msg("broadcasting multiple claims failed")
 			return err
 		}
 
+		// The tx made it into a block but it may still have failed on delivery (e.g. out of gas).
+		if txResponse.Code != 0 {
+			s.logger.Error().
+				Str("tx_hash", txResponse.TxHash).
+				Uint32("code", txResponse.Code).
+				Str("raw_log", txResponse.RawLog).
+				Msg("claims tx failed")
+
+			return errors.Errorf("claims tx %s failed with code %d: %s", txResponse.TxHash, txResponse.Code, txResponse.RawLog)
+		}
+
 		s.logger.Info().
 			Str("tx_hash", txResponse.TxHash).
 			Int("total_claims", len(events)).
-			Int("claims_sent", len(msgSet)).
+			Int("claims_sent", size).
+			Int64("gas_used", txResponse.GasUsed).
 			Msg("oracle sent set of claims successfully")
+
+		msgs = msgs[size:]
 	}
 
 	return nil
 }
-
-func splitMsgs(buf []sdk.Msg, lim int) [][]sdk.Msg {
-	var chunk []sdk.Msg
-	chunks := make([][]sdk.Msg, 0, len(buf)/lim+1)
-	for len(buf) >= lim {
-		chunk, buf = buf[:lim], buf[lim:]
-		chunks = append(chunks, chunk)
-	}
-	if len(buf) > 0 {
-		chunks = append(chunks, buf)
-	}
-	return chunks
-}
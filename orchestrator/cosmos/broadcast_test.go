@@ -15,6 +15,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/umee-network/peggo/cmd/peggo/client"
 	"github.com/umee-network/peggo/mocks"
 	wrappers "github.com/umee-network/peggo/solwrappers/Gravity.sol"
 )
@@ -26,7 +27,10 @@ func TestSendValsetConfirm(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
-		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(nil)
+		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(
+			[]*client.TxFuture{client.NewResolvedTxFuture("ABCD", nil)},
+			nil,
+		)
 		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{})
 
 		mockPersonalSignFn := func(account ethcmn.Address, data []byte) (sig []byte, err error) {
@@ -78,7 +82,7 @@ func TestSendValsetConfirm(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
-		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(errors.New("some error during broadcast"))
+		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(nil, errors.New("some error during broadcast"))
 		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{})
 
 		mockPersonalSignFn := func(account ethcmn.Address, data []byte) (sig []byte, err error) {
@@ -100,6 +104,43 @@ func TestSendValsetConfirm(t *testing.T) {
 		assert.EqualError(t, err, "broadcasting MsgValsetConfirm failed: some error during broadcast")
 	})
 
+	t.Run("tx failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
+		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(
+			[]*client.TxFuture{client.NewResolvedTxFuture("", &client.TxError{
+				TxHash:    "ABCD",
+				Codespace: "sdk",
+				Code:      11,
+				RawLog:    "out of gas",
+			})},
+			nil,
+		)
+		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{})
+
+		mockPersonalSignFn := func(account ethcmn.Address, data []byte) (sig []byte, err error) {
+			return []byte{}, nil
+		}
+
+		s := NewGravityBroadcastClient(
+			zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+			nil,
+			mockCosmos,
+			nil,
+			mockPersonalSignFn,
+		)
+
+		err := s.SendValsetConfirm(context.Background(), ethcmn.Address{}, "", types.Valset{
+			RewardAmount: sdk.NewInt(0),
+		})
+
+		var txErr *client.TxError
+		assert.True(t, errors.As(err, &txErr))
+		assert.EqualError(t, err, "MsgValsetConfirm not committed: tx ABCD failed with code 11 (sdk): out of gas")
+	})
+
 }
 
 func TestSendBatchConfirm(t *testing.T) {
@@ -109,7 +150,10 @@ func TestSendBatchConfirm(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
-		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(nil)
+		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(
+			[]*client.TxFuture{client.NewResolvedTxFuture("ABCD", nil)},
+			nil,
+		)
 		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{})
 
 		mockPersonalSignFn := func(account ethcmn.Address, data []byte) (sig []byte, err error) {
@@ -157,7 +201,7 @@ func TestSendBatchConfirm(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
-		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(errors.New("some error during broadcast"))
+		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(nil, errors.New("some error during broadcast"))
 		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{})
 
 		mockPersonalSignFn := func(account ethcmn.Address, data []byte) (sig []byte, err error) {
@@ -177,6 +221,41 @@ func TestSendBatchConfirm(t *testing.T) {
 		assert.EqualError(t, err, "broadcasting MsgConfirmBatch failed: some error during broadcast")
 	})
 
+	t.Run("tx failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
+		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(
+			[]*client.TxFuture{client.NewResolvedTxFuture("", &client.TxError{
+				TxHash:    "ABCD",
+				Codespace: "sdk",
+				Code:      11,
+				RawLog:    "out of gas",
+			})},
+			nil,
+		)
+		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{})
+
+		mockPersonalSignFn := func(account ethcmn.Address, data []byte) (sig []byte, err error) {
+			return []byte{}, nil
+		}
+
+		s := NewGravityBroadcastClient(
+			zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+			nil,
+			mockCosmos,
+			nil,
+			mockPersonalSignFn,
+		)
+
+		err := s.SendBatchConfirm(context.Background(), ethcmn.Address{}, "", types.OutgoingTxBatch{})
+
+		var txErr *client.TxError
+		assert.True(t, errors.As(err, &txErr))
+		assert.EqualError(t, err, "MsgConfirmBatch not committed: tx ABCD failed with code 11 (sdk): out of gas")
+	})
+
 }
 
 // Custom matcher for TestSendDepositClaims
@@ -376,6 +455,75 @@ func TestSendEthereumClaimsIgnoreNonSequentialNonces(t *testing.T) {
 	)
 }
 
+func TestSendEthereumClaimsDeliverTxFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
+	mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{}).AnyTimes()
+	mockCosmos.EXPECT().SyncBroadcastMsg(gomock.Any()).Return(&sdk.TxResponse{
+		TxHash: "ABCD",
+		Code:   11,
+		RawLog: "out of gas",
+	}, nil).Times(1)
+
+	s := NewGravityBroadcastClient(
+		zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		nil,
+		mockCosmos,
+		nil,
+		nil,
+	)
+
+	deposits := []*wrappers.GravitySendToCosmosEvent{
+		{
+			EventNonce: big.NewInt(1),
+			Amount:     big.NewInt(123),
+		},
+	}
+
+	err := s.SendEthereumClaims(context.Background(), 0, deposits, nil, nil, nil, time.Microsecond)
+	assert.EqualError(t, err, "claims tx ABCD failed with code 11: out of gas")
+}
+
+func TestSendEthereumClaimsSplitByGas(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
+	mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{}).AnyTimes()
+
+	var sent []int
+	mockCosmos.EXPECT().SyncBroadcastMsg(gomock.Any()).DoAndReturn(func(msgs ...sdk.Msg) (*sdk.TxResponse, error) {
+		if len(msgs) > 2 {
+			return nil, errors.Wrap(client.ErrGasLimitExceeded, "too much gas")
+		}
+
+		sent = append(sent, len(msgs))
+		return &sdk.TxResponse{}, nil
+	}).AnyTimes()
+
+	s := NewGravityBroadcastClient(
+		zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		nil,
+		mockCosmos,
+		nil,
+		nil,
+	)
+
+	deposits := make([]*wrappers.GravitySendToCosmosEvent, 5)
+	for i := range deposits {
+		deposits[i] = &wrappers.GravitySendToCosmosEvent{
+			EventNonce: big.NewInt(int64(i + 1)),
+			Amount:     big.NewInt(1),
+		}
+	}
+
+	err := s.SendEthereumClaims(context.Background(), 0, deposits, nil, nil, nil, time.Microsecond)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, sent)
+}
+
 func TestSendRequestBatch(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
@@ -383,7 +531,10 @@ func TestSendRequestBatch(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
-		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(nil)
+		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(
+			[]*client.TxFuture{client.NewResolvedTxFuture("ABCD", nil)},
+			nil,
+		)
 		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{})
 
 		s := NewGravityBroadcastClient(
@@ -404,7 +555,7 @@ func TestSendRequestBatch(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
-		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(errors.New("some error during broadcast"))
+		mockCosmos.EXPECT().QueueBroadcastMsg(gomock.Any()).Return(nil, errors.New("some error during broadcast"))
 		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{})
 
 		s := NewGravityBroadcastClient(
@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/pkg/errors"
@@ -36,10 +37,75 @@ func (p *gravityOrchestrator) CheckForEvents(
 		currentBlock = startingBlock + p.ethBlocksPerLoop
 	}
 
+	events, err := p.getEthereumEvents(ctx, startingBlock, currentBlock)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := p.sendEthereumClaims(ctx, events); err != nil {
+		return 0, err
+	}
+
+	return currentBlock, nil
+}
+
+// ethereumEvents groups all the Gravity contract events observed in a range of Ethereum blocks.
+type ethereumEvents struct {
+	erc20Deployed []*wrappers.GravityERC20DeployedEvent
+	sendToCosmos  []*wrappers.GravitySendToCosmosEvent
+	batchExecuted []*wrappers.GravityTransactionBatchExecutedEvent
+	valsetUpdated []*wrappers.GravityValsetUpdatedEvent
+}
+
+// append adds the events of other at the end of e's.
+func (e *ethereumEvents) append(other *ethereumEvents) {
+	e.erc20Deployed = append(e.erc20Deployed, other.erc20Deployed...)
+	e.sendToCosmos = append(e.sendToCosmos, other.sendToCosmos...)
+	e.batchExecuted = append(e.batchExecuted, other.batchExecuted...)
+	e.valsetUpdated = append(e.valsetUpdated, other.valsetUpdated...)
+}
+
+// lastEventNonce returns the highest event nonce among all the events, or zero if there are none.
+func (e *ethereumEvents) lastEventNonce() uint64 {
+	var nonce uint64
+
+	for _, ev := range e.erc20Deployed {
+		if n := ev.EventNonce.Uint64(); n > nonce {
+			nonce = n
+		}
+	}
+
+	for _, ev := range e.sendToCosmos {
+		if n := ev.EventNonce.Uint64(); n > nonce {
+			nonce = n
+		}
+	}
+
+	for _, ev := range e.batchExecuted {
+		if n := ev.EventNonce.Uint64(); n > nonce {
+			nonce = n
+		}
+	}
+
+	for _, ev := range e.valsetUpdated {
+		if n := ev.EventNonce.Uint64(); n > nonce {
+			nonce = n
+		}
+	}
+
+	return nonce
+}
+
+// getEthereumEvents scans the Gravity contract events between startingBlock and currentBlock (both included).
+func (p *gravityOrchestrator) getEthereumEvents(
+	ctx context.Context,
+	startingBlock uint64,
+	currentBlock uint64,
+) (*ethereumEvents, error) {
 	gravityFilterer, err := wrappers.NewGravityFilterer(p.gravityContract.Address(), p.ethProvider)
 	if err != nil {
 		err = errors.Wrap(err, "failed to init Gravity events filterer")
-		return 0, err
+		return nil, err
 	}
 
 	var erc20DeployedEvents []*wrappers.GravityERC20DeployedEvent
@@ -56,9 +122,9 @@ func (p *gravityOrchestrator) CheckForEvents(
 
 			if !isUnknownBlockErr(err) {
 				err = errors.Wrap(err, "failed to scan past ERC20Deployed events from Ethereum")
-				return 0, err
+				return nil, err
 			} else if iter == nil {
-				return 0, errors.New("no iterator returned")
+				return nil, errors.New("no iterator returned")
 			}
 		}
 
@@ -90,9 +156,9 @@ func (p *gravityOrchestrator) CheckForEvents(
 
 			if !isUnknownBlockErr(err) {
 				err = errors.Wrap(err, "failed to scan past SendToCosmos events from Ethereum")
-				return 0, err
+				return nil, err
 			} else if iter == nil {
-				return 0, errors.New("no iterator returned")
+				return nil, errors.New("no iterator returned")
 			}
 		}
 
@@ -123,9 +189,9 @@ func (p *gravityOrchestrator) CheckForEvents(
 
 			if !isUnknownBlockErr(err) {
 				err = errors.Wrap(err, "failed to scan past TransactionBatchExecuted events from Ethereum")
-				return 0, err
+				return nil, err
 			} else if iter == nil {
-				return 0, errors.New("no iterator returned")
+				return nil, errors.New("no iterator returned")
 			}
 		}
 
@@ -156,9 +222,9 @@ func (p *gravityOrchestrator) CheckForEvents(
 
 			if !isUnknownBlockErr(err) {
 				err = errors.Wrap(err, "failed to scan past ValsetUpdatedEvent events from Ethereum")
-				return 0, err
+				return nil, err
 			} else if iter == nil {
-				return 0, errors.New("no iterator returned")
+				return nil, errors.New("no iterator returned")
 			}
 		}
 
@@ -175,6 +241,16 @@ func (p *gravityOrchestrator) CheckForEvents(
 		Int("num_events", len(valsetUpdatedEvents)).
 		Msg("scanned ValsetUpdatedEvents events from Ethereum")
 
+	return &ethereumEvents{
+		erc20Deployed: erc20DeployedEvents,
+		sendToCosmos:  sendToCosmosEvents,
+		batchExecuted: transactionBatchExecutedEvents,
+		valsetUpdated: valsetUpdatedEvents,
+	}, nil
+}
+
+// sendEthereumClaims sends to Cosmos the claims for all the events that this oracle hasn't relayed yet.
+func (p *gravityOrchestrator) sendEthereumClaims(ctx context.Context, events *ethereumEvents) error {
 	// note that starting block overlaps with our last checked block, because we have to deal with
 	// the possibility that the relayer was killed after relaying only one of multiple events in a single
 	// block, so we also need this routine so make sure we don't send in the first event in this hypothetical
@@ -186,38 +262,81 @@ func (p *gravityOrchestrator) CheckForEvents(
 
 	if err != nil {
 		err = errors.New("failed to query last claim event from backend")
-		return 0, err
+		return err
 	}
 
 	if lastEventResp == nil {
-		return 0, errors.New("no last event response returned")
+		return errors.New("no last event response returned")
 	}
 
-	deposits := filterSendToCosmosEventsByNonce(sendToCosmosEvents, lastEventResp.EventNonce)
-	withdraws := filterTransactionBatchExecutedEventsByNonce(
-		transactionBatchExecutedEvents,
+	pending := &ethereumEvents{
+		sendToCosmos:  filterSendToCosmosEventsByNonce(events.sendToCosmos, lastEventResp.EventNonce),
+		batchExecuted: filterTransactionBatchExecutedEventsByNonce(events.batchExecuted, lastEventResp.EventNonce),
+		valsetUpdated: filterValsetUpdateEventsByNonce(events.valsetUpdated, lastEventResp.EventNonce),
+		erc20Deployed: filterERC20DeployedEventsByNonce(events.erc20Deployed, lastEventResp.EventNonce),
+	}
+
+	expectedNonce := pending.lastEventNonce()
+	if expectedNonce == 0 {
+		return nil
+	}
+
+	if err := p.gravityBroadcastClient.SendEthereumClaims(
+		ctx,
 		lastEventResp.EventNonce,
-	)
-	valsetUpdates := filterValsetUpdateEventsByNonce(valsetUpdatedEvents, lastEventResp.EventNonce)
-	deployedERC20Updates := filterERC20DeployedEventsByNonce(erc20DeployedEvents, lastEventResp.EventNonce)
-
-	if len(deposits) > 0 || len(withdraws) > 0 || len(valsetUpdates) > 0 || len(deployedERC20Updates) > 0 {
-
-		if err := p.gravityBroadcastClient.SendEthereumClaims(
-			ctx,
-			lastEventResp.EventNonce,
-			deposits,
-			withdraws,
-			valsetUpdates,
-			deployedERC20Updates,
-			p.cosmosBlockTime,
-		); err != nil {
-			err = errors.Wrap(err, "failed to send ethereum claims to Cosmos chain")
-			return 0, err
+		pending.sendToCosmos,
+		pending.batchExecuted,
+		pending.valsetUpdated,
+		pending.erc20Deployed,
+		p.cosmosBlockTime,
+	); err != nil {
+		err = errors.Wrap(err, "failed to send ethereum claims to Cosmos chain")
+		return err
+	}
+
+	return p.waitForEventNonce(ctx, expectedNonce)
+}
+
+// ErrClaimsNotIncluded is returned when the claims sent to Cosmos were broadcast successfully but the last event nonce
+// of this orchestrator didn't advance accordingly, i.e. the txs failed on delivery or some of the events were skipped.
+var ErrClaimsNotIncluded = errors.New("claims not included on chain")
+
+// claimInclusionChecks is the number of times the last event nonce is queried after sending claims before giving up.
+const claimInclusionChecks = 3
+
+// waitForEventNonce checks that the last event nonce claimed by this orchestrator has reached expectedNonce. The node
+// we query may lag behind the block our claims were included in, so it's given a few block times to catch up.
+func (p *gravityOrchestrator) waitForEventNonce(ctx context.Context, expectedNonce uint64) error {
+	var lastEventNonce uint64
+
+	for i := 0; i < claimInclusionChecks; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(p.cosmosBlockTime):
+			}
+		}
+
+		lastEventResp, err := p.cosmosQueryClient.LastEventNonceByAddr(ctx, &types.QueryLastEventNonceByAddrRequest{
+			Address: p.gravityBroadcastClient.AccFromAddress().String(),
+		})
+		if err != nil {
+			err = errors.Wrap(err, "failed to query last claim event from backend")
+			return err
+		}
+
+		if lastEventResp == nil {
+			return errors.New("no last event response returned")
+		}
+
+		lastEventNonce = lastEventResp.EventNonce
+		if lastEventNonce >= expectedNonce {
+			return nil
 		}
 	}
 
-	return currentBlock, nil
+	return errors.Wrapf(ErrClaimsNotIncluded, "last event nonce is %d, expected %d", lastEventNonce, expectedNonce)
 }
 
 func filterSendToCosmosEventsByNonce(
@@ -148,6 +148,13 @@ func TestCheckForEvents(t *testing.T) {
 			EventNonce: 1,
 		}, nil)
 
+		// once the claims are sent the last event nonce must match the ERC20Deployed event's
+		mockQClient.EXPECT().LastEventNonceByAddr(gomock.Any(), &types.QueryLastEventNonceByAddrRequest{
+			Address: gravityBroadcastClient.AccFromAddress().String(),
+		}).Return(&types.QueryLastEventNonceByAddrResponse{
+			EventNonce: 888,
+		}, nil)
+
 		orch := NewGravityOrchestrator(
 			logger,
 			mockQClient,
@@ -667,6 +674,78 @@ func TestIsUnknownBlockErr(t *testing.T) {
 	assert.False(t, isUnknownBlockErr(otherErr))
 }
 
+func TestSendEthereumClaimsInclusion(t *testing.T) {
+	fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	events := &ethereumEvents{
+		sendToCosmos: []*wrappers.GravitySendToCosmosEvent{
+			{EventNonce: big.NewInt(2), Amount: big.NewInt(1)},
+			{EventNonce: big.NewInt(3), Amount: big.NewInt(1)},
+		},
+	}
+
+	newOrchestrator := func(mockCtrl *gomock.Controller, mockQClient *mocks.MockQueryClient) *gravityOrchestrator {
+		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
+		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{}).AnyTimes()
+		mockCosmos.EXPECT().SyncBroadcastMsg(gomock.Any()).Return(&sdk.TxResponse{}, nil).Times(1)
+
+		ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		ethProvider.EXPECT().PendingNonceAt(gomock.Any(), fromAddress).Return(uint64(0), nil)
+		ethCommitter, _ := committer.NewEthCommitter(logger, fromAddress, 1.0, 1.0, nil, ethProvider)
+		gravityContract, _ := gravity.NewGravityContract(logger, ethCommitter, ethcmn.Address{}, nil)
+
+		return NewGravityOrchestrator(
+			logger,
+			mockQClient,
+			cosmos.NewGravityBroadcastClient(logger, nil, mockCosmos, nil, nil),
+			gravityContract,
+			fromAddress,
+			nil,
+			nil,
+			nil,
+			time.Millisecond,
+			time.Millisecond,
+			time.Second,
+			100,
+		).(*gravityOrchestrator)
+	}
+
+	t.Run("included", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockQClient := mocks.NewMockQueryClient(mockCtrl)
+		gomock.InOrder(
+			mockQClient.EXPECT().LastEventNonceByAddr(gomock.Any(), gomock.Any()).
+				Return(&types.QueryLastEventNonceByAddrResponse{EventNonce: 1}, nil),
+			// the node is one block behind at first
+			mockQClient.EXPECT().LastEventNonceByAddr(gomock.Any(), gomock.Any()).
+				Return(&types.QueryLastEventNonceByAddrResponse{EventNonce: 1}, nil),
+			mockQClient.EXPECT().LastEventNonceByAddr(gomock.Any(), gomock.Any()).
+				Return(&types.QueryLastEventNonceByAddrResponse{EventNonce: 3}, nil),
+		)
+
+		orch := newOrchestrator(mockCtrl, mockQClient)
+		assert.NoError(t, orch.sendEthereumClaims(context.Background(), events))
+	})
+
+	t.Run("not included", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockQClient := mocks.NewMockQueryClient(mockCtrl)
+		mockQClient.EXPECT().LastEventNonceByAddr(gomock.Any(), gomock.Any()).
+			Return(&types.QueryLastEventNonceByAddrResponse{EventNonce: 1}, nil).
+			Times(1 + claimInclusionChecks)
+
+		orch := newOrchestrator(mockCtrl, mockQClient)
+		err := orch.sendEthereumClaims(context.Background(), events)
+		assert.True(t, errors.Is(err, ErrClaimsNotIncluded))
+		assert.EqualError(t, err, "last event nonce is 1, expected 3: claims not included on chain")
+	})
+}
+
 type matchFilterQuery struct {
 	q ethereum.FilterQuery
 }
@@ -9,13 +9,24 @@ import (
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
 	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
 )
 
+var (
+	// ErrInsufficientFunds is returned when the node rejects a tx because its sender can't pay for it, and there's no
+	// other account in the sender pool to send it from.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrBalanceReserve is returned when a tx isn't sent because it could spend the balance reserve of its sender.
+	ErrBalanceReserve = errors.New("tx would spend the balance reserve")
+)
+
 // EVMCommitter defines an interface for submitting transactions
 // into Ethereum, Matic, and other EVM-compatible networks.
 type EVMCommitter interface {
 	FromAddress() ethcmn.Address
+	// SenderAddresses returns all the accounts txs are sent from, the committer's own first.
+	SenderAddresses() []ethcmn.Address
 	Provider() provider.EVMProvider
 	SendTx(
 		ctx context.Context,
@@ -25,10 +36,15 @@ type EVMCommitter interface {
 		gasPrice *big.Int,
 	) (txHash ethcmn.Hash, err error)
 
+	// GasPrice returns the gas price txs are sent at, depending on how urgent they are.
+	GasPrice(ctx context.Context, urgency gasprice.Urgency) (*big.Int, error)
+
+	// EstimateGas returns the gas limit and the gas price of a tx, the gas price depending on how urgent it is.
 	EstimateGas(
 		ctx context.Context,
 		recipient ethcmn.Address,
 		txData []byte,
+		urgency gasprice.Urgency,
 	) (gasCost uint64, gasPrice *big.Int, err error)
 }
 
@@ -38,6 +54,29 @@ type options struct {
 	GasPrice   decimal.Decimal
 	GasLimit   uint64
 	RPCTimeout time.Duration
+
+	// PrivateRelay is used to send txs privately instead of to the public mempool, if set.
+	PrivateRelay *privateRelay
+
+	// Senders are the accounts txs are sent from besides the committer's own.
+	Senders []Sender
+	// LowBalanceWarning is the balance below which the accounts txs are sent from are reported, if set.
+	LowBalanceWarning *big.Int
+	// BalanceReserve is the balance txs aren't allowed to spend, if set.
+	BalanceReserve *big.Int
+
+	// MaxGasPrice is the highest gas price txs are sent at, if set.
+	MaxGasPrice *big.Int
+	// MaxBaseFeeMultiplier caps the gas price of txs to a multiple of the base fee, if set.
+	MaxBaseFeeMultiplier float64
+	// MaxTxCost is the highest maximum cost of a tx, if set.
+	MaxTxCost *big.Int
+	// SpendBudget caps the ETH spent per hour and per day, if set.
+	SpendBudget *spendBudget
+
+	// GasPriceEstimator estimates the gas price of txs, the node's suggestion times the gas price adjustment if not
+	// set.
+	GasPriceEstimator gasprice.Estimator
 }
 
 func defaultOptions() *options {
@@ -94,6 +133,19 @@ func OptionGasLimit(limit uint64) EVMCommitterOption {
 	}
 }
 
+// OptionGasPriceEstimator sets how the gas price of txs is estimated, instead of adjusting the one suggested by the
+// node.
+func OptionGasPriceEstimator(estimator gasprice.Estimator) EVMCommitterOption {
+	return func(o *options) error {
+		if estimator == nil {
+			return errors.New("nil gas price estimator")
+		}
+
+		o.GasPriceEstimator = estimator
+		return nil
+	}
+}
+
 func TxBroadcastTimeout(dur time.Duration) EVMCommitterOption {
 	return func(o *options) error {
 		o.RPCTimeout = dur
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
 	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
 	"github.com/umee-network/peggo/orchestrator/ethereum/util"
 )
@@ -41,10 +42,21 @@ func NewEthCommitter(
 		return nil, err
 	}
 
-	committer.nonceCache.Sync(fromAddress, func() (uint64, error) {
-		nonce, err := evmProvider.PendingNonceAt(context.TODO(), fromAddress)
-		return nonce, err
-	})
+	if committer.committerOpts.GasPriceEstimator == nil {
+		committer.committerOpts.GasPriceEstimator = gasprice.NewAdjustedEstimator(evmProvider, ethGasPriceAdjustment)
+	}
+
+	committer.senders = newSenderPool(
+		append([]Sender{{Address: fromAddress, Signer: fromSigner}}, committer.committerOpts.Senders...)...,
+	)
+
+	for _, sender := range committer.senders.senders {
+		address := sender.Address
+		committer.nonceCache.Sync(address, func() (uint64, error) {
+			nonce, err := evmProvider.PendingNonceAt(context.TODO(), address)
+			return nonce, err
+		})
+	}
 
 	return committer, nil
 }
@@ -60,6 +72,7 @@ type ethCommitter struct {
 	ethGasLimitAdjustment float64
 	evmProvider           provider.EVMProviderWithRet
 	nonceCache            util.NonceCache
+	senders               *senderPool
 }
 
 func (e *ethCommitter) FromAddress() ethcmn.Address {
@@ -70,36 +83,22 @@ func (e *ethCommitter) Provider() provider.EVMProvider {
 	return e.evmProvider
 }
 
+func (e *ethCommitter) GasPrice(ctx context.Context, urgency gasprice.Urgency) (*big.Int, error) {
+	return e.committerOpts.GasPriceEstimator.GasPrice(ctx, urgency)
+}
+
 func (e *ethCommitter) EstimateGas(
 	ctx context.Context,
 	recipient ethcmn.Address,
 	txData []byte,
+	urgency gasprice.Urgency,
 ) (gasCost uint64, gasPrice *big.Int, err error) {
-
-	opts := &bind.TransactOpts{
-		From:     e.fromAddress,
-		Signer:   e.fromSigner,
-		GasPrice: e.committerOpts.GasPrice.BigInt(),
-		GasLimit: e.committerOpts.GasLimit,
-		Context:  ctx, // with RPC timeout
-	}
-
-	suggestedGasPrice, err := e.evmProvider.SuggestGasPrice(opts.Context)
+	gasPrice, err = e.GasPrice(ctx, urgency)
 	if err != nil {
-		return 0, nil, errors.Errorf("failed to suggest gas price: %v", err)
+		return 0, nil, err
 	}
 
-	// Suggested gas price may not be accurate, so we multiply the result by the gas price adjustment factor.
-	incrementedPrice := big.NewFloat(0).Mul(
-		new(big.Float).SetInt(suggestedGasPrice),
-		big.NewFloat(e.ethGasPriceAdjustment),
-	)
-
-	gasPrice = new(big.Int)
-	incrementedPrice.Int(gasPrice)
-
-	opts.GasPrice = gasPrice
-	msg := ethereum.CallMsg{From: opts.From, To: &recipient, GasPrice: gasPrice, Value: nil, Data: txData}
+	msg := ethereum.CallMsg{From: e.fromAddress, To: &recipient, GasPrice: gasPrice, Value: nil, Data: txData}
 
 	gasCost, err = e.evmProvider.EstimateGas(ctx, msg)
 
@@ -116,13 +115,74 @@ func (e *ethCommitter) SendTx(
 	gasCost uint64,
 	gasPrice *big.Int,
 ) (txHash ethcmn.Hash, err error) {
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gasCost), gasPrice)
+	if err := e.checkGasPrice(ctx, gasPrice, cost); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	if budget := e.committerOpts.SpendBudget; budget != nil {
+		spend, reserveErr := budget.reserve(cost)
+		if reserveErr != nil {
+			return ethcmn.Hash{}, reserveErr
+		}
+
+		// only what's actually sent counts against the budgets
+		defer func() {
+			if err == nil {
+				return
+			}
+
+			if err := budget.cancel(spend); err != nil {
+				e.logger.Err(err).Msg("failed to cancel the spending of an unsent tx")
+			}
+		}()
+	}
+
+	// An account out of funds is set aside and the tx is sent from another one, until every account is out of funds.
+	for {
+		var sender Sender
+		sender, err = e.acquireSender(ctx)
+		if err != nil {
+			return ethcmn.Hash{}, err
+		}
+
+		txHash, err = e.sendTxFrom(ctx, sender, recipient, txData, gasCost, gasPrice)
+		e.senders.release(sender)
+
+		if errors.Is(err, ErrInsufficientFunds) && e.senders.outOfFunds(sender.Address) {
+			e.logger.Warn().
+				Err(err).
+				Str("sender", sender.Address.Hex()).
+				Msg("sender account is out of funds; sending the tx from another account")
+
+			continue
+		}
+
+		return txHash, err
+	}
+}
+
+// sendTxFrom signs the tx with the sender account and sends it.
+func (e *ethCommitter) sendTxFrom(
+	ctx context.Context,
+	sender Sender,
+	recipient ethcmn.Address,
+	txData []byte,
+	gasCost uint64,
+	gasPrice *big.Int,
+) (txHash ethcmn.Hash, err error) {
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gasCost), gasPrice)
+	if err := e.checkBalanceReserve(ctx, sender.Address, cost); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
 	opts := &bind.TransactOpts{
-		From:   e.fromAddress,
-		Signer: e.fromSigner,
+		From:   sender.Address,
+		Signer: sender.Signer,
 
 		GasPrice: gasPrice,
 		GasLimit: gasCost,
-		Context:  ctx, // with RPC timeout
+		Context:  ctx,
 	}
 
 	resyncNonces := func(from ethcmn.Address) {
@@ -136,15 +196,12 @@ func (e *ethCommitter) SendTx(
 		})
 	}
 
-	if err := e.nonceCache.Serialize(e.fromAddress, func() (err error) {
-		nonce, _ := e.nonceCache.Get(e.fromAddress)
+	if err := e.nonceCache.Serialize(sender.Address, func() (err error) {
+		nonce, _ := e.nonceCache.Get(sender.Address)
 		var resyncUsed bool
 
 		for {
 			opts.Nonce = big.NewInt(nonce)
-			var cancel context.CancelFunc
-			opts.Context, cancel = context.WithTimeout(ctx, e.committerOpts.RPCTimeout)
-			defer cancel()
 
 			tx := types.NewTransaction(opts.Nonce.Uint64(), recipient, nil, opts.GasLimit, opts.GasPrice, txData)
 			signedTx, err := opts.Signer(opts.From, tx)
@@ -155,11 +212,11 @@ func (e *ethCommitter) SendTx(
 
 			txHash = signedTx.Hash()
 
-			txHashRet, err := e.evmProvider.SendTransactionWithRet(opts.Context, signedTx)
+			txHashRet, err := e.sendTransaction(ctx, signedTx)
 			if err == nil {
 				// override with a real hash from node resp
 				txHash = txHashRet
-				e.nonceCache.Incr(e.fromAddress)
+				e.nonceCache.Incr(sender.Address)
 				return nil
 			}
 
@@ -169,9 +226,12 @@ func (e *ethCommitter) SendTx(
 				Msg("sendTransaction failed")
 
 			switch {
+			case strings.Contains(err.Error(), "insufficient funds"):
+				err := errors.Wrapf(ErrInsufficientFunds, "%s: %s", sender.Address.Hex(), err)
+				return err
 			case strings.Contains(err.Error(), "invalid sender"):
 				err := errors.New("failed to sign transaction")
-				e.nonceCache.Incr(e.fromAddress)
+				e.nonceCache.Incr(sender.Address)
 				return err
 			case strings.Contains(err.Error(), "nonce is too low"),
 				strings.Contains(err.Error(), "nonce is too high"),
@@ -179,18 +239,18 @@ func (e *ethCommitter) SendTx(
 
 				if resyncUsed {
 					e.logger.Error().
-						Str("from_address", e.fromAddress.Hex()).
+						Str("from_address", sender.Address.Hex()).
 						Int64("nonce", nonce).
 						Msg("nonces synced, but still wrong nonce for address")
 					err = errors.Wrapf(err, "nonce %d mismatch", nonce)
 					return err
 				}
 
-				resyncNonces(e.fromAddress)
+				resyncNonces(sender.Address)
 
 				resyncUsed = true
 				// try again with updated nonce
-				nonce, _ = e.nonceCache.Get(e.fromAddress)
+				nonce, _ = e.nonceCache.Get(sender.Address)
 				opts.Nonce = big.NewInt(nonce)
 
 				continue
@@ -198,14 +258,14 @@ func (e *ethCommitter) SendTx(
 			default:
 				if strings.Contains(err.Error(), "known transaction") {
 					// skip one nonce step, try to send again
-					nonce := e.nonceCache.Incr(e.fromAddress)
+					nonce := e.nonceCache.Incr(sender.Address)
 					opts.Nonce = big.NewInt(nonce)
 					continue
 				}
 
 				if strings.Contains(err.Error(), "VM Exception") {
 					// a VM execution consumes gas and nonce is increasing
-					e.nonceCache.Incr(e.fromAddress)
+					e.nonceCache.Incr(sender.Address)
 					return err
 				}
 
@@ -216,5 +276,7 @@ func (e *ethCommitter) SendTx(
 		return ethcmn.Hash{}, err
 	}
 
+	e.checkSenderBalance(ctx, sender.Address)
+
 	return txHash, nil
 }
@@ -0,0 +1,312 @@
+package committer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// PrivateRelayMethod is the JSON-RPC method used to send txs to a private relay.
+type PrivateRelayMethod string
+
+const (
+	// PrivateRelayBundle sends the tx as a single tx bundle with eth_sendBundle, once per targeted block.
+	PrivateRelayBundle PrivateRelayMethod = "bundle"
+	// PrivateRelayPrivateTx sends the tx with eth_sendPrivateTransaction, valid up to the last targeted block.
+	PrivateRelayPrivateTx PrivateRelayMethod = "private"
+)
+
+const (
+	defaultPrivateRelayPollInterval = 3 * time.Second
+	privateRelaySignatureHeader     = "X-Flashbots-Signature"
+)
+
+// privateRelay sends signed txs to a bundle-style relay endpoint instead of the public mempool, so other relayers
+// can't copy their call data.
+type privateRelay struct {
+	endpoint string
+	method   PrivateRelayMethod
+	// blocks is the number of blocks targeted after the current one.
+	blocks uint64
+	// timeout is how long to wait for the tx to be mined before broadcasting it publicly.
+	timeout      time.Duration
+	pollInterval time.Duration
+	// authKey signs the requests, relays use it to identify the sender.
+	authKey    *ecdsa.PrivateKey
+	httpClient *http.Client
+}
+
+// OptionPrivateRelay makes the committer send txs to the given private relay endpoint, targeting the next blocks
+// blocks, and broadcast them publicly if they weren't mined after timeout. The requests are signed with authKey, so
+// the relay keeps identifying the sender across restarts, or with a random key if it's nil.
+func OptionPrivateRelay(
+	endpoint string,
+	method PrivateRelayMethod,
+	blocks uint64,
+	timeout time.Duration,
+	authKey *ecdsa.PrivateKey,
+) EVMCommitterOption {
+	return func(o *options) error {
+		switch method {
+		case PrivateRelayBundle, PrivateRelayPrivateTx:
+		default:
+			return errors.Errorf("invalid private relay method: %s", method)
+		}
+
+		if blocks == 0 {
+			return errors.New("the private relay must target at least one block")
+		}
+
+		if authKey == nil {
+			var err error
+			if authKey, err = crypto.GenerateKey(); err != nil {
+				err = errors.Wrap(err, "failed to generate private relay auth key")
+				return err
+			}
+		}
+
+		o.PrivateRelay = &privateRelay{
+			endpoint:     endpoint,
+			method:       method,
+			blocks:       blocks,
+			timeout:      timeout,
+			pollInterval: defaultPrivateRelayPollInterval,
+			authKey:      authKey,
+			httpClient:   &http.Client{},
+		}
+
+		return nil
+	}
+}
+
+type privateRelayRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type privateRelayResponse struct {
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// call makes a JSON-RPC request to the relay, signed the same way Flashbots expects.
+func (r *privateRelay) call(ctx context.Context, method string, params ...interface{}) error {
+	body, err := json.Marshal(privateRelayRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	signature, err := crypto.Sign(accounts.TextHash([]byte(crypto.Keccak256Hash(body).Hex())), r.authKey)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(
+		privateRelaySignatureHeader,
+		fmt.Sprintf("%s:%s", crypto.PubkeyToAddress(r.authKey.PublicKey).Hex(), hexutil.Encode(signature)),
+	)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("private relay returned %s: %s", resp.Status, respBody)
+	}
+
+	var rpcResp privateRelayResponse
+	if err := json.Unmarshal(respBody, &rpcResp); err != nil {
+		return errors.Wrap(err, "failed to decode private relay response")
+	}
+
+	if rpcResp.Error != nil {
+		return errors.Errorf("private relay error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+
+	return nil
+}
+
+// send submits the signed tx to the relay for the blocks after currentBlock, and returns the last block it was
+// accepted for, zero if none. A bundle refused for a later block doesn't undo the ones accepted before it, so the
+// error is returned along with the last accepted block.
+func (r *privateRelay) send(ctx context.Context, signedTx *types.Transaction, currentBlock uint64) (uint64, error) {
+	rawTx, err := signedTx.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	lastBlock := currentBlock + r.blocks
+
+	if r.method == PrivateRelayPrivateTx {
+		err := r.call(ctx, "eth_sendPrivateTransaction", map[string]interface{}{
+			"tx":             hexutil.Encode(rawTx),
+			"maxBlockNumber": hexutil.EncodeUint64(lastBlock),
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return lastBlock, nil
+	}
+
+	var sentBlock uint64
+
+	// a bundle only targets a single block
+	for block := currentBlock + 1; block <= lastBlock; block++ {
+		err := r.call(ctx, "eth_sendBundle", map[string]interface{}{
+			"txs":         []string{hexutil.Encode(rawTx)},
+			"blockNumber": hexutil.EncodeUint64(block),
+		})
+		if err != nil {
+			return sentBlock, err
+		}
+
+		sentBlock = block
+	}
+
+	return sentBlock, nil
+}
+
+// sendTransaction sends the signed tx to the private relay if there's one, falling back to the public mempool if the
+// relay can't be reached. The RPC calls time out after RPCTimeout, while a private tx is watched until ctx is done.
+func (e *ethCommitter) sendTransaction(ctx context.Context, signedTx *types.Transaction) (ethcmn.Hash, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, e.committerOpts.RPCTimeout)
+	defer cancel()
+
+	relay := e.committerOpts.PrivateRelay
+	if relay == nil {
+		return e.evmProvider.SendTransactionWithRet(rpcCtx, signedTx)
+	}
+
+	header, err := e.evmProvider.HeaderByNumber(rpcCtx, nil)
+	if err == nil {
+		var lastBlock uint64
+		lastBlock, err = relay.send(rpcCtx, signedTx, header.Number.Uint64())
+
+		// the tx is private as soon as the relay accepted it for a block
+		if lastBlock > 0 {
+			if err != nil {
+				e.logger.Warn().
+					Err(err).
+					Str("tx_hash", signedTx.Hash().Hex()).
+					Uint64("last_block", lastBlock).
+					Msg("private relay refused the tx for the later blocks")
+			}
+
+			e.logger.Info().
+				Str("tx_hash", signedTx.Hash().Hex()).
+				Uint64("last_block", lastBlock).
+				Msg("sent tx to the private relay")
+
+			go e.watchPrivateTx(ctx, signedTx, lastBlock)
+
+			return signedTx.Hash(), nil
+		}
+	}
+
+	e.logger.Err(err).Str("tx_hash", signedTx.Hash().Hex()).Msg("failed to send tx privately; broadcasting it")
+
+	return e.evmProvider.SendTransactionWithRet(rpcCtx, signedTx)
+}
+
+// watchPrivateTx waits for the privately sent tx to be mined, and broadcasts it publicly if it isn't by the time the
+// targeted blocks have passed or the private relay timeout expires. It gives up once ctx is done.
+func (e *ethCommitter) watchPrivateTx(ctx context.Context, signedTx *types.Transaction, lastBlock uint64) {
+	relay := e.committerOpts.PrivateRelay
+	logger := e.logger.With().Str("tx_hash", signedTx.Hash().Hex()).Logger()
+
+	timeout := time.After(relay.timeout)
+	ticker := time.NewTicker(relay.pollInterval)
+	defer ticker.Stop()
+
+wait:
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Warn().Err(ctx.Err()).Msg("stopped watching private tx")
+			return
+
+		case <-timeout:
+			break wait
+
+		case <-ticker.C:
+			mined, blockNumber, err := e.privateTxStatus(ctx, signedTx.Hash())
+			if err != nil {
+				logger.Err(err).Msg("failed to check private tx status")
+				continue
+			}
+
+			if mined {
+				logger.Info().Msg("private tx mined")
+				return
+			}
+
+			if blockNumber > lastBlock {
+				break wait
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.committerOpts.RPCTimeout)
+	defer cancel()
+
+	// the tx may have been mined since the last check, in which case the node rejects it
+	if _, err := e.evmProvider.SendTransactionWithRet(ctx, signedTx); err != nil {
+		logger.Err(err).Msg("failed to broadcast private tx")
+		return
+	}
+
+	logger.Warn().Msg("private tx wasn't mined in time; broadcasted it publicly")
+}
+
+// privateTxStatus returns whether the tx was mined, and the current block number.
+func (e *ethCommitter) privateTxStatus(ctx context.Context, txHash ethcmn.Hash) (bool, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.committerOpts.RPCTimeout)
+	defer cancel()
+
+	_, err := e.evmProvider.TransactionReceipt(ctx, txHash)
+	switch {
+	case err == nil:
+		return true, 0, nil
+	case !errors.Is(err, ethereum.NotFound):
+		return false, 0, err
+	}
+
+	header, err := e.evmProvider.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return false, header.Number.Uint64(), nil
+}
@@ -0,0 +1,316 @@
+package committer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umee-network/peggo/mocks"
+)
+
+type stubRelayRequest struct {
+	Method string                   `json:"method"`
+	Params []map[string]interface{} `json:"params"`
+}
+
+// stubRelay is a local private relay endpoint recording the requests it receives.
+type stubRelay struct {
+	t        *testing.T
+	authAddr ethcmn.Address
+	fail     bool
+	// accepted is the number of requests accepted before failing, if fail is set.
+	accepted int
+
+	mtx      sync.Mutex
+	requests []stubRelayRequest
+}
+
+func (r *stubRelay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(r.t, err)
+
+	// the request must be signed by the auth key
+	parts := strings.Split(req.Header.Get(privateRelaySignatureHeader), ":")
+	require.Len(r.t, parts, 2)
+	signature := hexutil.MustDecode(parts[1])
+	pubKey, err := crypto.SigToPub(accounts.TextHash([]byte(crypto.Keccak256Hash(body).Hex())), signature)
+	require.NoError(r.t, err)
+	assert.Equal(r.t, r.authAddr, crypto.PubkeyToAddress(*pubKey))
+	assert.Equal(r.t, r.authAddr.Hex(), parts[0])
+
+	var rpcReq stubRelayRequest
+	require.NoError(r.t, json.Unmarshal(body, &rpcReq))
+
+	r.mtx.Lock()
+	r.requests = append(r.requests, rpcReq)
+	received := len(r.requests)
+	r.mtx.Unlock()
+
+	if r.fail && received > r.accepted {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"relay unavailable"}}`))
+		return
+	}
+
+	_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x01"}}`))
+}
+
+func testPrivateRelayCommitter(
+	t *testing.T,
+	evmProvider *mocks.MockEVMProviderWithRet,
+	method PrivateRelayMethod,
+	fail bool,
+) (*ethCommitter, *stubRelay, func()) {
+	opts := defaultOptions()
+	require.NoError(t, applyOptions(opts, OptionPrivateRelay("", method, 3, time.Minute, nil)))
+
+	relay := &stubRelay{t: t, authAddr: crypto.PubkeyToAddress(opts.PrivateRelay.authKey.PublicKey), fail: fail}
+	server := httptest.NewServer(relay)
+
+	opts.PrivateRelay.endpoint = server.URL
+	opts.PrivateRelay.pollInterval = time.Millisecond
+
+	committer := &ethCommitter{
+		logger:        zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		committerOpts: opts,
+		evmProvider:   evmProvider,
+	}
+
+	return committer, relay, server.Close
+}
+
+func testSignedTx(t *testing.T) *types.Transaction {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	tx := types.NewTransaction(1, ethcmn.HexToAddress("0x01"), nil, 21000, big.NewInt(1), []byte{1})
+	signedTx, err := types.SignTx(tx, types.HomesteadSigner{}, key)
+	require.NoError(t, err)
+
+	return signedTx
+}
+
+func TestPrivateRelayBundleFallback(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	signedTx := testSignedTx(t)
+	rawTx, err := signedTx.MarshalBinary()
+	require.NoError(t, err)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(100)}, nil)
+
+	committer, relay, closeRelay := testPrivateRelayCommitter(t, evmProvider, PrivateRelayBundle, false)
+	defer closeRelay()
+
+	// not mined while the targeted blocks pass
+	evmProvider.EXPECT().TransactionReceipt(gomock.Any(), signedTx.Hash()).Return(nil, ethereum.NotFound).Times(2)
+	gomock.InOrder(
+		evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(103)}, nil),
+		evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(104)}, nil),
+	)
+
+	broadcasted := make(chan struct{})
+	evmProvider.EXPECT().
+		SendTransactionWithRet(gomock.Any(), signedTx).
+		DoAndReturn(func(context.Context, *types.Transaction) (ethcmn.Hash, error) {
+			close(broadcasted)
+			return signedTx.Hash(), nil
+		})
+
+	txHash, err := committer.sendTransaction(context.Background(), signedTx)
+	require.NoError(t, err)
+	assert.Equal(t, signedTx.Hash(), txHash)
+
+	// a bundle is sent for each of the targeted blocks
+	relay.mtx.Lock()
+	require.Len(t, relay.requests, 3)
+	for i, req := range relay.requests {
+		assert.Equal(t, "eth_sendBundle", req.Method)
+		assert.Equal(t, hexutil.EncodeUint64(uint64(101+i)), req.Params[0]["blockNumber"])
+		assert.Equal(t, []interface{}{hexutil.Encode(rawTx)}, req.Params[0]["txs"])
+	}
+	relay.mtx.Unlock()
+
+	select {
+	case <-broadcasted:
+	case <-time.After(time.Second):
+		t.Fatal("the tx wasn't broadcasted publicly")
+	}
+}
+
+func TestPrivateRelayBundlePartiallyAccepted(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	signedTx := testSignedTx(t)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(100)}, nil)
+
+	committer, relay, closeRelay := testPrivateRelayCommitter(t, evmProvider, PrivateRelayBundle, true)
+	defer closeRelay()
+
+	// the bundle is refused for the last block only
+	relay.accepted = 2
+
+	// mined in one of the blocks the bundle was accepted for, so it's never broadcasted publicly
+	mined := make(chan struct{})
+	evmProvider.EXPECT().
+		TransactionReceipt(gomock.Any(), signedTx.Hash()).
+		DoAndReturn(func(context.Context, ethcmn.Hash) (*types.Receipt, error) {
+			close(mined)
+			return &types.Receipt{}, nil
+		})
+
+	txHash, err := committer.sendTransaction(context.Background(), signedTx)
+	require.NoError(t, err)
+	assert.Equal(t, signedTx.Hash(), txHash)
+
+	relay.mtx.Lock()
+	assert.Len(t, relay.requests, 3)
+	relay.mtx.Unlock()
+
+	select {
+	case <-mined:
+	case <-time.After(time.Second):
+		t.Fatal("the private tx wasn't watched")
+	}
+}
+
+func TestPrivateRelayPrivateTxMined(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	signedTx := testSignedTx(t)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(100)}, nil)
+
+	committer, relay, closeRelay := testPrivateRelayCommitter(t, evmProvider, PrivateRelayPrivateTx, false)
+	defer closeRelay()
+
+	// mined, so it's never broadcasted
+	mined := make(chan struct{})
+	evmProvider.EXPECT().
+		TransactionReceipt(gomock.Any(), signedTx.Hash()).
+		DoAndReturn(func(context.Context, ethcmn.Hash) (*types.Receipt, error) {
+			close(mined)
+			return &types.Receipt{Status: types.ReceiptStatusSuccessful}, nil
+		})
+
+	_, err := committer.sendTransaction(context.Background(), signedTx)
+	require.NoError(t, err)
+
+	relay.mtx.Lock()
+	require.Len(t, relay.requests, 1)
+	assert.Equal(t, "eth_sendPrivateTransaction", relay.requests[0].Method)
+	assert.Equal(t, hexutil.EncodeUint64(103), relay.requests[0].Params[0]["maxBlockNumber"])
+	relay.mtx.Unlock()
+
+	select {
+	case <-mined:
+	case <-time.After(time.Second):
+		t.Fatal("the tx status wasn't checked")
+	}
+
+	// leave the watcher some time to return
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestPrivateRelayWatchStopped(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	signedTx := testSignedTx(t)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(100)}, nil).AnyTimes()
+
+	committer, _, closeRelay := testPrivateRelayCommitter(t, evmProvider, PrivateRelayPrivateTx, false)
+	defer closeRelay()
+
+	// not mined yet when the caller's context is canceled, so it's never broadcasted
+	watched := make(chan struct{})
+	var once sync.Once
+	evmProvider.EXPECT().
+		TransactionReceipt(gomock.Any(), signedTx.Hash()).
+		DoAndReturn(func(context.Context, ethcmn.Hash) (*types.Receipt, error) {
+			once.Do(func() { close(watched) })
+			return nil, ethereum.NotFound
+		}).
+		AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := committer.sendTransaction(ctx, signedTx)
+	require.NoError(t, err)
+
+	select {
+	case <-watched:
+	case <-time.After(time.Second):
+		t.Fatal("the private tx wasn't watched")
+	}
+
+	cancel()
+
+	// leave the watcher some time to return
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestPrivateRelayUnavailable(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	signedTx := testSignedTx(t)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{Number: big.NewInt(100)}, nil)
+
+	committer, _, closeRelay := testPrivateRelayCommitter(t, evmProvider, PrivateRelayPrivateTx, true)
+	defer closeRelay()
+
+	// broadcasted right away
+	evmProvider.EXPECT().SendTransactionWithRet(gomock.Any(), signedTx).Return(signedTx.Hash(), nil)
+
+	txHash, err := committer.sendTransaction(context.Background(), signedTx)
+	require.NoError(t, err)
+	assert.Equal(t, signedTx.Hash(), txHash)
+}
+
+func TestOptionPrivateRelay(t *testing.T) {
+	const endpoint = "http://relay"
+
+	assert.Error(t, applyOptions(defaultOptions(), OptionPrivateRelay(endpoint, "public", 3, time.Minute, nil)))
+	assert.Error(t, applyOptions(defaultOptions(), OptionPrivateRelay(endpoint, PrivateRelayBundle, 0, time.Minute, nil)))
+
+	opts := defaultOptions()
+	require.NoError(t, applyOptions(opts, OptionPrivateRelay(endpoint, PrivateRelayBundle, 1, time.Minute, nil)))
+	assert.NotNil(t, opts.PrivateRelay.authKey)
+
+	// the given auth key is kept, so the relay identifies the sender across restarts
+	authKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	opts = defaultOptions()
+	require.NoError(t, applyOptions(opts, OptionPrivateRelay(endpoint, PrivateRelayBundle, 1, time.Minute, authKey)))
+	assert.Equal(t, authKey, opts.PrivateRelay.authKey)
+}
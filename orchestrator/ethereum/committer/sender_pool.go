@@ -0,0 +1,301 @@
+package committer
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// Sender is an Ethereum account txs can be sent from.
+type Sender struct {
+	Address ethcmn.Address
+	Signer  bind.SignerFn
+}
+
+// OptionSenderPool adds accounts to send txs from, besides the committer's own. Every tx is sent from an idle account,
+// i.e. one without unmined txs, so a stuck tx only holds up the txs of its own account.
+func OptionSenderPool(senders ...Sender) EVMCommitterOption {
+	return func(o *options) error {
+		for _, sender := range senders {
+			if sender.Address == (ethcmn.Address{}) || sender.Signer == nil {
+				return errors.New("sender pool accounts need an address and a signer")
+			}
+		}
+
+		o.Senders = append(o.Senders, senders...)
+		return nil
+	}
+}
+
+// OptionLowBalanceWarning makes the committer warn when the ETH balance of an account it sent a tx from is below
+// minBalance (in wei), so it can be topped up.
+func OptionLowBalanceWarning(minBalance *big.Int) EVMCommitterOption {
+	return func(o *options) error {
+		if minBalance == nil || minBalance.Sign() < 0 {
+			return errors.New("invalid low balance threshold")
+		}
+
+		o.LowBalanceWarning = minBalance
+		return nil
+	}
+}
+
+// OptionBalanceReserve makes the committer refuse to send a tx if its maximum cost would leave the balance of the
+// account below reserve (in wei).
+func OptionBalanceReserve(reserve *big.Int) EVMCommitterOption {
+	return func(o *options) error {
+		if reserve == nil || reserve.Sign() < 0 {
+			return errors.New("invalid balance reserve")
+		}
+
+		o.BalanceReserve = reserve
+		return nil
+	}
+}
+
+// outOfFundsRetry is how long an account that ran out of funds is set aside before being tried again, in case it was
+// topped up.
+const outOfFundsRetry = 10 * time.Minute
+
+type pooledSender struct {
+	Sender
+
+	// users is the number of SendTx calls currently using the account.
+	users int
+	// outOfFundsAt is when a tx from the account was last rejected for insufficient funds, zero if it never was.
+	outOfFundsAt time.Time
+}
+
+// senderPool assigns the accounts txs are sent from.
+type senderPool struct {
+	mtx     sync.Mutex
+	senders []*pooledSender
+	// next is where the search for an idle account starts, so they're used in turns.
+	next int
+
+	now func() time.Time
+}
+
+func newSenderPool(senders ...Sender) *senderPool {
+	p := &senderPool{now: time.Now}
+	for _, sender := range senders {
+		// the committer's own account may also be listed in the pool
+		if p.find(sender.Address) == nil {
+			p.senders = append(p.senders, &pooledSender{Sender: sender})
+		}
+	}
+
+	return p
+}
+
+func (p *senderPool) find(address ethcmn.Address) *pooledSender {
+	for _, sender := range p.senders {
+		if sender.Address == address {
+			return sender
+		}
+	}
+
+	return nil
+}
+
+// hasFunds returns false if the account was out of funds less than outOfFundsRetry ago.
+func (p *senderPool) hasFunds(sender *pooledSender) bool {
+	return sender.outOfFundsAt.IsZero() || p.now().Sub(sender.outOfFundsAt) >= outOfFundsRetry
+}
+
+// outOfFunds sets the account aside as it ran out of funds. It returns true if there are other accounts to send txs
+// from.
+func (p *senderPool) outOfFunds(address ethcmn.Address) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if s := p.find(address); s != nil {
+		s.outOfFundsAt = p.now()
+	}
+
+	for _, sender := range p.senders {
+		if p.hasFunds(sender) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// idle returns the accounts that aren't being used, the ones whose unmined txs acquire needs to know about. It's empty
+// when there's a single account, as there's nothing to choose from.
+func (p *senderPool) idle() []ethcmn.Address {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if len(p.senders) == 1 {
+		return nil
+	}
+
+	var idle []ethcmn.Address
+	for _, sender := range p.senders {
+		if sender.users == 0 && p.hasFunds(sender) {
+			idle = append(idle, sender.Address)
+		}
+	}
+
+	return idle
+}
+
+// acquire returns the account the next tx should be sent from: the first one that isn't being used and has no unmined
+// txs, or else the one with the fewest unmined txs. queuedTxs holds the number of unmined txs of the accounts returned
+// by idle, fetched without holding the pool so concurrent txs don't wait on each other's RPCs. The accounts out of
+// funds are skipped, ErrInsufficientFunds is returned if every one of them is. It must be released once the tx is sent.
+func (p *senderPool) acquire(queuedTxs map[ethcmn.Address]int64) (Sender, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	var (
+		best       = -1
+		bestQueued = int64(math.MaxInt64)
+		// fallback is the first account with funds in turn, used if every account is busy
+		fallback = -1
+	)
+
+	for i := 0; i < len(p.senders); i++ {
+		idx := (p.next + i) % len(p.senders)
+		if !p.hasFunds(p.senders[idx]) {
+			continue
+		}
+
+		if fallback < 0 {
+			fallback = idx
+		}
+
+		if p.senders[idx].users > 0 {
+			continue
+		}
+
+		// an account released since idle was called has an unknown number of unmined txs
+		queued, ok := queuedTxs[p.senders[idx].Address]
+		if !ok {
+			queued = math.MaxInt64 - 1
+		}
+
+		if queued < bestQueued {
+			best, bestQueued = idx, queued
+		}
+
+		if bestQueued == 0 {
+			break
+		}
+	}
+
+	if fallback < 0 {
+		return Sender{}, errors.Wrap(ErrInsufficientFunds, "every sender account is out of funds")
+	}
+
+	// every account is busy, the tx waits for the next one in turn
+	if best < 0 {
+		best = fallback
+	}
+
+	p.next = (best + 1) % len(p.senders)
+	p.senders[best].users++
+
+	return p.senders[best].Sender, nil
+}
+
+func (p *senderPool) release(sender Sender) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if s := p.find(sender.Address); s != nil && s.users > 0 {
+		s.users--
+	}
+}
+
+// SenderAddresses returns all the accounts txs are sent from, the committer's own first.
+func (e *ethCommitter) SenderAddresses() []ethcmn.Address {
+	addresses := make([]ethcmn.Address, len(e.senders.senders))
+	for i, sender := range e.senders.senders {
+		addresses[i] = sender.Address
+	}
+
+	return addresses
+}
+
+// acquireSender returns the account the next tx should be sent from, see senderPool.acquire.
+func (e *ethCommitter) acquireSender(ctx context.Context) (Sender, error) {
+	idle := e.senders.idle()
+
+	queuedTxs := make(map[ethcmn.Address]int64, len(idle))
+	for _, address := range idle {
+		queuedTxs[address] = e.queuedTxs(ctx, address)
+	}
+
+	return e.senders.acquire(queuedTxs)
+}
+
+// queuedTxs returns the number of txs sent from the account that weren't mined yet. If it can't be told, the account
+// is considered to have too many.
+func (e *ethCommitter) queuedTxs(ctx context.Context, address ethcmn.Address) int64 {
+	minedNonce, err := e.evmProvider.NonceAt(ctx, address, nil)
+	if err != nil {
+		e.logger.Err(err).Str("sender", address.Hex()).Msg("failed to get sender nonce")
+		return math.MaxInt64 - 1
+	}
+
+	nonce, _ := e.nonceCache.Get(address)
+	if queued := nonce - int64(minedNonce); queued > 0 {
+		return queued
+	}
+
+	return 0
+}
+
+// checkBalanceReserve returns ErrBalanceReserve if sending a tx costing up to cost would leave the account balance
+// below the reserve.
+func (e *ethCommitter) checkBalanceReserve(ctx context.Context, address ethcmn.Address, cost *big.Int) error {
+	reserve := e.committerOpts.BalanceReserve
+	if reserve == nil {
+		return nil
+	}
+
+	balance, err := e.evmProvider.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to get sender balance")
+	}
+
+	if new(big.Int).Sub(balance, cost).Cmp(reserve) < 0 {
+		return errors.Wrapf(
+			ErrBalanceReserve,
+			"balance %s of %s can't pay %s and keep %s", balance, address.Hex(), cost, reserve,
+		)
+	}
+
+	return nil
+}
+
+// checkSenderBalance warns if the account balance is below the low balance threshold.
+func (e *ethCommitter) checkSenderBalance(ctx context.Context, address ethcmn.Address) {
+	minBalance := e.committerOpts.LowBalanceWarning
+	if minBalance == nil {
+		return
+	}
+
+	balance, err := e.evmProvider.BalanceAt(ctx, address, nil)
+	if err != nil {
+		e.logger.Err(err).Str("sender", address.Hex()).Msg("failed to get sender balance")
+		return
+	}
+
+	if balance.Cmp(minBalance) < 0 {
+		e.logger.Warn().
+			Str("sender", address.Hex()).
+			Str("balance", balance.String()).
+			Str("min_balance", minBalance.String()).
+			Msg("sender account balance is low; top it up")
+	}
+}
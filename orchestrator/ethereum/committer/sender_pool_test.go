@@ -0,0 +1,259 @@
+package committer
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umee-network/peggo/mocks"
+)
+
+func testSender(t *testing.T) Sender {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	txOpts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1))
+	require.NoError(t, err)
+
+	return Sender{Address: txOpts.From, Signer: txOpts.Signer}
+}
+
+func mustAcquire(t *testing.T, pool *senderPool, queuedTxs map[ethcmn.Address]int64) Sender {
+	sender, err := pool.acquire(queuedTxs)
+	require.NoError(t, err)
+
+	return sender
+}
+
+func TestSenderPoolAcquire(t *testing.T) {
+	var (
+		senderA = Sender{Address: ethcmn.HexToAddress("0x0a")}
+		senderB = Sender{Address: ethcmn.HexToAddress("0x0b")}
+		senderC = Sender{Address: ethcmn.HexToAddress("0x0c")}
+	)
+
+	// the committer's own account listed again is ignored
+	pool := newSenderPool(senderA, senderB, senderC, senderA)
+	require.Len(t, pool.senders, 3)
+
+	queued := map[ethcmn.Address]int64{senderA.Address: 1, senderB.Address: 0, senderC.Address: 0}
+	queuedTxs := func() map[ethcmn.Address]int64 {
+		idle := map[ethcmn.Address]int64{}
+		for _, address := range pool.idle() {
+			idle[address] = queued[address]
+		}
+
+		return idle
+	}
+
+	// A has an unmined tx, B is idle
+	assert.Equal(t, senderB, mustAcquire(t, pool, queuedTxs()))
+	// B is in use
+	assert.Equal(t, senderC, mustAcquire(t, pool, queuedTxs()))
+	// only A isn't in use
+	assert.Equal(t, senderA, mustAcquire(t, pool, queuedTxs()))
+	// every account is in use, so they're used in turns
+	assert.Equal(t, senderB, mustAcquire(t, pool, queuedTxs()))
+
+	pool.release(senderB)
+	pool.release(senderB)
+
+	// C was released after its unmined txs were fetched, so it's only picked once nothing better is known
+	idle := queuedTxs()
+	pool.release(senderC)
+	assert.Equal(t, senderB, mustAcquire(t, pool, idle))
+	pool.release(senderB)
+
+	queued[senderA.Address] = 0
+	pool.release(senderA)
+
+	// idle accounts are used in turns
+	assert.Equal(t, senderC, mustAcquire(t, pool, queuedTxs()))
+	assert.Equal(t, senderA, mustAcquire(t, pool, queuedTxs()))
+	assert.Equal(t, senderB, mustAcquire(t, pool, queuedTxs()))
+}
+
+func TestSenderPoolOutOfFunds(t *testing.T) {
+	var (
+		senderA = Sender{Address: ethcmn.HexToAddress("0x0a")}
+		senderB = Sender{Address: ethcmn.HexToAddress("0x0b")}
+	)
+
+	now := time.Now()
+	pool := newSenderPool(senderA, senderB)
+	pool.now = func() time.Time { return now }
+
+	// B is left
+	assert.True(t, pool.outOfFunds(senderA.Address))
+	assert.Equal(t, []ethcmn.Address{senderB.Address}, pool.idle())
+	assert.Equal(t, senderB, mustAcquire(t, pool, nil))
+	// even when B is busy
+	assert.Equal(t, senderB, mustAcquire(t, pool, nil))
+
+	assert.False(t, pool.outOfFunds(senderB.Address))
+	_, err := pool.acquire(nil)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// the accounts may have been topped up
+	now = now.Add(outOfFundsRetry)
+	assert.Equal(t, []ethcmn.Address{senderA.Address}, pool.idle())
+	pool.release(senderB)
+	pool.release(senderB)
+	assert.Len(t, pool.idle(), 2)
+}
+
+func TestSendTxSenderPool(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		primary = testSender(t)
+		pooled  = testSender(t)
+	)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().PendingNonceAt(gomock.Any(), primary.Address).Return(uint64(5), nil)
+	evmProvider.EXPECT().PendingNonceAt(gomock.Any(), pooled.Address).Return(uint64(2), nil)
+
+	c, err := NewEthCommitter(
+		zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		primary.Address,
+		1,
+		1,
+		primary.Signer,
+		evmProvider,
+		OptionSenderPool(pooled),
+		OptionLowBalanceWarning(big.NewInt(1000)),
+	)
+	require.NoError(t, err)
+
+	// the primary account has a stuck tx
+	evmProvider.EXPECT().NonceAt(gomock.Any(), primary.Address, nil).Return(uint64(4), nil)
+	evmProvider.EXPECT().NonceAt(gomock.Any(), pooled.Address, nil).Return(uint64(2), nil)
+
+	var sentTx *types.Transaction
+	evmProvider.EXPECT().
+		SendTransactionWithRet(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tx *types.Transaction) (ethcmn.Hash, error) {
+			sentTx = tx
+			return tx.Hash(), nil
+		})
+	evmProvider.EXPECT().BalanceAt(gomock.Any(), pooled.Address, nil).Return(big.NewInt(999), nil)
+
+	txHash, err := c.SendTx(context.Background(), ethcmn.HexToAddress("0x01"), []byte{1}, 21000, big.NewInt(1))
+	require.NoError(t, err)
+	require.NotNil(t, sentTx)
+	assert.Equal(t, sentTx.Hash(), txHash)
+
+	from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1)), sentTx)
+	require.NoError(t, err)
+	assert.Equal(t, pooled.Address, from)
+	assert.Equal(t, uint64(2), sentTx.Nonce())
+}
+
+func TestOptionSenderPool(t *testing.T) {
+	assert.Error(t, applyOptions(defaultOptions(), OptionSenderPool(Sender{Address: ethcmn.HexToAddress("0x0a")})))
+	assert.NoError(t, applyOptions(defaultOptions(), OptionSenderPool(testSender(t))))
+	assert.Error(t, applyOptions(defaultOptions(), OptionLowBalanceWarning(big.NewInt(-1))))
+}
+
+func TestSendTxInsufficientFunds(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sender := testSender(t)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().PendingNonceAt(gomock.Any(), sender.Address).Return(uint64(0), nil)
+
+	c, err := NewEthCommitter(
+		zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		sender.Address,
+		1,
+		1,
+		sender.Signer,
+		evmProvider,
+		OptionBalanceReserve(big.NewInt(100)),
+	)
+	require.NoError(t, err)
+
+	// 150 - 100 * 1 would be below the reserve
+	evmProvider.EXPECT().BalanceAt(gomock.Any(), sender.Address, nil).Return(big.NewInt(150), nil)
+
+	_, err = c.SendTx(context.Background(), ethcmn.HexToAddress("0x01"), []byte{1}, 100, big.NewInt(1))
+	assert.ErrorIs(t, err, ErrBalanceReserve)
+
+	// the node disagrees with the balance
+	evmProvider.EXPECT().BalanceAt(gomock.Any(), sender.Address, nil).Return(big.NewInt(200), nil)
+	evmProvider.EXPECT().
+		SendTransactionWithRet(gomock.Any(), gomock.Any()).
+		Return(ethcmn.Hash{}, errors.New("insufficient funds for gas * price + value"))
+
+	_, err = c.SendTx(context.Background(), ethcmn.HexToAddress("0x01"), []byte{1}, 100, big.NewInt(1))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestSendTxOutOfFundsSender(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		primary = testSender(t)
+		pooled  = testSender(t)
+	)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().PendingNonceAt(gomock.Any(), primary.Address).Return(uint64(0), nil)
+	evmProvider.EXPECT().PendingNonceAt(gomock.Any(), pooled.Address).Return(uint64(0), nil)
+	evmProvider.EXPECT().NonceAt(gomock.Any(), gomock.Any(), nil).Return(uint64(0), nil).AnyTimes()
+
+	c, err := NewEthCommitter(
+		zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		primary.Address,
+		1,
+		1,
+		primary.Signer,
+		evmProvider,
+		OptionSenderPool(pooled),
+	)
+	require.NoError(t, err)
+
+	// the primary account is out of funds, the tx is sent from the pooled one
+	evmProvider.EXPECT().
+		SendTransactionWithRet(gomock.Any(), gomock.Any()).
+		Return(ethcmn.Hash{}, errors.New("insufficient funds for gas * price + value"))
+	evmProvider.EXPECT().
+		SendTransactionWithRet(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tx *types.Transaction) (ethcmn.Hash, error) {
+			from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1)), tx)
+			require.NoError(t, err)
+			assert.Equal(t, pooled.Address, from)
+
+			return tx.Hash(), nil
+		})
+
+	_, err = c.SendTx(context.Background(), ethcmn.HexToAddress("0x01"), []byte{1}, 100, big.NewInt(1))
+	require.NoError(t, err)
+
+	// once every account is out of funds, the relayer is told
+	evmProvider.EXPECT().
+		SendTransactionWithRet(gomock.Any(), gomock.Any()).
+		Return(ethcmn.Hash{}, errors.New("insufficient funds for gas * price + value"))
+
+	_, err = c.SendTx(context.Background(), ethcmn.HexToAddress("0x01"), []byte{1}, 100, big.NewInt(1))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = c.SendTx(context.Background(), ethcmn.HexToAddress("0x01"), []byte{1}, 100, big.NewInt(1))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
@@ -0,0 +1,277 @@
+package committer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrSpendLimit is returned when a tx isn't sent because it's above the gas price ceiling or the maximum cost of a tx,
+// or because it doesn't fit in the spend budgets. It's meant to be sent later, once the gas price is lower or the
+// budget was freed.
+var ErrSpendLimit = errors.New("tx deferred by the spend limits")
+
+// ErrGasPriceCeiling is the ErrSpendLimit returned when a tx isn't sent because its gas price is above the max gas
+// price or the multiple of the base fee, so any other tx at that gas price would be deferred too.
+var ErrGasPriceCeiling = errors.Wrap(ErrSpendLimit, "gas price above the ceiling")
+
+const (
+	hourlyBudgetWindow = time.Hour
+	dailyBudgetWindow  = 24 * time.Hour
+)
+
+// OptionMaxGasPrice makes the committer defer the txs whose gas price is above maxGasPrice (in wei).
+func OptionMaxGasPrice(maxGasPrice *big.Int) EVMCommitterOption {
+	return func(o *options) error {
+		if maxGasPrice == nil || maxGasPrice.Sign() <= 0 {
+			return errors.New("invalid max gas price")
+		}
+
+		o.MaxGasPrice = maxGasPrice
+		return nil
+	}
+}
+
+// OptionMaxBaseFeeMultiplier makes the committer defer the txs whose gas price is above multiplier times the base fee
+// of the latest block.
+func OptionMaxBaseFeeMultiplier(multiplier float64) EVMCommitterOption {
+	return func(o *options) error {
+		if multiplier < 1 {
+			return errors.Errorf("base fee multiplier must be at least 1, got %f", multiplier)
+		}
+
+		o.MaxBaseFeeMultiplier = multiplier
+		return nil
+	}
+}
+
+// OptionMaxTxCost makes the committer defer the txs whose maximum cost, i.e. gas limit times gas price, is above
+// maxCost (in wei).
+func OptionMaxTxCost(maxCost *big.Int) EVMCommitterOption {
+	return func(o *options) error {
+		if maxCost == nil || maxCost.Sign() <= 0 {
+			return errors.New("invalid max tx cost")
+		}
+
+		o.MaxTxCost = maxCost
+		return nil
+	}
+}
+
+// OptionSpendBudget makes the committer defer the txs that would take the ETH spent in the last hour or day above
+// hourly or daily (in wei), a nil budget having no limit. The spending is kept in stateFile so the budgets hold across
+// restarts.
+func OptionSpendBudget(hourly, daily *big.Int, stateFile string) EVMCommitterOption {
+	return func(o *options) error {
+		for _, budget := range []*big.Int{hourly, daily} {
+			if budget != nil && budget.Sign() <= 0 {
+				return errors.New("invalid spend budget")
+			}
+		}
+
+		if stateFile == "" {
+			return errors.New("the spend budget needs a state file")
+		}
+
+		budget, err := loadSpendBudget(hourly, daily, stateFile)
+		if err != nil {
+			return err
+		}
+
+		o.SpendBudget = budget
+		return nil
+	}
+}
+
+// checkGasPrice returns ErrSpendLimit if the tx is above the gas price ceilings or the maximum cost of a tx.
+func (e *ethCommitter) checkGasPrice(ctx context.Context, gasPrice, cost *big.Int) error {
+	opts := e.committerOpts
+
+	if opts.MaxGasPrice != nil && gasPrice.Cmp(opts.MaxGasPrice) > 0 {
+		return errors.Wrapf(ErrGasPriceCeiling, "gas price %s is above the max of %s", gasPrice, opts.MaxGasPrice)
+	}
+
+	if opts.MaxTxCost != nil && cost.Cmp(opts.MaxTxCost) > 0 {
+		return errors.Wrapf(ErrSpendLimit, "tx cost %s is above the max of %s", cost, opts.MaxTxCost)
+	}
+
+	if opts.MaxBaseFeeMultiplier > 0 {
+		header, err := e.evmProvider.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to get latest header")
+		}
+
+		// there's no base fee before London
+		if header.BaseFee == nil {
+			return nil
+		}
+
+		maxGasPrice, _ := new(big.Float).Mul(
+			new(big.Float).SetInt(header.BaseFee),
+			big.NewFloat(opts.MaxBaseFeeMultiplier),
+		).Int(nil)
+
+		if gasPrice.Cmp(maxGasPrice) > 0 {
+			return errors.Wrapf(
+				ErrGasPriceCeiling,
+				"gas price %s is above %.2f times the base fee of %s", gasPrice, opts.MaxBaseFeeMultiplier, header.BaseFee,
+			)
+		}
+	}
+
+	return nil
+}
+
+// spendRecord is the maximum cost of a tx that was sent.
+type spendRecord struct {
+	Time time.Time `json:"time"`
+	Cost *big.Int  `json:"cost"`
+}
+
+// spendBudget keeps track of the ETH spent over the last day to enforce the hourly and daily budgets. The txs are
+// counted at their maximum cost, as what they actually cost is only known once they're mined.
+type spendBudget struct {
+	mtx       sync.Mutex
+	hourly    *big.Int
+	daily     *big.Int
+	stateFile string
+	spends    []*spendRecord
+
+	now func() time.Time
+}
+
+func loadSpendBudget(hourly, daily *big.Int, stateFile string) (*spendBudget, error) {
+	b := &spendBudget{
+		hourly:    hourly,
+		daily:     daily,
+		stateFile: stateFile,
+		now:       time.Now,
+	}
+
+	bz, err := ioutil.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the spend state file")
+	}
+
+	if err := json.Unmarshal(bz, &b.spends); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the spend state file %s", stateFile)
+	}
+
+	return b, nil
+}
+
+// reserve counts cost against the budgets, returning ErrSpendLimit if it doesn't fit in them. The returned record must
+// be canceled if the tx isn't sent after all.
+func (b *spendBudget) reserve(cost *big.Int) (*spendRecord, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := b.now()
+	b.prune(now)
+
+	for _, limit := range []struct {
+		name   string
+		window time.Duration
+		budget *big.Int
+	}{
+		{"hourly", hourlyBudgetWindow, b.hourly},
+		{"daily", dailyBudgetWindow, b.daily},
+	} {
+		if limit.budget == nil {
+			continue
+		}
+
+		spent := b.spentSince(now.Add(-limit.window))
+		if new(big.Int).Add(spent, cost).Cmp(limit.budget) > 0 {
+			return nil, errors.Wrapf(
+				ErrSpendLimit,
+				"tx cost %s doesn't fit in the %s budget of %s, %s spent already", cost, limit.name, limit.budget, spent,
+			)
+		}
+	}
+
+	record := &spendRecord{Time: now, Cost: cost}
+	b.spends = append(b.spends, record)
+
+	// the budgets couldn't hold across restarts
+	if err := b.save(); err != nil {
+		b.spends = b.spends[:len(b.spends)-1]
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// cancel removes the record of a tx that wasn't sent.
+func (b *spendBudget) cancel(record *spendRecord) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for i, r := range b.spends {
+		if r == record {
+			b.spends = append(b.spends[:i], b.spends[i+1:]...)
+			return b.save()
+		}
+	}
+
+	return nil
+}
+
+// prune forgets the spends older than the daily window.
+func (b *spendBudget) prune(now time.Time) {
+	cutoff := now.Add(-dailyBudgetWindow)
+
+	i := 0
+	for i < len(b.spends) && !b.spends[i].Time.After(cutoff) {
+		i++
+	}
+
+	b.spends = b.spends[i:]
+}
+
+func (b *spendBudget) spentSince(since time.Time) *big.Int {
+	spent := new(big.Int)
+	for _, r := range b.spends {
+		if r.Time.After(since) {
+			spent.Add(spent, r.Cost)
+		}
+	}
+
+	return spent
+}
+
+// save writes the spends to the state file, going through a temporary file so it's never left half written.
+func (b *spendBudget) save() error {
+	bz, err := json.Marshal(b.spends)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the spend state")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(b.stateFile), filepath.Base(b.stateFile)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to write the spend state file")
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to write the spend state file")
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the spend state file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), b.stateFile), "failed to write the spend state file")
+}
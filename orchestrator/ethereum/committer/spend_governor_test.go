@@ -0,0 +1,135 @@
+package committer
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umee-network/peggo/mocks"
+)
+
+func TestSpendBudget(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "spend.json")
+
+	budget, err := loadSpendBudget(big.NewInt(100), big.NewInt(150), stateFile)
+	require.NoError(t, err)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	budget.now = func() time.Time { return now }
+
+	_, err = budget.reserve(big.NewInt(60))
+	require.NoError(t, err)
+
+	// above the hourly budget
+	_, err = budget.reserve(big.NewInt(50))
+	assert.ErrorIs(t, err, ErrSpendLimit)
+
+	spend, err := budget.reserve(big.NewInt(40))
+	require.NoError(t, err)
+
+	// the tx wasn't sent after all
+	require.NoError(t, budget.cancel(spend))
+
+	now = now.Add(time.Hour)
+
+	_, err = budget.reserve(big.NewInt(80))
+	require.NoError(t, err)
+
+	// the hourly budget was freed, but the daily one wasn't
+	now = now.Add(time.Hour)
+	_, err = budget.reserve(big.NewInt(20))
+	assert.ErrorIs(t, err, ErrSpendLimit)
+
+	// the spending holds across restarts
+	budget, err = loadSpendBudget(big.NewInt(100), big.NewInt(150), stateFile)
+	require.NoError(t, err)
+	budget.now = func() time.Time { return now }
+
+	_, err = budget.reserve(big.NewInt(20))
+	assert.ErrorIs(t, err, ErrSpendLimit)
+
+	_, err = budget.reserve(big.NewInt(10))
+	require.NoError(t, err)
+
+	// a day after the first tx
+	now = now.Add(22 * time.Hour)
+	_, err = budget.reserve(big.NewInt(60))
+	require.NoError(t, err)
+	assert.Len(t, budget.spends, 3)
+}
+
+func TestLoadSpendBudget(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "spend.json")
+	require.NoError(t, os.WriteFile(stateFile, []byte("{"), 0o600))
+
+	_, err := loadSpendBudget(nil, big.NewInt(1), stateFile)
+	assert.Error(t, err)
+
+	assert.Error(t, applyOptions(defaultOptions(), OptionSpendBudget(nil, big.NewInt(1), "")))
+	assert.Error(t, applyOptions(defaultOptions(), OptionSpendBudget(big.NewInt(0), nil, stateFile)))
+}
+
+func TestSendTxSpendLimits(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sender := testSender(t)
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().PendingNonceAt(gomock.Any(), sender.Address).Return(uint64(0), nil)
+
+	c, err := NewEthCommitter(
+		zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		sender.Address,
+		1,
+		1,
+		sender.Signer,
+		evmProvider,
+		OptionMaxGasPrice(big.NewInt(50)),
+		OptionMaxBaseFeeMultiplier(2),
+		OptionMaxTxCost(big.NewInt(3000)),
+		OptionSpendBudget(nil, big.NewInt(5000), filepath.Join(t.TempDir(), "spend.json")),
+	)
+	require.NoError(t, err)
+
+	recipient := ethcmn.HexToAddress("0x01")
+
+	// above the gas price ceiling
+	_, err = c.SendTx(context.Background(), recipient, []byte{1}, 10, big.NewInt(60))
+	assert.ErrorIs(t, err, ErrGasPriceCeiling)
+
+	// above the maximum cost of a tx
+	_, err = c.SendTx(context.Background(), recipient, []byte{1}, 100, big.NewInt(40))
+	assert.ErrorIs(t, err, ErrSpendLimit)
+	assert.NotErrorIs(t, err, ErrGasPriceCeiling)
+
+	// above twice the base fee
+	evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{BaseFee: big.NewInt(10)}, nil)
+	_, err = c.SendTx(context.Background(), recipient, []byte{1}, 10, big.NewInt(30))
+	assert.ErrorIs(t, err, ErrGasPriceCeiling)
+
+	evmProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&types.Header{BaseFee: big.NewInt(20)}, nil).Times(3)
+	evmProvider.EXPECT().
+		SendTransactionWithRet(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tx *types.Transaction) (ethcmn.Hash, error) {
+			return tx.Hash(), nil
+		}).Times(2)
+
+	_, err = c.SendTx(context.Background(), recipient, []byte{1}, 100, big.NewInt(25))
+	require.NoError(t, err)
+	_, err = c.SendTx(context.Background(), recipient, []byte{1}, 100, big.NewInt(25))
+	require.NoError(t, err)
+
+	// the daily budget is spent
+	_, err = c.SendTx(context.Background(), recipient, []byte{1}, 100, big.NewInt(25))
+	assert.ErrorIs(t, err, ErrSpendLimit)
+}
@@ -0,0 +1,66 @@
+package gasprice
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
+)
+
+// Urgency is how soon a tx needs to be mined, the more urgent the higher the gas price.
+type Urgency int
+
+const (
+	// UrgencyNormal is for txs that can wait a few blocks, e.g. batches far from their timeout.
+	UrgencyNormal Urgency = iota
+	// UrgencyHigh is for txs that should be mined in the next blocks, e.g. valset updates and batches about to time
+	// out.
+	UrgencyHigh
+)
+
+func (u Urgency) String() string {
+	switch u {
+	case UrgencyNormal:
+		return "normal"
+	case UrgencyHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// Estimator returns the gas price txs are sent at.
+type Estimator interface {
+	GasPrice(ctx context.Context, urgency Urgency) (*big.Int, error)
+}
+
+type adjustedEstimator struct {
+	provider   provider.EVMProvider
+	adjustment float64
+}
+
+// NewAdjustedEstimator returns an Estimator multiplying the gas price suggested by the node by adjustment, whatever
+// the urgency.
+func NewAdjustedEstimator(evmProvider provider.EVMProvider, adjustment float64) Estimator {
+	return &adjustedEstimator{
+		provider:   evmProvider,
+		adjustment: adjustment,
+	}
+}
+
+func (e *adjustedEstimator) GasPrice(ctx context.Context, _ Urgency) (*big.Int, error) {
+	suggestedGasPrice, err := e.provider.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, errors.Errorf("failed to suggest gas price: %v", err)
+	}
+
+	// Suggested gas price may not be accurate, so we multiply the result by the gas price adjustment factor.
+	gasPrice, _ := new(big.Float).Mul(
+		new(big.Float).SetInt(suggestedGasPrice),
+		big.NewFloat(e.adjustment),
+	).Int(nil)
+
+	return gasPrice, nil
+}
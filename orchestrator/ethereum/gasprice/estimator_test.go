@@ -0,0 +1,76 @@
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umee-network/peggo/mocks"
+	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
+)
+
+func TestAdjustedEstimator(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().SuggestGasPrice(gomock.Any()).Return(big.NewInt(100), nil).Times(2)
+
+	estimator := NewAdjustedEstimator(evmProvider, 1.3)
+
+	// the urgency is ignored
+	for _, urgency := range []Urgency{UrgencyNormal, UrgencyHigh} {
+		gasPrice, err := estimator.GasPrice(context.Background(), urgency)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(130), gasPrice)
+	}
+}
+
+func TestFeeHistoryEstimator(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+
+	_, err := NewFeeHistoryEstimator(evmProvider, 0, 50, 90)
+	assert.Error(t, err)
+	_, err = NewFeeHistoryEstimator(evmProvider, 10, 50, 101)
+	assert.Error(t, err)
+	_, err = NewFeeHistoryEstimator(evmProvider, 10, 90, 50)
+	assert.Error(t, err)
+
+	estimator, err := NewFeeHistoryEstimator(evmProvider, 3, 50, 90)
+	require.NoError(t, err)
+
+	evmProvider.EXPECT().FeeHistory(gomock.Any(), uint64(3), nil, []float64{50}).Return(&provider.FeeHistory{
+		OldestBlock: big.NewInt(100),
+		Reward:      [][]*big.Int{{big.NewInt(10)}, {big.NewInt(20)}, {big.NewInt(30)}},
+		BaseFee:     []*big.Int{big.NewInt(900), big.NewInt(950), big.NewInt(1000), big.NewInt(1000)},
+	}, nil)
+
+	// 1000 * 1.125 + 20
+	gasPrice, err := estimator.GasPrice(context.Background(), UrgencyNormal)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1145), gasPrice)
+
+	evmProvider.EXPECT().FeeHistory(gomock.Any(), uint64(3), nil, []float64{90}).Return(&provider.FeeHistory{
+		OldestBlock: big.NewInt(100),
+		Reward:      [][]*big.Int{{big.NewInt(40)}, {big.NewInt(50)}, {big.NewInt(60)}},
+		BaseFee:     []*big.Int{big.NewInt(900), big.NewInt(950), big.NewInt(1000), big.NewInt(1000)},
+	}, nil)
+
+	// 1000 * 1.125^2 + 50
+	gasPrice, err = estimator.GasPrice(context.Background(), UrgencyHigh)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1315), gasPrice)
+
+	evmProvider.EXPECT().FeeHistory(gomock.Any(), uint64(3), nil, []float64{50}).Return(&provider.FeeHistory{
+		OldestBlock: big.NewInt(100),
+	}, nil)
+
+	_, err = estimator.GasPrice(context.Background(), UrgencyNormal)
+	assert.EqualError(t, err, "empty fee history")
+}
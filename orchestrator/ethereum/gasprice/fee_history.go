@@ -0,0 +1,90 @@
+package gasprice
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
+)
+
+// baseFeeMaxChange is how much the base fee can go up from a block to the next one, 12.5% (EIP-1559).
+var baseFeeMaxChange = big.NewFloat(1.125)
+
+type feeHistoryEstimator struct {
+	provider    provider.EVMProvider
+	blocks      uint64
+	percentiles map[Urgency]float64
+}
+
+// NewFeeHistoryEstimator returns an Estimator basing the gas price on the fees paid over the last blocks, through
+// eth_feeHistory. The priority fee is the average over those blocks of the fees paid at normalPercentile, or
+// highPercentile for urgent txs, so it follows the market instead of a fixed factor. It's added to the base fee of the
+// next block, with room for the base fee to go up for one block, or two for urgent txs.
+func NewFeeHistoryEstimator(
+	evmProvider provider.EVMProvider,
+	blocks uint64,
+	normalPercentile float64,
+	highPercentile float64,
+) (Estimator, error) {
+	if blocks == 0 {
+		return nil, errors.New("the fee history needs at least one block")
+	}
+
+	for _, percentile := range []float64{normalPercentile, highPercentile} {
+		if percentile < 0 || percentile > 100 {
+			return nil, errors.Errorf("invalid fee history percentile: %f", percentile)
+		}
+	}
+
+	if highPercentile < normalPercentile {
+		return nil, errors.New("the high urgency percentile can't be below the normal one")
+	}
+
+	return &feeHistoryEstimator{
+		provider: evmProvider,
+		blocks:   blocks,
+		percentiles: map[Urgency]float64{
+			UrgencyNormal: normalPercentile,
+			UrgencyHigh:   highPercentile,
+		},
+	}, nil
+}
+
+func (e *feeHistoryEstimator) GasPrice(ctx context.Context, urgency Urgency) (*big.Int, error) {
+	percentile, ok := e.percentiles[urgency]
+	if !ok {
+		return nil, errors.Errorf("unknown urgency: %s", urgency)
+	}
+
+	history, err := e.provider.FeeHistory(ctx, e.blocks, nil, []float64{percentile})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get fee history")
+	}
+
+	// the last base fee is the one of the next block
+	if len(history.BaseFee) == 0 || len(history.Reward) == 0 {
+		return nil, errors.New("empty fee history")
+	}
+
+	tip := new(big.Int)
+	for _, rewards := range history.Reward {
+		if len(rewards) == 0 {
+			return nil, errors.New("fee history without rewards")
+		}
+
+		tip.Add(tip, rewards[0])
+	}
+
+	tip.Div(tip, big.NewInt(int64(len(history.Reward))))
+
+	baseFee := new(big.Float).SetInt(history.BaseFee[len(history.BaseFee)-1])
+	for i := UrgencyNormal; i <= urgency; i++ {
+		baseFee.Mul(baseFee, baseFeeMaxChange)
+	}
+
+	gasPrice, _ := baseFee.Int(nil)
+
+	return gasPrice.Add(gasPrice, tip), nil
+}
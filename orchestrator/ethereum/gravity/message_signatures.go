@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -126,3 +127,44 @@ func EncodeTxBatchConfirm(gravityID string, batch types.OutgoingTxBatch) ethcmn.
 	hash := crypto.Keccak256Hash(abiEncodedBatch[4:])
 	return hash
 }
+
+// ValidValsetConfirms returns the confirms of valset whose signature over its checkpoint was made by the Ethereum
+// address they were sent by, the only ones the contract accepts.
+func ValidValsetConfirms(
+	gravityID string,
+	valset types.Valset,
+	confirms []types.MsgValsetConfirm,
+) []types.MsgValsetConfirm {
+	checkpoint := EncodeValsetConfirm(gravityID, valset)
+
+	valid := make([]types.MsgValsetConfirm, 0, len(confirms))
+	for _, c := range confirms {
+		if verifyConfirmSig(checkpoint, c.Signature, ethcmn.HexToAddress(c.EthAddress)) {
+			valid = append(valid, c)
+		}
+	}
+
+	return valid
+}
+
+// verifyConfirmSig returns true if the signature over the confirm checkpoint was made by the given Ethereum address.
+// Orchestrators sign the checkpoint as a personal message, so that's what the signer is recovered from.
+func verifyConfirmSig(checkpoint ethcmn.Hash, sigHex string, ethSigner ethcmn.Address) bool {
+	sig := ethcmn.FromHex(sigHex)
+	if len(sig) != crypto.SignatureLength {
+		return false
+	}
+
+	// the recovery id may come in the Ethereum format (27 or 28)
+	sig = append([]byte{}, sig...)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(accounts.TextHash(checkpoint.Bytes()), sig)
+	if err != nil {
+		return false
+	}
+
+	return crypto.PubkeyToAddress(*pubKey) == ethSigner
+}
@@ -1,13 +1,38 @@
 package gravity
 
 import (
+	"crypto/ecdsa"
 	"testing"
 
 	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// testEthKey returns a deterministic Ethereum key for tests.
+func testEthKey(t *testing.T, i byte) *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(ethcmn.LeftPadBytes([]byte{i}, 32))
+	require.NoError(t, err)
+
+	return key
+}
+
+func testEthAddress(t *testing.T, i byte) string {
+	return crypto.PubkeyToAddress(testEthKey(t, i).PublicKey).Hex()
+}
+
+// signConfirm signs the checkpoint the same way orchestrators do.
+func signConfirm(t *testing.T, key *ecdsa.PrivateKey, checkpoint ethcmn.Hash) string {
+	sig, err := crypto.Sign(accounts.TextHash(checkpoint.Bytes()), key)
+	require.NoError(t, err)
+
+	return ethcmn.Bytes2Hex(sig)
+}
+
 func TestEncodeValsetConfirm(t *testing.T) {
 	gravityID := "defaultgravityid"
 
@@ -52,3 +77,28 @@ func TestEncodeTxBatchConfirm(t *testing.T) {
 	// Check the result with a previously calculated one.
 	assert.Equal(t, "0xf78189166c4bf48863f7765ba1b29afe15c45c0e48b2fbdeaf43b15ed09c138c", result.Hex())
 }
+
+func TestVerifyConfirmSig(t *testing.T) {
+	checkpoint := EncodeTxBatchConfirm("defaultgravityid", types.OutgoingTxBatch{BatchNonce: 1})
+	signer := ethcmn.HexToAddress(testEthAddress(t, 1))
+	sig := signConfirm(t, testEthKey(t, 1), checkpoint)
+
+	assert.True(t, verifyConfirmSig(checkpoint, sig, signer))
+	assert.True(t, verifyConfirmSig(checkpoint, "0x"+sig, signer))
+
+	// same signature with the recovery id in the Ethereum format
+	sigBytes := ethcmn.FromHex(sig)
+	sigBytes[64] += 27
+	assert.True(t, verifyConfirmSig(checkpoint, ethcmn.Bytes2Hex(sigBytes), signer))
+
+	// signed by someone else
+	assert.False(t, verifyConfirmSig(checkpoint, signConfirm(t, testEthKey(t, 2), checkpoint), signer))
+
+	// signed over another checkpoint
+	otherCheckpoint := EncodeTxBatchConfirm("defaultgravityid", types.OutgoingTxBatch{BatchNonce: 2})
+	assert.False(t, verifyConfirmSig(otherCheckpoint, sig, signer))
+
+	// malformed
+	assert.False(t, verifyConfirmSig(checkpoint, "0xaae54ee7", signer))
+	assert.False(t, verifyConfirmSig(checkpoint, "", signer))
+}
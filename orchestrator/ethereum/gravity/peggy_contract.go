@@ -70,6 +70,14 @@ type Contract interface {
 		callerAddress ethcmn.Address,
 	) (string, error)
 
+	// GetValsetCheckpoint returns the checkpoint of the current valset stored in the contract at the given block, or
+	// at the latest one if blockNumber is nil.
+	GetValsetCheckpoint(
+		ctx context.Context,
+		callerAddress ethcmn.Address,
+		blockNumber *big.Int,
+	) (ethcmn.Hash, error)
+
 	GetERC20Symbol(
 		ctx context.Context,
 		erc20ContractAddress ethcmn.Address,
@@ -86,11 +94,26 @@ type Contract interface {
 	// to the Gravity contract.
 	SubscribeToPendingTxs(ctx context.Context, alchemyWebsocketURL string) error
 
+	// SubscribeToNewPendingTxs subscribes to the standard newPendingTransactions on a websocket endpoint and looks up
+	// every pending tx to find the ones made to the Gravity contract.
+	SubscribeToNewPendingTxs(ctx context.Context, wsURL string) error
+
+	// PollTxPool polls txpool_content on a self-hosted node to find the pending txs made to the Gravity contract.
+	PollTxPool(ctx context.Context, rpcURL string, interval time.Duration) error
+
 	// IsPendingTxInput returns true if the input data is found in the pending tx list. If the tx is found but the tx is
 	// older than pendingTxWaitDuration, we consider it stale and return false, so the validator re-sends it.
 	IsPendingTxInput(txData []byte, pendingTxWaitDuration time.Duration) bool
 
+	// EvictPendingTxs removes from the pending tx list the txs older than pendingTxWaitDuration, and the ones that were
+	// mined or dropped since.
+	EvictPendingTxs(ctx context.Context, pendingTxWaitDuration time.Duration) error
+
 	GetPendingTxInputList() *PendingTxInputList
+
+	// SimulateTx executes the call to the Gravity contract with the given tx data on top of the pending block, without
+	// sending any tx. It returns a *SimulationError if the call would revert.
+	SimulateTx(ctx context.Context, txData []byte) error
 }
 
 type gravityContract struct {
@@ -103,6 +126,7 @@ type gravityContract struct {
 
 	mtx               sync.Mutex
 	erc20DecimalCache map[string]uint8
+	gravityID         string
 }
 
 func NewGravityContract(
@@ -179,6 +203,58 @@ func (s *gravityContract) GetGravityID(
 	return string(gravityID[:]), nil
 }
 
+// Gets the checkpoint of the current valset
+func (s *gravityContract) GetValsetCheckpoint(
+	ctx context.Context,
+	callerAddress ethcmn.Address,
+	blockNumber *big.Int,
+) (ethcmn.Hash, error) {
+
+	checkpoint, err := s.ethGravity.StateLastValsetCheckpoint(&bind.CallOpts{
+		From:        callerAddress,
+		BlockNumber: blockNumber,
+		Context:     ctx,
+	})
+
+	if err != nil {
+		err = errors.Wrap(err, "StateLastValsetCheckpoint call failed")
+		return ethcmn.Hash{}, err
+	}
+
+	return checkpoint, nil
+}
+
+// cachedGravityID returns the gravityID, only querying the contract the first time as it never changes.
+func (s *gravityContract) cachedGravityID(ctx context.Context) (string, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.gravityID != "" {
+		return s.gravityID, nil
+	}
+
+	gravityID, err := s.GetGravityID(ctx, s.FromAddress())
+	if err != nil {
+		return "", err
+	}
+
+	s.gravityID = gravityID
+	return gravityID, nil
+}
+
+// reportInvalidSigs logs the orchestrators whose confirmations were dropped because their signature doesn't match
+// their Ethereum signer.
+func (s *gravityContract) reportInvalidSigs(invalidSigs []genericConfirm, confirmType string, nonce uint64) {
+	for _, sig := range invalidSigs {
+		s.logger.Warn().
+			Str("orchestrator", sig.Orchestrator).
+			Str("eth_signer", sig.EthSigner).
+			Str("confirm_type", confirmType).
+			Uint64("nonce", nonce).
+			Msg("dropping confirmation with an invalid signature")
+	}
+}
+
 func (s *gravityContract) GetERC20Symbol(
 	ctx context.Context,
 	erc20ContractAddress ethcmn.Address,
@@ -259,6 +335,11 @@ func sigToVRS(sigHex string) (v uint8, r, s ethcmn.Hash) {
 	return
 }
 
+// HasEnoughPower returns true if the given power is enough to pass the signature threshold of the Gravity contract.
+func HasEnoughPower(power *big.Int) bool {
+	return gravityPowerToPercent(power) >= 66
+}
+
 // gravityPowerToPercent takes in an amount of power in the Gravity Bridge, returns a percentage of total
 func gravityPowerToPercent(total *big.Int) float32 {
 	d := decimal.NewFromBigInt(total, 0)
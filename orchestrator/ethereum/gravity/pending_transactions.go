@@ -3,28 +3,57 @@ package gravity
 import (
 	"bytes"
 	"context"
+	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
 )
 
+// maxPendingTxInputs is the maximum number of pending txs tracked, the oldest ones are dropped first.
+const maxPendingTxInputs = 100
+
 // PendingTxInput contains the data of a pending transaction and the time we first saw it.
 type PendingTxInput struct {
 	InputData    hexutil.Bytes
 	ReceivedTime time.Time
+	TxHash       ethcmn.Hash
+	From         ethcmn.Address
+	Nonce        uint64
+
+	// seq orders the txs by the time they were added
+	seq uint64
 }
 
-type PendingTxInputList []PendingTxInput
+// PendingTxInputList is the set of pending submitBatch and updateValset calls to the Gravity contract, keyed by the
+// hash of their call data. It's safe for concurrent use.
+type PendingTxInputList struct {
+	mtx sync.RWMutex
+	txs map[ethcmn.Hash]PendingTxInput
+	seq uint64
+}
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	Input hexutil.Bytes `json:"input"`
+	Hash  ethcmn.Hash     `json:"hash"`
+	From  ethcmn.Address  `json:"from"`
+	Nonce hexutil.Uint64  `json:"nonce"`
+	To    *ethcmn.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
 }
 
 // AddPendingTxInput adds pending submitBatch and updateBatch calls to the Gravity contract to the list of pending
-// transactions, any other transaction is ignored.
+// transactions, any other transaction is ignored. A call already in the list keeps the time it was first seen.
 func (p *PendingTxInputList) AddPendingTxInput(pendingTx *RPCTransaction) {
+	// plain transfers have no call data
+	if len(pendingTx.Input) < 4 {
+		return
+	}
 
 	submitBatchMethod := gravityABI.Methods["submitBatch"]
 	valsetUpdateMethod := gravityABI.Methods["updateValset"]
@@ -37,30 +66,149 @@ func (p *PendingTxInputList) AddPendingTxInput(pendingTx *RPCTransaction) {
 		return
 	}
 
-	pendingTxInput := PendingTxInput{
+	key := crypto.Keccak256Hash(pendingTx.Input)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.txs == nil {
+		p.txs = map[ethcmn.Hash]PendingTxInput{}
+	}
+
+	if _, ok := p.txs[key]; ok {
+		return
+	}
+
+	// Dequeue the oldest pending tx input
+	if len(p.txs) >= maxPendingTxInputs {
+		var oldestKey ethcmn.Hash
+		for k, tx := range p.txs {
+			if oldest, ok := p.txs[oldestKey]; !ok || tx.seq < oldest.seq {
+				oldestKey = k
+			}
+		}
+
+		delete(p.txs, oldestKey)
+	}
+
+	p.txs[key] = PendingTxInput{
 		InputData:    pendingTx.Input,
 		ReceivedTime: time.Now(),
+		TxHash:       pendingTx.Hash,
+		From:         pendingTx.From,
+		Nonce:        uint64(pendingTx.Nonce),
+		seq:          p.seq,
+	}
+	p.seq++
+}
+
+// IsPendingTxInput returns true if the call data is in the list and was seen less than pendingTxWaitDuration ago.
+// Expired calls are removed from the list.
+func (p *PendingTxInputList) IsPendingTxInput(txData []byte, pendingTxWaitDuration time.Duration) bool {
+	key := crypto.Keccak256Hash(txData)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	pendingTx, ok := p.txs[key]
+	if !ok {
+		return false
 	}
 
-	*p = append(*p, pendingTxInput)
-	// Persisting top 100 pending txs of Gravity contract only.
-	if len(*p) > 100 {
-		(*p)[0] = PendingTxInput{} // to avoid memory leak
-		// Dequeue pending tx input
-		*p = (*p)[1:]
+	// If this tx was for too long in the pending list, consider it stale
+	if !time.Now().Before(pendingTx.ReceivedTime.Add(pendingTxWaitDuration)) {
+		delete(p.txs, key)
+		return false
 	}
+
+	return true
 }
 
-func (s *gravityContract) IsPendingTxInput(txData []byte, pendingTxWaitDuration time.Duration) bool {
-	t := time.Now()
+// Len returns the number of pending txs in the list.
+func (p *PendingTxInputList) Len() int {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
 
-	for _, pendingTxInput := range s.pendingTxInputList {
-		if bytes.Equal(pendingTxInput.InputData, txData) {
-			// If this tx was for too long in the pending list, consider it stale
-			return t.Before(pendingTxInput.ReceivedTime.Add(pendingTxWaitDuration))
+	return len(p.txs)
+}
+
+// Evict removes the pending txs seen more than pendingTxWaitDuration ago, and the ones that were mined or dropped,
+// i.e. when the nonce of their sender on-chain is past theirs.
+func (p *PendingTxInputList) Evict(
+	ctx context.Context,
+	ethProvider provider.EVMProvider,
+	pendingTxWaitDuration time.Duration,
+) error {
+	now := time.Now()
+
+	// the RPC calls are made without holding the lock
+	p.mtx.RLock()
+	pendingTxs := make(map[ethcmn.Hash]PendingTxInput, len(p.txs))
+	for k, tx := range p.txs {
+		pendingTxs[k] = tx
+	}
+	p.mtx.RUnlock()
+
+	evicted := map[ethcmn.Hash]struct{}{}
+	nonces := map[ethcmn.Address]uint64{}
+
+	for k, tx := range pendingTxs {
+		if !now.Before(tx.ReceivedTime.Add(pendingTxWaitDuration)) {
+			evicted[k] = struct{}{}
+			continue
+		}
+
+		// without its sender, we can only tell if the tx itself was mined
+		if tx.From == (ethcmn.Address{}) {
+			_, err := ethProvider.TransactionReceipt(ctx, tx.TxHash)
+			switch {
+			case err == nil:
+				evicted[k] = struct{}{}
+			case !errors.Is(err, ethereum.NotFound):
+				return errors.Wrap(err, "failed to get pending tx receipt")
+			}
+
+			continue
+		}
+
+		nonce, ok := nonces[tx.From]
+		if !ok {
+			var err error
+			if nonce, err = ethProvider.NonceAt(ctx, tx.From, nil); err != nil {
+				return errors.Wrap(err, "failed to get pending tx sender nonce")
+			}
+
+			nonces[tx.From] = nonce
+		}
+
+		if nonce > tx.Nonce {
+			evicted[k] = struct{}{}
 		}
 	}
-	return false
+
+	if len(evicted) == 0 {
+		return nil
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for k := range evicted {
+		// the entry may have been replaced in the meantime
+		if p.txs[k].seq == pendingTxs[k].seq {
+			delete(p.txs, k)
+		}
+	}
+
+	return nil
+}
+
+func (s *gravityContract) IsPendingTxInput(txData []byte, pendingTxWaitDuration time.Duration) bool {
+	return s.pendingTxInputList.IsPendingTxInput(txData, pendingTxWaitDuration)
+}
+
+func (s *gravityContract) EvictPendingTxs(ctx context.Context, pendingTxWaitDuration time.Duration) error {
+	return s.pendingTxInputList.Evict(ctx, s.Provider(), pendingTxWaitDuration)
 }
 
 func (s *gravityContract) SubscribeToPendingTxs(ctx context.Context, alchemyWebsocketURL string) error {
@@ -90,7 +238,7 @@ func (s *gravityContract) SubscribeToPendingTxs(ctx context.Context, alchemyWebs
 	for {
 		select {
 		case pendingTransaction := <-ch:
-			s.pendingTxInputList.AddPendingTxInput(pendingTransaction)
+			s.addPendingTx(pendingTransaction)
 
 		case <-ctx.Done():
 			return nil
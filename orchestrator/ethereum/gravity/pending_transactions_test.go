@@ -1,12 +1,16 @@
 package gravity
 
 import (
+	"context"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/golang/mock/gomock"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -33,17 +37,95 @@ func TestAddPendingTxInput(t *testing.T) {
 		Input: hexutil.MustDecode("0x0f21235700000000"),
 	})
 
+	// try to add a plain transfer
+	txList.AddPendingTxInput(&RPCTransaction{})
+
+	// add the same submitBatch call again
+	txList.AddPendingTxInput(&RPCTransaction{
+		Input: hexutil.MustDecode("0x8690ff9800000000"),
+	})
+
 	// Only the first 2 TXs should have been added
-	assert.Len(t, txList, 2)
+	assert.Equal(t, 2, txList.Len())
 
 	for i := 0; i < 110; i++ {
 		txList.AddPendingTxInput(&RPCTransaction{
-			Input: hexutil.MustDecode("0x8690ff9880000000"),
+			Input: append(hexutil.MustDecode("0x8690ff98"), byte(i)),
 		})
 	}
 
-	// The list should be at full capacity now
-	assert.Len(t, txList, 100)
+	// The list should be at full capacity now, with the oldest txs dropped
+	assert.Equal(t, 100, txList.Len())
+	assert.False(t, txList.IsPendingTxInput(hexutil.MustDecode("0xaca6b1c100000000"), time.Minute))
+	assert.True(t, txList.IsPendingTxInput(append(hexutil.MustDecode("0x8690ff98"), byte(109)), time.Minute))
+}
+
+func TestEvictPendingTxs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	senderA := ethcmn.HexToAddress("0x000000000000000000000000000000000000000A")
+	senderB := ethcmn.HexToAddress("0x000000000000000000000000000000000000000B")
+
+	txList := PendingTxInputList{}
+	pendingTxs := []*RPCTransaction{
+		// mined, sender A is at nonce 5
+		{Hash: ethcmn.HexToHash("0x01"), From: senderA, Nonce: 4, Input: hexutil.MustDecode("0x8690ff9801")},
+		// still pending
+		{Hash: ethcmn.HexToHash("0x02"), From: senderA, Nonce: 5, Input: hexutil.MustDecode("0x8690ff9802")},
+		// dropped, sender B replaced it
+		{Hash: ethcmn.HexToHash("0x03"), From: senderB, Nonce: 1, Input: hexutil.MustDecode("0x8690ff9803")},
+		// unknown sender, mined
+		{Hash: ethcmn.HexToHash("0x04"), Input: hexutil.MustDecode("0x8690ff9804")},
+		// unknown sender, still pending
+		{Hash: ethcmn.HexToHash("0x05"), Input: hexutil.MustDecode("0x8690ff9805")},
+	}
+	for _, tx := range pendingTxs {
+		txList.AddPendingTxInput(tx)
+	}
+
+	mockEvmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	// the nonce of each sender is requested once
+	mockEvmProvider.EXPECT().NonceAt(gomock.Any(), senderA, nil).Return(uint64(5), nil)
+	mockEvmProvider.EXPECT().NonceAt(gomock.Any(), senderB, nil).Return(uint64(2), nil)
+	mockEvmProvider.EXPECT().
+		TransactionReceipt(gomock.Any(), ethcmn.HexToHash("0x04")).
+		Return(&ethtypes.Receipt{Status: ethtypes.ReceiptStatusSuccessful}, nil)
+	mockEvmProvider.EXPECT().
+		TransactionReceipt(gomock.Any(), ethcmn.HexToHash("0x05")).
+		Return(nil, ethereum.NotFound)
+
+	assert.NoError(t, txList.Evict(context.Background(), mockEvmProvider, time.Minute))
+	assert.Equal(t, 2, txList.Len())
+	assert.True(t, txList.IsPendingTxInput(hexutil.MustDecode("0x8690ff9802"), time.Minute))
+	assert.True(t, txList.IsPendingTxInput(hexutil.MustDecode("0x8690ff9805"), time.Minute))
+
+	// everything expires
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, txList.Evict(context.Background(), mockEvmProvider, time.Microsecond))
+	assert.Equal(t, 0, txList.Len())
+}
+
+func TestPendingTxInputListConcurrency(t *testing.T) {
+	txList := PendingTxInputList{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			txList.AddPendingTxInput(&RPCTransaction{Input: append(hexutil.MustDecode("0x8690ff98"), byte(i))})
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+			txList.IsPendingTxInput(append(hexutil.MustDecode("0x8690ff98"), byte(i)), time.Minute)
+		}(i)
+	}
+
+	wg.Wait()
+	assert.Equal(t, 10, txList.Len())
 }
 
 func TestIsPendingTxInput(t *testing.T) {
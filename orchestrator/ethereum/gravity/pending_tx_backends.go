@@ -0,0 +1,169 @@
+package gravity
+
+import (
+	"context"
+	"time"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+// PendingTxBackend is the source of the pending txs used to avoid relaying what another relayer already sent.
+type PendingTxBackend string
+
+const (
+	// PendingTxBackendAlchemy uses Alchemy's alchemy_filteredNewFullPendingTransactions subscription.
+	PendingTxBackendAlchemy PendingTxBackend = "alchemy"
+	// PendingTxBackendSubscribe uses the standard newPendingTransactions subscription, looking up every tx by hash.
+	PendingTxBackendSubscribe PendingTxBackend = "subscribe"
+	// PendingTxBackendTxPool polls txpool_content, only available on self-hosted nodes.
+	PendingTxBackendTxPool PendingTxBackend = "txpool"
+)
+
+const (
+	// pendingTxLookupWorkers is the number of pending tx hashes looked up concurrently, so the subscription keeps up
+	// with the mempool.
+	pendingTxLookupWorkers = 8
+	// pendingTxResubscribeWait is how long to wait before reconnecting after a backend failed.
+	pendingTxResubscribeWait = 5 * time.Second
+)
+
+// addPendingTx adds the tx to the pending list if it's made to the Gravity contract.
+func (s *gravityContract) addPendingTx(tx *RPCTransaction) {
+	if tx == nil || tx.To == nil || *tx.To != s.gravityAddress {
+		return
+	}
+
+	s.pendingTxInputList.AddPendingTxInput(tx)
+}
+
+// SubscribeToNewPendingTxs subscribes to newPendingTransactions on the given websocket endpoint and adds the pending
+// txs made to the Gravity contract to the pending list. The subscription is re-established if it drops, it only
+// returns when ctx is done.
+func (s *gravityContract) SubscribeToNewPendingTxs(ctx context.Context, wsURL string) error {
+	logger := s.logger.With().Str("endpoint", wsURL).Logger()
+
+	for {
+		if err := s.subscribeToNewPendingTxs(ctx, wsURL); err != nil {
+			logger.Err(err).Msg("pending txs subscription failed; resubscribing...")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pendingTxResubscribeWait):
+		}
+	}
+}
+
+func (s *gravityContract) subscribeToNewPendingTxs(ctx context.Context, wsURL string) error {
+	rc, err := rpc.DialContext(ctx, wsURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to websocket")
+	}
+
+	defer rc.Close()
+
+	return s.watchNewPendingTxs(ctx, rc)
+}
+
+// watchNewPendingTxs looks up the full tx of every hash received from the newPendingTransactions subscription.
+func (s *gravityContract) watchNewPendingTxs(ctx context.Context, rc *rpc.Client) error {
+	hashes := make(chan ethcmn.Hash, 1024)
+
+	sub, err := rc.EthSubscribe(ctx, hashes, "newPendingTransactions")
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe to pending txs")
+	}
+
+	defer sub.Unsubscribe()
+
+	s.logger.Info().Msg("subscribed to pending txs")
+
+	lookups := make(chan ethcmn.Hash)
+	defer close(lookups)
+
+	for i := 0; i < pendingTxLookupWorkers; i++ {
+		go func() {
+			for hash := range lookups {
+				var tx *RPCTransaction
+				if err := rc.CallContext(ctx, &tx, "eth_getTransactionByHash", hash); err != nil {
+					s.logger.Debug().Err(err).Str("tx_hash", hash.Hex()).Msg("failed to look up pending tx")
+					continue
+				}
+
+				// the tx is gone already if it's nil
+				s.addPendingTx(tx)
+			}
+		}()
+	}
+
+	for {
+		select {
+		case hash := <-hashes:
+			select {
+			case lookups <- hash:
+			case <-ctx.Done():
+				return nil
+			}
+
+		case err := <-sub.Err():
+			return err
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// PollTxPool polls txpool_content on the given endpoint every interval and adds the txs made to the Gravity contract
+// to the pending list. It only returns when ctx is done.
+func (s *gravityContract) PollTxPool(ctx context.Context, rpcURL string, interval time.Duration) error {
+	rc, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to Ethereum node")
+	}
+
+	defer rc.Close()
+
+	return s.pollTxPool(ctx, rc, interval)
+}
+
+func (s *gravityContract) pollTxPool(ctx context.Context, rc *rpc.Client, interval time.Duration) error {
+	// the txs seen on the previous poll, so the same tx isn't added again while it stays in the pool
+	seen := map[ethcmn.Hash]struct{}{}
+
+	for {
+		var content map[string]map[string]map[string]*RPCTransaction
+		if err := rc.CallContext(ctx, &content, "txpool_content"); err != nil {
+			s.logger.Err(err).Msg("failed to get txpool content")
+		} else {
+			inPool := map[ethcmn.Hash]struct{}{}
+
+			// the txs are grouped in pending and queued, then by sender and by nonce
+			for _, senders := range content {
+				for _, nonces := range senders {
+					for _, tx := range nonces {
+						if tx == nil {
+							continue
+						}
+
+						inPool[tx.Hash] = struct{}{}
+						if _, ok := seen[tx.Hash]; !ok {
+							s.addPendingTx(tx)
+						}
+					}
+				}
+			}
+
+			seen = inPool
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package gravity
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEthService serves the pending txs of a node on the eth namespace.
+type fakeEthService struct {
+	txs []*RPCTransaction
+}
+
+func (s *fakeEthService) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	sub := notifier.CreateSubscription()
+	go func() {
+		for _, tx := range s.txs {
+			_ = notifier.Notify(sub.ID, tx.Hash)
+		}
+
+		// a hash that can't be found anymore
+		_ = notifier.Notify(sub.ID, ethcmn.HexToHash("0xdead"))
+	}()
+
+	return sub, nil
+}
+
+func (s *fakeEthService) GetTransactionByHash(hash ethcmn.Hash) *RPCTransaction {
+	for _, tx := range s.txs {
+		if tx.Hash == hash {
+			return tx
+		}
+	}
+
+	return nil
+}
+
+// fakeTxPoolService serves the pending txs of a node on the txpool namespace.
+type fakeTxPoolService struct {
+	txs []*RPCTransaction
+}
+
+func (s *fakeTxPoolService) Content() map[string]map[string]map[string]*RPCTransaction {
+	content := map[string]map[string]map[string]*RPCTransaction{
+		"pending": {},
+		"queued":  {},
+	}
+
+	// every tx from another sender
+	for i, tx := range s.txs {
+		content["pending"][ethcmn.BigToAddress(big.NewInt(int64(i))).Hex()] = map[string]*RPCTransaction{
+			hexutil.EncodeUint64(0): tx,
+		}
+	}
+
+	return content
+}
+
+func testPendingTxs(gravityAddress ethcmn.Address) []*RPCTransaction {
+	otherAddress := ethcmn.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	return []*RPCTransaction{
+		// submitBatch
+		{Hash: ethcmn.HexToHash("0x01"), To: &gravityAddress, Input: hexutil.MustDecode("0x8690ff9800000001")},
+		// updateValset
+		{Hash: ethcmn.HexToHash("0x02"), To: &gravityAddress, Input: hexutil.MustDecode("0xaca6b1c100000002")},
+		// sendToCosmos
+		{Hash: ethcmn.HexToHash("0x03"), To: &gravityAddress, Input: hexutil.MustDecode("0x0f21235700000003")},
+		// submitBatch to another contract
+		{Hash: ethcmn.HexToHash("0x04"), To: &otherAddress, Input: hexutil.MustDecode("0x8690ff9800000004")},
+		// plain transfer
+		{Hash: ethcmn.HexToHash("0x05"), To: &gravityAddress},
+		// contract creation
+		{Hash: ethcmn.HexToHash("0x06"), Input: hexutil.MustDecode("0x8690ff9800000006")},
+	}
+}
+
+func TestWatchNewPendingTxs(t *testing.T) {
+	gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	require.NoError(t, server.RegisterName("eth", &fakeEthService{txs: testPendingTxs(gravityAddress)}))
+
+	rc := rpc.DialInProc(server)
+	defer rc.Close()
+
+	contract := &gravityContract{
+		logger:         zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		gravityAddress: gravityAddress,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- contract.watchNewPendingTxs(ctx, rc)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return contract.IsPendingTxInput(hexutil.MustDecode("0x8690ff9800000001"), time.Minute) &&
+			contract.IsPendingTxInput(hexutil.MustDecode("0xaca6b1c100000002"), time.Minute)
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	assert.Equal(t, 2, contract.pendingTxInputList.Len())
+}
+
+func TestPollTxPool(t *testing.T) {
+	gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	require.NoError(t, server.RegisterName("txpool", &fakeTxPoolService{txs: testPendingTxs(gravityAddress)}))
+
+	rc := rpc.DialInProc(server)
+	defer rc.Close()
+
+	contract := &gravityContract{
+		logger:         zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		gravityAddress: gravityAddress,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// the pool is polled several times, but the txs still in it are only added once
+	assert.NoError(t, contract.pollTxPool(ctx, rc, 5*time.Millisecond))
+
+	assert.Equal(t, 2, contract.pendingTxInputList.Len())
+	assert.True(t, contract.IsPendingTxInput(hexutil.MustDecode("0x8690ff9800000001"), time.Minute))
+	assert.True(t, contract.IsPendingTxInput(hexutil.MustDecode("0xaca6b1c100000002"), time.Minute))
+}
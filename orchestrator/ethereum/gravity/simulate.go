@@ -0,0 +1,133 @@
+package gravity
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+// SimulationOutcome classifies why a Gravity contract call would revert.
+type SimulationOutcome int
+
+const (
+	// SimulationReverted is a revert we don't know the reason of.
+	SimulationReverted SimulationOutcome = iota
+	// SimulationAlreadyRelayed means a batch or valset with the same or a higher nonce has already been relayed.
+	SimulationAlreadyRelayed
+	// SimulationStaleValset means the current valset we are relaying against doesn't match the contract's.
+	SimulationStaleValset
+	// SimulationTimedOut means the batch timeout is lower than the current Ethereum block height.
+	SimulationTimedOut
+	// SimulationInsufficientPower means the signatures don't add up to the power threshold.
+	SimulationInsufficientPower
+	// SimulationInvalidSignature means some of the signatures don't match their validators.
+	SimulationInvalidSignature
+)
+
+func (o SimulationOutcome) String() string {
+	switch o {
+	case SimulationAlreadyRelayed:
+		return "already relayed"
+	case SimulationStaleValset:
+		return "stale valset"
+	case SimulationTimedOut:
+		return "timed out"
+	case SimulationInsufficientPower:
+		return "insufficient power"
+	case SimulationInvalidSignature:
+		return "invalid signature"
+	default:
+		return "reverted"
+	}
+}
+
+// revertOutcomes maps the Gravity contract custom errors to their outcome.
+var revertOutcomes = map[string]SimulationOutcome{
+	"InvalidBatchNonce":            SimulationAlreadyRelayed,
+	"InvalidValsetNonce":           SimulationAlreadyRelayed,
+	"IncorrectCheckpoint":          SimulationStaleValset,
+	"MalformedCurrentValidatorSet": SimulationStaleValset,
+	"BatchTimedOut":                SimulationTimedOut,
+	"InsufficientPower":            SimulationInsufficientPower,
+	"InvalidSignature":             SimulationInvalidSignature,
+}
+
+// SimulationError is returned by SimulateTx when the call would revert.
+type SimulationError struct {
+	Outcome SimulationOutcome
+	Reason  string
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("tx would revert (%s): %s", e.Outcome, e.Reason)
+}
+
+// SimulateTx executes the call to the Gravity contract with the given tx data on top of the pending block, without
+// sending any tx. It returns a *SimulationError if the call would revert, or any other error if the simulation
+// couldn't be performed at all.
+func (s *gravityContract) SimulateTx(ctx context.Context, txData []byte) error {
+	gravityAddress := s.Address()
+
+	_, err := s.Provider().PendingCallContract(ctx, ethereum.CallMsg{
+		From: s.FromAddress(),
+		To:   &gravityAddress,
+		Data: txData,
+	})
+	if err == nil {
+		return nil
+	}
+
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		err = errors.Wrap(err, "failed to simulate tx")
+		return err
+	}
+
+	return classifyRevert(dataErr)
+}
+
+// classifyRevert decodes the revert data returned along with a failed call into a *SimulationError.
+func classifyRevert(dataErr rpc.DataError) *SimulationError {
+	simErr := &SimulationError{Outcome: SimulationReverted, Reason: dataErr.Error()}
+
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return simErr
+	}
+
+	data, err := hexutil.Decode(hexData)
+	if err != nil || len(data) < 4 {
+		return simErr
+	}
+
+	// revert("reason") or require(cond, "reason")
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		simErr.Reason = reason
+		return simErr
+	}
+
+	for name, abiErr := range gravityABI.Errors {
+		if !bytes.Equal(abiErr.ID[:4], data[:4]) {
+			continue
+		}
+
+		simErr.Reason = name
+		if args, err := abiErr.Unpack(data); err == nil {
+			simErr.Reason = fmt.Sprintf("%s%v", name, args)
+		}
+
+		if outcome, ok := revertOutcomes[name]; ok {
+			simErr.Outcome = outcome
+		}
+
+		break
+	}
+
+	return simErr
+}
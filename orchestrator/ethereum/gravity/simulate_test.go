@@ -0,0 +1,177 @@
+package gravity
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umee-network/peggo/mocks"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
+	wrappers "github.com/umee-network/peggo/solwrappers/Gravity.sol"
+)
+
+type revertError struct {
+	data string
+}
+
+func (e revertError) Error() string          { return "execution reverted" }
+func (e revertError) ErrorData() interface{} { return e.data }
+
+func encodeCustomError(t *testing.T, name string, args ...interface{}) string {
+	abiErr, ok := gravityABI.Errors[name]
+	require.True(t, ok)
+
+	packed, err := abiErr.Inputs.Pack(args...)
+	require.NoError(t, err)
+
+	return hexutil.Encode(append(abiErr.ID[:4], packed...))
+}
+
+func encodeRevertReason(t *testing.T, reason string) string {
+	stringTy, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+
+	packed, err := abi.Arguments{{Type: stringTy}}.Pack(reason)
+	require.NoError(t, err)
+
+	// Error(string) selector
+	return hexutil.Encode(append([]byte{0x08, 0xc3, 0x79, 0xa0}, packed...))
+}
+
+func TestClassifyRevert(t *testing.T) {
+	testCases := []struct {
+		name            string
+		data            string
+		expectedOutcome SimulationOutcome
+		expectedReason  string
+	}{
+		{
+			name:            "batch already relayed",
+			data:            encodeCustomError(t, "InvalidBatchNonce", big.NewInt(5), big.NewInt(4)),
+			expectedOutcome: SimulationAlreadyRelayed,
+			expectedReason:  "InvalidBatchNonce[5 4]",
+		},
+		{
+			name:            "batch timed out",
+			data:            encodeCustomError(t, "BatchTimedOut"),
+			expectedOutcome: SimulationTimedOut,
+			expectedReason:  "BatchTimedOut[]",
+		},
+		{
+			name:            "stale valset",
+			data:            encodeCustomError(t, "IncorrectCheckpoint"),
+			expectedOutcome: SimulationStaleValset,
+			expectedReason:  "IncorrectCheckpoint[]",
+		},
+		{
+			name:            "insufficient power",
+			data:            encodeCustomError(t, "InsufficientPower", big.NewInt(10), big.NewInt(20)),
+			expectedOutcome: SimulationInsufficientPower,
+			expectedReason:  "InsufficientPower[10 20]",
+		},
+		{
+			name:            "revert reason",
+			data:            encodeRevertReason(t, "something went wrong"),
+			expectedOutcome: SimulationReverted,
+			expectedReason:  "something went wrong",
+		},
+		{
+			name:            "unknown selector",
+			data:            "0xdeadbeef",
+			expectedOutcome: SimulationReverted,
+			expectedReason:  "execution reverted",
+		},
+		{
+			name:            "no data",
+			data:            "0x",
+			expectedOutcome: SimulationReverted,
+			expectedReason:  "execution reverted",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			simErr := classifyRevert(revertError{data: tc.data})
+			assert.Equal(t, tc.expectedOutcome, simErr.Outcome)
+			assert.Equal(t, tc.expectedReason, simErr.Reason)
+		})
+	}
+}
+
+func TestSimulateTx(t *testing.T) {
+	newGravityContract := func(mockEvmProvider *mocks.MockEVMProviderWithRet) Contract {
+		logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+		ethCommitter, _ := committer.NewEthCommitter(
+			logger,
+			ethcmn.Address{},
+			1.0,
+			1.0,
+			nil,
+			mockEvmProvider,
+		)
+
+		ethGravity, _ := wrappers.NewGravity(ethcmn.Address{}, ethCommitter.Provider())
+		gravityContract, _ := NewGravityContract(logger, ethCommitter, ethcmn.Address{}, ethGravity)
+
+		return gravityContract
+	}
+
+	t.Run("would succeed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockEvmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		mockEvmProvider.EXPECT().PendingNonceAt(gomock.Any(), gomock.Any()).Return(uint64(0), nil)
+		mockEvmProvider.EXPECT().
+			PendingCallContract(gomock.Any(), gomock.AssignableToTypeOf(ethereum.CallMsg{})).
+			Return(nil, nil)
+
+		err := newGravityContract(mockEvmProvider).SimulateTx(context.Background(), []byte{1, 2, 3})
+		assert.Nil(t, err)
+	})
+
+	t.Run("would revert", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockEvmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		mockEvmProvider.EXPECT().PendingNonceAt(gomock.Any(), gomock.Any()).Return(uint64(0), nil)
+		mockEvmProvider.EXPECT().
+			PendingCallContract(gomock.Any(), gomock.AssignableToTypeOf(ethereum.CallMsg{})).
+			Return(nil, revertError{data: encodeCustomError(t, "BatchTimedOut")})
+
+		err := newGravityContract(mockEvmProvider).SimulateTx(context.Background(), []byte{1, 2, 3})
+
+		var simErr *SimulationError
+		require.True(t, errors.As(err, &simErr))
+		assert.Equal(t, SimulationTimedOut, simErr.Outcome)
+		assert.EqualError(t, err, "tx would revert (timed out): BatchTimedOut[]")
+	})
+
+	t.Run("call failed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockEvmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		mockEvmProvider.EXPECT().PendingNonceAt(gomock.Any(), gomock.Any()).Return(uint64(0), nil)
+		mockEvmProvider.EXPECT().
+			PendingCallContract(gomock.Any(), gomock.AssignableToTypeOf(ethereum.CallMsg{})).
+			Return(nil, errors.New("connection refused"))
+
+		err := newGravityContract(mockEvmProvider).SimulateTx(context.Background(), []byte{1, 2, 3})
+
+		var simErr *SimulationError
+		assert.False(t, errors.As(err, &simErr))
+		assert.EqualError(t, err, "failed to simulate tx: connection refused")
+	})
+}
@@ -21,8 +21,9 @@ type RepackedSigs struct {
 // genericConfirm exists only to aid the check and repacking of signatures.
 // This way both ValsetUpdates and Batch's signatures can be checked and repacked in the same function.
 type genericConfirm struct {
-	EthSigner string
-	Signature string
+	Orchestrator string
+	EthSigner    string
+	Signature    string
 }
 
 func (s *gravityContract) EncodeTransactionBatch(
@@ -32,7 +33,15 @@ func (s *gravityContract) EncodeTransactionBatch(
 	confirms []types.MsgConfirmBatch,
 ) ([]byte, error) {
 
-	sigs, err := checkBatchSigsAndRepack(currentValset, confirms)
+	gravityID, err := s.cachedGravityID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := EncodeTxBatchConfirm(gravityID, batch)
+
+	sigs, invalidSigs, err := checkBatchSigsAndRepack(currentValset, checkpoint, confirms)
+	s.reportInvalidSigs(invalidSigs, "batch", batch.BatchNonce)
 	if err != nil {
 		err = errors.Wrap(err, "confirmations check failed")
 		return nil, err
@@ -98,24 +107,39 @@ func getBatchCheckpointValues(batch types.OutgoingTxBatch) (
 
 // checkBatchSigsAndRepack checks all the signatures for a batch (confirmations), assembles them into the expected
 // format and checks if the power of the signatures would be enough to send this batch to Ethereum.
-func checkBatchSigsAndRepack(valset types.Valset, confirms []types.MsgConfirmBatch) (*RepackedSigs, error) {
+func checkBatchSigsAndRepack(
+	valset types.Valset,
+	checkpoint ethcmn.Hash,
+	confirms []types.MsgConfirmBatch,
+) (*RepackedSigs, []genericConfirm, error) {
 	if len(confirms) == 0 {
-		return nil, errors.New("no signatures in batch confirmation")
+		return nil, nil, errors.New("no signatures in batch confirmation")
 	}
 
 	genericConfirms := make([]genericConfirm, len(confirms))
 	for i, c := range confirms {
 		genericConfirms[i] = genericConfirm{
-			EthSigner: c.EthSigner,
-			Signature: c.Signature,
+			Orchestrator: c.Orchestrator,
+			EthSigner:    c.EthSigner,
+			Signature:    c.Signature,
 		}
 	}
 
-	return checkAndRepackSigs(valset, genericConfirms)
+	return checkAndRepackSigs(valset, checkpoint, genericConfirms)
 }
 
-func checkAndRepackSigs(valset types.Valset, confirms []genericConfirm) (*RepackedSigs, error) {
-	var err error
+// checkAndRepackSigs assembles the signatures of the valset members over the checkpoint in the order expected by the
+// contract. A signature that wasn't made by its signer would make the whole tx revert, so it's treated as missing and
+// returned along with the other invalid ones. The threshold is checked against the power of the valid signatures only.
+func checkAndRepackSigs(
+	valset types.Valset,
+	checkpoint ethcmn.Hash,
+	confirms []genericConfirm,
+) (*RepackedSigs, []genericConfirm, error) {
+	var (
+		err         error
+		invalidSigs []genericConfirm
+	)
 
 	sigs := &RepackedSigs{}
 
@@ -128,7 +152,13 @@ func checkAndRepackSigs(valset types.Valset, confirms []genericConfirm) (*Repack
 
 	for _, m := range valset.Members {
 		mPower := big.NewInt(0).SetUint64(m.Power)
-		if sig, ok := signerToSig[m.EthereumAddress]; ok && sig.EthSigner == m.EthereumAddress {
+		sig, ok := signerToSig[m.EthereumAddress]
+		if ok && !verifyConfirmSig(checkpoint, sig.Signature, ethcmn.HexToAddress(m.EthereumAddress)) {
+			invalidSigs = append(invalidSigs, sig)
+			ok = false
+		}
+
+		if ok {
 			powerOfGoodSigs.Add(powerOfGoodSigs, mPower)
 
 			sigs.validators = append(sigs.validators, ethcmn.HexToAddress(m.EthereumAddress))
@@ -146,10 +176,12 @@ func checkAndRepackSigs(valset types.Valset, confirms []genericConfirm) (*Repack
 			sigs.s = append(sigs.s, [32]byte{})
 		}
 	}
-	if gravityPowerToPercent(powerOfGoodSigs) < 66 {
+	if !HasEnoughPower(powerOfGoodSigs) {
 		err = ErrInsufficientVotingPowerToPass
-		return sigs, err
+		if len(invalidSigs) > 0 {
+			err = errors.Wrapf(err, "%d invalid signatures dropped", len(invalidSigs))
+		}
 	}
 
-	return sigs, err
+	return sigs, invalidSigs, err
 }
@@ -10,8 +10,10 @@ import (
 
 	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum"
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/umee-network/peggo/mocks"
@@ -25,6 +27,9 @@ func TestEncodeTransactionBatch(t *testing.T) {
 	mockEvmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
 
 	mockEvmProvider.EXPECT().PendingNonceAt(gomock.Any(), ethcmn.HexToAddress("0x0")).Return(uint64(0), nil)
+	mockEvmProvider.EXPECT().
+		CallContract(gomock.Any(), gomock.AssignableToTypeOf(ethereum.CallMsg{}), nil).
+		Return(ethcmn.RightPadBytes([]byte("defaultgravityid"), 32), nil)
 
 	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
 	ethCommitter, _ := committer.NewEthCommitter(
@@ -41,32 +46,21 @@ func TestEncodeTransactionBatch(t *testing.T) {
 		Height: 1111,
 		Members: []types.BridgeValidator{
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x0").Hex(),
+				EthereumAddress: testEthAddress(t, 1),
 				Power:           1111111111,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x1").Hex(),
+				EthereumAddress: testEthAddress(t, 2),
 				Power:           2212121212,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x2").Hex(),
+				EthereumAddress: testEthAddress(t, 3),
 				Power:           123456,
 			},
 		},
 		RewardAmount: sdk.NewInt(0),
 	}
 
-	confirms := []types.MsgConfirmBatch{
-		{
-			EthSigner: ethcmn.HexToAddress("0x0").Hex(),
-			Signature: "0xaae54ee7e285fbb0275279143abc4c554e5314e7b417ecac83a5984a964facbaad68866a2841c3e83ddf125a2985566261c4014f9f960ec60253aebcda9513a9b4",
-		},
-		{
-			EthSigner: ethcmn.HexToAddress("0x1").Hex(),
-			Signature: "0xaae54ee7e285fbb0275279143abc4c554e5314e7b417ecac83a5984a964facbaad68866a2841c3e83ddf125a2985566261c4014f9f960ec60253aebcda9513a9b4",
-		},
-	}
-
 	batch := types.OutgoingTxBatch{
 		BatchNonce:    1,
 		BatchTimeout:  11111,
@@ -87,6 +81,18 @@ func TestEncodeTransactionBatch(t *testing.T) {
 		},
 	}
 
+	checkpoint := EncodeTxBatchConfirm("defaultgravityid", batch)
+	confirms := []types.MsgConfirmBatch{
+		{
+			EthSigner: testEthAddress(t, 1),
+			Signature: signConfirm(t, testEthKey(t, 1), checkpoint),
+		},
+		{
+			EthSigner: testEthAddress(t, 2),
+			Signature: signConfirm(t, testEthKey(t, 2), checkpoint),
+		},
+	}
+
 	ethGravity, _ := wrappers.NewGravity(ethcmn.Address{}, ethCommitter.Provider())
 	gravityContract, _ := NewGravityContract(logger, ethCommitter, ethcmn.Address{}, ethGravity)
 
@@ -101,7 +107,7 @@ func TestEncodeTransactionBatch(t *testing.T) {
 
 	// Let's check the hash of the TX data instead of the entire thing
 	txDataHash := sha256.Sum256(txData)
-	assert.Equal(t, "86244d2ed48753a4a08797cec89af96e9aa9e858e328c92b9d0a242a3778d883", hex.EncodeToString(txDataHash[:]))
+	assert.Equal(t, "64a8afda5e2390b030b953199198e50eb0e3c2df7542a23a5fb4c066a1f2de79", hex.EncodeToString(txDataHash[:]))
 }
 
 func TestGetBatchCheckpointValues(t *testing.T) {
@@ -131,35 +137,80 @@ func TestCheckBatchSigsAndRepack(t *testing.T) {
 	valset := types.Valset{
 		Members: []types.BridgeValidator{
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x0").Hex(),
+				EthereumAddress: testEthAddress(t, 1),
 				Power:           1111111111,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x1").Hex(),
+				EthereumAddress: testEthAddress(t, 2),
 				Power:           2212121212,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x2").Hex(),
+				EthereumAddress: testEthAddress(t, 3),
 				Power:           123456,
 			},
 		},
 	}
 
+	checkpoint := EncodeTxBatchConfirm("defaultgravityid", types.OutgoingTxBatch{BatchNonce: 1})
 	confirms := []types.MsgConfirmBatch{
 		{
-			EthSigner: ethcmn.HexToAddress("0x0").Hex(),
-			Signature: "0xaae54ee7e285fbb0275279143abc4c554e5314e7b417ecac83a5984a964facbaad68866a2841c3e83ddf125a2985566261c4014f9f960ec60253aebcda9513a9b4",
+			EthSigner: testEthAddress(t, 1),
+			Signature: signConfirm(t, testEthKey(t, 1), checkpoint),
 		},
 		{
-			EthSigner: ethcmn.HexToAddress("0x1").Hex(),
-			Signature: "0xaae54ee7e285fbb0275279143abc4c554e5314e7b417ecac83a5984a964facbaad68866a2841c3e83ddf125a2985566261c4014f9f960ec60253aebcda9513a9b4",
+			EthSigner: testEthAddress(t, 2),
+			Signature: signConfirm(t, testEthKey(t, 2), checkpoint),
 		},
 	}
 
-	repackedSigs, err := checkBatchSigsAndRepack(valset, confirms)
-	assert.Nil(t, err)
-
-	assert.Equal(t, []ethcmn.Address{ethcmn.HexToAddress("0x0"), ethcmn.HexToAddress("0x1"), ethcmn.HexToAddress("0x2")}, repackedSigs.validators)
-	assert.Equal(t, []*big.Int{big.NewInt(1111111111), big.NewInt(2212121212), big.NewInt(123456)}, repackedSigs.powers)
+	expectedValidators := []ethcmn.Address{
+		ethcmn.HexToAddress(testEthAddress(t, 1)),
+		ethcmn.HexToAddress(testEthAddress(t, 2)),
+		ethcmn.HexToAddress(testEthAddress(t, 3)),
+	}
 
+	t.Run("ok", func(t *testing.T) {
+		repackedSigs, invalidSigs, err := checkBatchSigsAndRepack(valset, checkpoint, confirms)
+		assert.Nil(t, err)
+		assert.Empty(t, invalidSigs)
+
+		assert.Equal(t, expectedValidators, repackedSigs.validators)
+		assert.Equal(t, []*big.Int{big.NewInt(1111111111), big.NewInt(2212121212), big.NewInt(123456)}, repackedSigs.powers)
+		assert.NotEqual(t, ethcmn.Hash{}, repackedSigs.r[0])
+		assert.NotEqual(t, ethcmn.Hash{}, repackedSigs.r[1])
+		assert.Equal(t, ethcmn.Hash{}, repackedSigs.r[2])
+	})
+
+	t.Run("invalid signature dropped, still enough power", func(t *testing.T) {
+		badConfirms := append([]types.MsgConfirmBatch{}, confirms...)
+		badConfirms = append(badConfirms, types.MsgConfirmBatch{
+			Orchestrator: "umee1bad",
+			EthSigner:    testEthAddress(t, 3),
+			Signature:    signConfirm(t, testEthKey(t, 4), checkpoint),
+		})
+
+		repackedSigs, invalidSigs, err := checkBatchSigsAndRepack(valset, checkpoint, badConfirms)
+		assert.Nil(t, err)
+		assert.Equal(t, []genericConfirm{{
+			Orchestrator: "umee1bad",
+			EthSigner:    testEthAddress(t, 3),
+			Signature:    badConfirms[2].Signature,
+		}}, invalidSigs)
+
+		assert.Equal(t, expectedValidators, repackedSigs.validators)
+		assert.Equal(t, uint8(0), repackedSigs.v[2])
+		assert.Equal(t, ethcmn.Hash{}, repackedSigs.r[2])
+		assert.Equal(t, ethcmn.Hash{}, repackedSigs.s[2])
+	})
+
+	t.Run("invalid signature dropped, not enough power", func(t *testing.T) {
+		badConfirms := append([]types.MsgConfirmBatch{}, confirms...)
+		badConfirms[1].Signature = "0xaae54ee7"
+
+		repackedSigs, invalidSigs, err := checkBatchSigsAndRepack(valset, checkpoint, badConfirms)
+		assert.True(t, errors.Is(err, ErrInsufficientVotingPowerToPass))
+		assert.EqualError(t, err, "1 invalid signatures dropped: insufficient voting power")
+		assert.Len(t, invalidSigs, 1)
+		assert.Equal(t, ethcmn.Hash{}, repackedSigs.r[1])
+	})
 }
@@ -37,9 +37,17 @@ func (s *gravityContract) EncodeValsetUpdate(
 		RewardToken:  ethcmn.HexToAddress(newValset.RewardToken),
 	}
 
-	// we need to use the old valset here because our signatures need to match the current
-	// members of the validator set in the contract.
-	sigs, err := checkValsetSigsAndRepack(oldValset, confirms)
+	gravityID, err := s.cachedGravityID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the confirmations are signatures over the new valset checkpoint, but we need to use the old valset here because
+	// our signatures need to match the current members of the validator set in the contract.
+	checkpoint := EncodeValsetConfirm(gravityID, newValset)
+
+	sigs, invalidSigs, err := checkValsetSigsAndRepack(oldValset, checkpoint, confirms)
+	s.reportInvalidSigs(invalidSigs, "valset", newValset.Nonce)
 	if err != nil {
 		err = errors.Wrap(err, "confirmations check failed")
 		return nil, err
@@ -95,18 +103,23 @@ func validatorsAndPowers(valset types.Valset) (
 	return
 }
 
-func checkValsetSigsAndRepack(valset types.Valset, confirms []types.MsgValsetConfirm) (*RepackedSigs, error) {
+func checkValsetSigsAndRepack(
+	valset types.Valset,
+	checkpoint ethcmn.Hash,
+	confirms []types.MsgValsetConfirm,
+) (*RepackedSigs, []genericConfirm, error) {
 	if len(confirms) == 0 {
-		return nil, errors.New("no signatures in valset confirmation")
+		return nil, nil, errors.New("no signatures in valset confirmation")
 	}
 
 	genericConfirms := make([]genericConfirm, len(confirms))
 	for i, c := range confirms {
 		genericConfirms[i] = genericConfirm{
-			EthSigner: c.EthAddress,
-			Signature: c.Signature,
+			Orchestrator: c.Orchestrator,
+			EthSigner:    c.EthAddress,
+			Signature:    c.Signature,
 		}
 	}
 
-	return checkAndRepackSigs(valset, genericConfirms)
+	return checkAndRepackSigs(valset, checkpoint, genericConfirms)
 }
@@ -10,8 +10,10 @@ import (
 
 	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum"
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/umee-network/peggo/mocks"
@@ -25,6 +27,9 @@ func TestEncodeValsetUpdate(t *testing.T) {
 	mockEvmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
 
 	mockEvmProvider.EXPECT().PendingNonceAt(gomock.Any(), ethcmn.HexToAddress("0x0")).Return(uint64(0), nil)
+	mockEvmProvider.EXPECT().
+		CallContract(gomock.Any(), gomock.AssignableToTypeOf(ethereum.CallMsg{}), nil).
+		Return(ethcmn.RightPadBytes([]byte("defaultgravityid"), 32), nil)
 
 	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
 	ethCommitter, _ := committer.NewEthCommitter(
@@ -41,15 +46,15 @@ func TestEncodeValsetUpdate(t *testing.T) {
 		Height: 1111,
 		Members: []types.BridgeValidator{
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x0").Hex(),
+				EthereumAddress: testEthAddress(t, 1),
 				Power:           1111111111,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x1").Hex(),
+				EthereumAddress: testEthAddress(t, 2),
 				Power:           2212121212,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x2").Hex(),
+				EthereumAddress: testEthAddress(t, 3),
 				Power:           123456,
 			},
 		},
@@ -61,25 +66,26 @@ func TestEncodeValsetUpdate(t *testing.T) {
 		Height: 2222,
 		Members: []types.BridgeValidator{
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x0").Hex(),
+				EthereumAddress: testEthAddress(t, 1),
 				Power:           1111111111,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x1").Hex(),
+				EthereumAddress: testEthAddress(t, 2),
 				Power:           2212121212,
 			},
 		},
 		RewardAmount: sdk.NewInt(0),
 	}
 
+	checkpoint := EncodeValsetConfirm("defaultgravityid", newValset)
 	confirms := []types.MsgValsetConfirm{
 		{
-			EthAddress: ethcmn.HexToAddress("0x0").Hex(),
-			Signature:  "0xaae54ee7e285fbb0275279143abc4c554e5314e7b417ecac83a5984a964facbaad68866a2841c3e83ddf125a2985566261c4014f9f960ec60253aebcda9513a9b4",
+			EthAddress: testEthAddress(t, 1),
+			Signature:  signConfirm(t, testEthKey(t, 1), checkpoint),
 		},
 		{
-			EthAddress: ethcmn.HexToAddress("0x1").Hex(),
-			Signature:  "0xaae54ee7e285fbb0275279143abc4c554e5314e7b417ecac83a5984a964facbaad68866a2841c3e83ddf125a2985566261c4014f9f960ec60253aebcda9513a9b4",
+			EthAddress: testEthAddress(t, 2),
+			Signature:  signConfirm(t, testEthKey(t, 2), checkpoint),
 		},
 	}
 
@@ -97,7 +103,7 @@ func TestEncodeValsetUpdate(t *testing.T) {
 
 	// Let's check the hash of the TX data instead of the entire thing
 	txDataHash := sha256.Sum256(txData)
-	assert.Equal(t, "f22e880adca043d34ea5af87cb0024e28c0cc0b5767b478ec6eb949705765015", hex.EncodeToString(txDataHash[:]))
+	assert.Equal(t, "e2914a5ba099105f15c7a19428d99763bccea7b1b4125c12e8f9c25bb5e89b99", hex.EncodeToString(txDataHash[:]))
 
 }
 
@@ -135,35 +141,52 @@ func TestCheckValsetSigsAndRepack(t *testing.T) {
 	valset := types.Valset{
 		Members: []types.BridgeValidator{
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x0").Hex(),
+				EthereumAddress: testEthAddress(t, 1),
 				Power:           1111111111,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x1").Hex(),
+				EthereumAddress: testEthAddress(t, 2),
 				Power:           2212121212,
 			},
 			{
-				EthereumAddress: ethcmn.HexToAddress("0x2").Hex(),
+				EthereumAddress: testEthAddress(t, 3),
 				Power:           123456,
 			},
 		},
 	}
 
+	checkpoint := EncodeValsetConfirm("defaultgravityid", types.Valset{Nonce: 2, RewardAmount: sdk.NewInt(0)})
 	confirms := []types.MsgValsetConfirm{
 		{
-			EthAddress: ethcmn.HexToAddress("0x0").Hex(),
-			Signature:  "0xaae54ee7e285fbb0275279143abc4c554e5314e7b417ecac83a5984a964facbaad68866a2841c3e83ddf125a2985566261c4014f9f960ec60253aebcda9513a9b4",
+			EthAddress: testEthAddress(t, 1),
+			Signature:  signConfirm(t, testEthKey(t, 1), checkpoint),
 		},
 		{
-			EthAddress: ethcmn.HexToAddress("0x1").Hex(),
-			Signature:  "0xaae54ee7e285fbb0275279143abc4c554e5314e7b417ecac83a5984a964facbaad68866a2841c3e83ddf125a2985566261c4014f9f960ec60253aebcda9513a9b4",
+			Orchestrator: "umee1bad",
+			EthAddress:   testEthAddress(t, 2),
+			Signature:    signConfirm(t, testEthKey(t, 4), checkpoint),
+		},
+		{
+			EthAddress: testEthAddress(t, 3),
+			Signature:  signConfirm(t, testEthKey(t, 3), checkpoint),
 		},
 	}
 
-	repackedSigs, err := checkValsetSigsAndRepack(valset, confirms)
-	assert.Nil(t, err)
+	// the signature of the second member doesn't match its address, and without its power there's not enough
+	repackedSigs, invalidSigs, err := checkValsetSigsAndRepack(valset, checkpoint, confirms)
+	assert.True(t, errors.Is(err, ErrInsufficientVotingPowerToPass))
+	assert.Len(t, invalidSigs, 1)
+	assert.Equal(t, "umee1bad", invalidSigs[0].Orchestrator)
 
-	assert.Equal(t, []ethcmn.Address{ethcmn.HexToAddress("0x0"), ethcmn.HexToAddress("0x1"), ethcmn.HexToAddress("0x2")}, repackedSigs.validators)
+	expectedValidators := []ethcmn.Address{
+		ethcmn.HexToAddress(testEthAddress(t, 1)),
+		ethcmn.HexToAddress(testEthAddress(t, 2)),
+		ethcmn.HexToAddress(testEthAddress(t, 3)),
+	}
+	assert.Equal(t, expectedValidators, repackedSigs.validators)
 	assert.Equal(t, []*big.Int{big.NewInt(1111111111), big.NewInt(2212121212), big.NewInt(123456)}, repackedSigs.powers)
+	assert.NotEqual(t, ethcmn.Hash{}, repackedSigs.r[0])
+	assert.Equal(t, ethcmn.Hash{}, repackedSigs.r[1])
+	assert.NotEqual(t, ethcmn.Hash{}, repackedSigs.r[2])
 
 }
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// logRangeGrowAfter is the number of consecutive successful eth_getLogs requests spanning the whole allowed range
+	// after which we try to double that range again.
+	logRangeGrowAfter = 10
+
+	// rateLimitRetries is how many times an eth_getLogs request rejected by the rate limits of the provider is retried.
+	rateLimitRetries = 5
+	// defaultRateLimitBackoff is the delay before the first retry of a rate limited request, doubled on every retry.
+	defaultRateLimitBackoff = time.Second
+)
+
+// rangeTooLargeErrs contains the (lowercase) error messages returned by the most common providers when an
+// eth_getLogs query spans too many blocks or would return too many results.
+var rangeTooLargeErrs = []string{
+	"query returned more than",     // Infura
+	"log response size exceeded",   // Alchemy
+	"response size exceeded",       // Alchemy (newer versions)
+	"exceed maximum block range",   // Ankr, BSC
+	"block range is too wide",      // Chainstack
+	"block range too large",        // Pokt
+	"range too large",              // Generic
+	"is limited to a",              // QuickNode
+	"too many results",             // Generic
+	"more than 10000 results",      // Infura (older versions)
+	"requested too many blocks",    // Blast
+	"maximum block range exceeded", // Generic
+}
+
+// rateLimitErrs contains the (lowercase) error messages returned by the most common providers when a request is
+// rejected by their rate limits, whatever its range. These are retried as is instead of being bisected.
+var rateLimitErrs = []string{
+	"rate limit",            // Infura, Alchemy, Generic
+	"request count limit",   // Infura daily limit
+	"too many requests",     // HTTP 429
+	"compute units per",     // Alchemy
+	"capacity exceeded",     // Alchemy
+	"request limit reached", // Generic
+}
+
+// LogFilterer is the subset of an Ethereum client used to fetch logs.
+type LogFilterer interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// adaptiveLogFilterer wraps a LogFilterer so that eth_getLogs queries rejected by the provider for spanning too many
+// blocks (or returning too many results) are bisected automatically. The largest range known to work is remembered,
+// so following queries are chunked upfront, and it grows back as requests keep succeeding.
+type adaptiveLogFilterer struct {
+	filterer LogFilterer
+
+	// rateLimitBackoff is the delay before the first retry of a rate limited request.
+	rateLimitBackoff time.Duration
+
+	mtx       sync.Mutex
+	maxRange  uint64 // 0 means that we haven't hit any limit yet
+	successes int
+}
+
+func newAdaptiveLogFilterer(filterer LogFilterer) *adaptiveLogFilterer {
+	return &adaptiveLogFilterer{
+		filterer:         filterer,
+		rateLimitBackoff: defaultRateLimitBackoff,
+	}
+}
+
+// MaxRange returns the largest block range currently used per eth_getLogs request, zero meaning unlimited.
+func (f *adaptiveLogFilterer) MaxRange() uint64 {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	return f.maxRange
+}
+
+func (f *adaptiveLogFilterer) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	// Queries by block hash or open ended ones can't be split.
+	if q.BlockHash != nil || q.FromBlock == nil || q.ToBlock == nil || q.ToBlock.Cmp(q.FromBlock) < 0 {
+		return f.filterer.FilterLogs(ctx, q)
+	}
+
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+
+	var logs []types.Log
+	for start := from; start <= to; {
+		end := to
+		if maxRange := f.MaxRange(); maxRange > 0 && end-start+1 > maxRange {
+			end = start + maxRange - 1
+		}
+
+		chunk, err := f.filterRange(ctx, q, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		logs = append(logs, chunk...)
+
+		if end == to {
+			break
+		}
+
+		start = end + 1
+	}
+
+	return logs, nil
+}
+
+// filterRange fetches the logs in [start, end], bisecting the range as long as the provider rejects it.
+func (f *adaptiveLogFilterer) filterRange(
+	ctx context.Context,
+	q ethereum.FilterQuery,
+	start, end uint64,
+) ([]types.Log, error) {
+	q.FromBlock = new(big.Int).SetUint64(start)
+	q.ToBlock = new(big.Int).SetUint64(end)
+
+	logs, err := f.filterLogs(ctx, q)
+	if err == nil {
+		f.recordSuccess(end - start + 1)
+		return logs, nil
+	}
+
+	if start == end || !isRangeTooLargeErr(err) {
+		return nil, err
+	}
+
+	f.recordFailure(end - start + 1)
+
+	mid := start + (end-start)/2
+
+	left, err := f.filterRange(ctx, q, start, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := f.filterRange(ctx, q, mid+1, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(left, right...), nil
+}
+
+// filterLogs sends a single eth_getLogs request, retrying it with an exponential backoff while it's rate limited.
+func (f *adaptiveLogFilterer) filterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+
+	err := retry.Do(
+		func() (err error) {
+			logs, err = f.filterer.FilterLogs(ctx, q)
+			return err
+		},
+		retry.Context(ctx),
+		retry.Attempts(rateLimitRetries+1),
+		retry.Delay(f.rateLimitBackoff),
+		retry.DelayType(retry.BackOffDelay),
+		retry.RetryIf(isRateLimitErr),
+		retry.LastErrorOnly(true),
+	)
+
+	return logs, err
+}
+
+func (f *adaptiveLogFilterer) recordSuccess(span uint64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.maxRange == 0 || span < f.maxRange {
+		return
+	}
+
+	f.successes++
+	if f.successes >= logRangeGrowAfter {
+		f.maxRange *= 2
+		f.successes = 0
+	}
+}
+
+func (f *adaptiveLogFilterer) recordFailure(span uint64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	half := span / 2
+	if half == 0 {
+		half = 1
+	}
+
+	if f.maxRange == 0 || half < f.maxRange {
+		f.maxRange = half
+	}
+
+	f.successes = 0
+}
+
+func isRangeTooLargeErr(err error) bool {
+	return !isRateLimitErr(err) && errContainsAny(err, rangeTooLargeErrs)
+}
+
+func isRateLimitErr(err error) bool {
+	return errContainsAny(err, rateLimitErrs)
+}
+
+func errContainsAny(err error, msgs []string) bool {
+	msg := strings.ToLower(err.Error())
+
+	for _, s := range msgs {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLogFilterer returns one log per block and rejects queries spanning more than limit blocks.
+type fakeLogFilterer struct {
+	limit uint64
+	calls [][2]uint64
+}
+
+func (f *fakeLogFilterer) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	f.calls = append(f.calls, [2]uint64{from, to})
+
+	if to-from+1 > f.limit {
+		return nil, errors.New("query returned more than 10000 results")
+	}
+
+	logs := make([]types.Log, 0, to-from+1)
+	for b := from; b <= to; b++ {
+		logs = append(logs, types.Log{BlockNumber: b})
+	}
+
+	return logs, nil
+}
+
+func rangeQuery(from, to uint64) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+	}
+}
+
+func TestAdaptiveLogFilterer(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
+		fake := &fakeLogFilterer{limit: 1000}
+		f := newAdaptiveLogFilterer(fake)
+
+		logs, err := f.FilterLogs(context.Background(), rangeQuery(1, 100))
+		assert.NoError(t, err)
+		assert.Len(t, logs, 100)
+		assert.Len(t, fake.calls, 1)
+		assert.Equal(t, uint64(0), f.MaxRange())
+	})
+
+	t.Run("bisect and remember", func(t *testing.T) {
+		fake := &fakeLogFilterer{limit: 30}
+		f := newAdaptiveLogFilterer(fake)
+
+		logs, err := f.FilterLogs(context.Background(), rangeQuery(1, 100))
+		assert.NoError(t, err)
+		assert.Len(t, logs, 100)
+
+		// logs must come back in order and without gaps
+		for i, l := range logs {
+			assert.Equal(t, uint64(i+1), l.BlockNumber)
+		}
+
+		maxRange := f.MaxRange()
+		assert.NotZero(t, maxRange)
+		assert.LessOrEqual(t, maxRange, uint64(30))
+
+		// the next query is chunked upfront, so no request is rejected
+		fake.calls = nil
+		logs, err = f.FilterLogs(context.Background(), rangeQuery(101, 200))
+		assert.NoError(t, err)
+		assert.Len(t, logs, 100)
+
+		for _, c := range fake.calls {
+			assert.LessOrEqual(t, c[1]-c[0]+1, maxRange)
+		}
+	})
+
+	t.Run("grow back", func(t *testing.T) {
+		fake := &fakeLogFilterer{limit: 10}
+		f := newAdaptiveLogFilterer(fake)
+
+		_, err := f.FilterLogs(context.Background(), rangeQuery(1, 20))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(10), f.MaxRange())
+
+		// the provider is now more permissive
+		fake.limit = 1000
+		_, err = f.FilterLogs(context.Background(), rangeQuery(21, 21+10*logRangeGrowAfter-1))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(20), f.MaxRange())
+	})
+
+	t.Run("other errors are returned", func(t *testing.T) {
+		f := newAdaptiveLogFilterer(&erroringLogFilterer{})
+
+		_, err := f.FilterLogs(context.Background(), rangeQuery(1, 100))
+		assert.EqualError(t, err, "connection refused")
+		assert.Equal(t, uint64(0), f.MaxRange())
+	})
+
+	t.Run("rate limits are retried", func(t *testing.T) {
+		fake := &rateLimitedLogFilterer{failures: 2}
+		f := newAdaptiveLogFilterer(fake)
+		f.rateLimitBackoff = time.Millisecond
+
+		logs, err := f.FilterLogs(context.Background(), rangeQuery(1, 100))
+		assert.NoError(t, err)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, 3, fake.calls)
+		assert.Equal(t, uint64(0), f.MaxRange())
+
+		// the range isn't bisected when the rate limits persist
+		fake = &rateLimitedLogFilterer{failures: rateLimitRetries + 1}
+		f = newAdaptiveLogFilterer(fake)
+		f.rateLimitBackoff = time.Millisecond
+
+		_, err = f.FilterLogs(context.Background(), rangeQuery(1, 100))
+		assert.EqualError(t, err, "daily request count limit exceeded")
+		assert.Equal(t, rateLimitRetries+1, fake.calls)
+		assert.Equal(t, uint64(0), f.MaxRange())
+	})
+
+	t.Run("single block still too large", func(t *testing.T) {
+		fake := &fakeLogFilterer{limit: 0}
+		f := newAdaptiveLogFilterer(fake)
+
+		_, err := f.FilterLogs(context.Background(), rangeQuery(1, 4))
+		assert.Error(t, err)
+		assert.Equal(t, uint64(1), f.MaxRange())
+	})
+}
+
+type erroringLogFilterer struct{}
+
+func (erroringLogFilterer) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return nil, errors.New("connection refused")
+}
+
+// rateLimitedLogFilterer rejects the first failures queries for exceeding its rate limits.
+type rateLimitedLogFilterer struct {
+	failures int
+	calls    int
+}
+
+func (f *rateLimitedLogFilterer) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errors.New("daily request count limit exceeded")
+	}
+
+	return []types.Log{{BlockNumber: q.FromBlock.Uint64()}}, nil
+}
+
+func TestIsRangeTooLargeErr(t *testing.T) {
+	assert.True(t, isRangeTooLargeErr(errors.New("query returned more than 10000 results")))
+	assert.True(t, isRangeTooLargeErr(errors.New("Log response size exceeded. You can make eth_getLogs requests...")))
+	assert.True(t, isRangeTooLargeErr(errors.New("exceed maximum block range: 5000")))
+	assert.True(t, isRangeTooLargeErr(errors.New("eth_getLogs is limited to a 10,000 range")))
+	assert.False(t, isRangeTooLargeErr(errors.New("unknown block")))
+	assert.False(t, isRangeTooLargeErr(errors.New("daily request count limit exceeded, request rate limited")))
+	assert.False(t, isRangeTooLargeErr(errors.New("project ID request rate exceeded: rate limit exceeded")))
+	assert.True(t, isRateLimitErr(errors.New("429 Too Many Requests")))
+}
@@ -21,7 +21,10 @@ type EVMProvider interface {
 	bind.ContractFilterer
 
 	PendingNonceAt(ctx context.Context, account ethcmn.Address) (uint64, error)
+	NonceAt(ctx context.Context, account ethcmn.Address, blockNumber *big.Int) (uint64, error)
+	BalanceAt(ctx context.Context, account ethcmn.Address, blockNumber *big.Int) (*big.Int, error)
 	PendingCodeAt(ctx context.Context, account ethcmn.Address) ([]byte, error)
+	PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error)
 	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
 	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 	TransactionByHash(ctx context.Context, hash ethcmn.Hash) (tx *types.Transaction, isPending bool, err error)
@@ -29,6 +32,23 @@ type EVMProvider interface {
 	SendTransaction(ctx context.Context, tx *types.Transaction) error
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	FeeHistory(
+		ctx context.Context,
+		blockCount uint64,
+		lastBlock *big.Int,
+		rewardPercentiles []float64,
+	) (*FeeHistory, error)
+}
+
+// FeeHistory is the result of eth_feeHistory: the base fee and the priority fees paid at the requested percentiles of
+// a range of blocks.
+type FeeHistory struct {
+	OldestBlock *big.Int
+	// Reward holds the priority fees of every block, one per requested percentile.
+	Reward [][]*big.Int
+	// BaseFee holds the base fee of every block, plus the one of the block after the newest.
+	BaseFee      []*big.Int
+	GasUsedRatio []float64
 }
 
 type EVMProviderWithRet interface {
@@ -39,16 +59,26 @@ type EVMProviderWithRet interface {
 
 type evmProviderWithRet struct {
 	*ethclient.Client
-	rc *rpc.Client
+	rc          *rpc.Client
+	logFilterer *adaptiveLogFilterer
 }
 
 func NewEVMProvider(rc *rpc.Client) EVMProviderWithRet {
+	client := ethclient.NewClient(rc)
+
 	return &evmProviderWithRet{
-		Client: ethclient.NewClient(rc),
-		rc:     rc,
+		Client:      client,
+		rc:          rc,
+		logFilterer: newAdaptiveLogFilterer(client),
 	}
 }
 
+// FilterLogs executes an eth_getLogs query, splitting it into smaller block ranges whenever the provider rejects it
+// for being too large.
+func (p *evmProviderWithRet) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return p.logFilterer.FilterLogs(ctx, q)
+}
+
 func (p *evmProviderWithRet) SendTransactionWithRet(
 	ctx context.Context,
 	tx *types.Transaction,
@@ -68,6 +98,55 @@ func (p *evmProviderWithRet) SendTransactionWithRet(
 	return txHash, nil
 }
 
+// FeeHistory returns the fee history of blockCount blocks up to lastBlock, the latest one if nil.
+func (p *evmProviderWithRet) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	lastBlock *big.Int,
+	rewardPercentiles []float64,
+) (*FeeHistory, error) {
+	var res struct {
+		OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+		Reward       [][]*hexutil.Big `json:"reward"`
+		BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio []float64        `json:"gasUsedRatio"`
+	}
+
+	block := "latest"
+	if lastBlock != nil {
+		block = hexutil.EncodeBig(lastBlock)
+	}
+
+	err := p.rc.CallContext(ctx, &res, "eth_feeHistory", hexutil.Uint64(blockCount), block, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.OldestBlock == nil {
+		return nil, errors.New("empty fee history")
+	}
+
+	history := &FeeHistory{
+		OldestBlock:  res.OldestBlock.ToInt(),
+		Reward:       make([][]*big.Int, len(res.Reward)),
+		BaseFee:      make([]*big.Int, len(res.BaseFee)),
+		GasUsedRatio: res.GasUsedRatio,
+	}
+
+	for i, rewards := range res.Reward {
+		history.Reward[i] = make([]*big.Int, len(rewards))
+		for j, reward := range rewards {
+			history.Reward[i][j] = reward.ToInt()
+		}
+	}
+
+	for i, baseFee := range res.BaseFee {
+		history.BaseFee[i] = baseFee.ToInt()
+	}
+
+	return history, nil
+}
+
 type TransactFunc func(opts *bind.TransactOpts, contract *ethcmn.Address, input []byte) (*types.Transaction, error)
 
 func TransactFn(p EVMProviderWithRet, contractAddress ethcmn.Address, txHashOut *ethcmn.Hash) TransactFunc {
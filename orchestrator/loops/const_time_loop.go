@@ -18,31 +18,52 @@ var ErrGracefulStop = errors.New("stop")
 // has a deadline and cannot run longer than interval itself. There is a
 // protection from panic which could crash adjacent loops.
 func RunLoop(ctx context.Context, logger zerolog.Logger, interval time.Duration, fn func() error) (err error) {
+	return RunLoopWithWakeUp(ctx, logger, interval, nil, fn)
+}
+
+// RunLoopWithWakeUp works just like RunLoop, but an iteration also runs right away every time a signal is received
+// on wakeUp. The interval is then used as a fallback heartbeat, in case no signal is received for a while. A nil
+// wakeUp channel makes it behave exactly like RunLoop.
+func RunLoopWithWakeUp(
+	ctx context.Context,
+	logger zerolog.Logger,
+	interval time.Duration,
+	wakeUp <-chan struct{},
+	fn func() error,
+) (err error) {
 	defer panicRecover(logger, &err)
 
 	delayTimer := time.NewTimer(0)
 	for {
 		select {
 		case <-delayTimer.C:
-			var start = time.Now()
-
-			if fnErr := fn(); fnErr != nil {
-				if fnErr == ErrGracefulStop {
-					return nil
+		case <-wakeUp:
+			if !delayTimer.Stop() {
+				// drain the channel in case the timer fired in the meantime
+				select {
+				case <-delayTimer.C:
+				default:
 				}
-
-				return fnErr
 			}
+		case <-ctx.Done():
+			return nil
+		}
+
+		var start = time.Now()
 
-			if elapsed := time.Since(start); elapsed >= interval {
-				// in case of an overlap, use just interval
-				delayTimer.Reset(interval)
-			} else {
-				delayTimer.Reset(interval - elapsed)
+		if fnErr := fn(); fnErr != nil {
+			if fnErr == ErrGracefulStop {
+				return nil
 			}
 
-		case <-ctx.Done():
-			return nil
+			return fnErr
+		}
+
+		if elapsed := time.Since(start); elapsed >= interval {
+			// in case of an overlap, use just interval
+			delayTimer.Reset(interval)
+		} else {
+			delayTimer.Reset(interval - elapsed)
 		}
 	}
 }
@@ -0,0 +1,17 @@
+package loops
+
+// WakeUp is a signal used to run a loop iteration right away instead of waiting for the next tick (see
+// RunLoopWithWakeUp). Notifications sent while the loop is busy are coalesced into a single one.
+type WakeUp chan struct{}
+
+func NewWakeUp() WakeUp {
+	return make(WakeUp, 1)
+}
+
+// Notify signals the loop without ever blocking the caller.
+func (w WakeUp) Notify() {
+	select {
+	case w <- struct{}{}:
+	default:
+	}
+}
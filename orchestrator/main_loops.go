@@ -9,6 +9,7 @@ import (
 	"github.com/avast/retry-go"
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/umee-network/peggo/orchestrator/loops"
+	"github.com/umee-network/peggo/orchestrator/scheduler"
 )
 
 const (
@@ -23,25 +24,42 @@ const (
 	// Run every approximately 3 Cosmos blocks; so we sign batches and valset updates ASAP but not run these requests
 	// too often that we make too many requests to Cosmos.
 	ethSignerLoopMultiplier = 1
+
+	// confirmRetryAttempts is the number of times the signer tries to get a confirmation committed before leaving it to
+	// the next iteration.
+	confirmRetryAttempts = 5
 )
 
 // Start combines the all major roles required to make
 // up the Orchestrator, all of these are async loops.
+// Only the selected roles are started, see SetRoles.
 func (p *gravityOrchestrator) Start(ctx context.Context) error {
+	if len(p.roles) == 0 {
+		return errors.New("no role selected")
+	}
+
 	var pg loops.ParanoidGroup
 
-	pg.Go(func() error {
-		return p.EthOracleMainLoop(ctx)
-	})
-	pg.Go(func() error {
-		return p.BatchRequesterLoop(ctx)
-	})
-	pg.Go(func() error {
-		return p.EthSignerMainLoop(ctx)
-	})
-	pg.Go(func() error {
-		return p.RelayerMainLoop(ctx)
-	})
+	if HasRole(p.roles, RoleOracle) {
+		pg.Go(func() error {
+			return p.EthOracleMainLoop(ctx)
+		})
+	}
+	if HasRole(p.roles, RoleRequester) {
+		pg.Go(func() error {
+			return p.BatchRequesterLoop(ctx)
+		})
+	}
+	if HasRole(p.roles, RoleSigner) {
+		pg.Go(func() error {
+			return p.EthSignerMainLoop(ctx)
+		})
+	}
+	if HasRole(p.roles, RoleRelayer) {
+		pg.Go(func() error {
+			return p.RelayerMainLoop(ctx)
+		})
+	}
 
 	return pg.Wait()
 }
@@ -89,11 +107,35 @@ func (p *gravityOrchestrator) EthOracleMainLoop(ctx context.Context) (err error)
 
 	logger.Info().Uint64("last_checked_block", lastCheckedBlock).Msg("start scanning for events")
 
-	return loops.RunLoop(ctx, p.logger, p.ethereumBlockTime*ethOracleLoopMultiplier, func() error {
+	wakeUp := p.eventScheduler.WakeUpOn(scheduler.EthereumNewHead)
+
+	return loops.RunLoopWithWakeUp(ctx, p.logger, p.ethereumBlockTime*ethOracleLoopMultiplier, wakeUp, func() error {
+		// If we are far behind the Ethereum head (e.g. after some downtime), relay the backlog of events as fast as
+		// Cosmos accepts them before going back to the regular pace.
+		if err := retry.Do(func() (err error) {
+			ethBlockDelay := getEthBlockDelay(gravityParams.BridgeChainId)
+			lastCheckedBlock, err = p.CatchUp(ctx, lastCheckedBlock, ethBlockDelay)
+			if err != nil {
+				lastCheckedBlock = p.rewindOnMissingClaims(ctx, err, lastCheckedBlock, ethBlockDelay)
+			}
+
+			return err
+		}, retry.Context(ctx), retry.OnRetry(func(n uint, err error) {
+			logger.Err(err).Uint("retry", n).Msg("error during Eth events catch up; retrying...")
+		})); err != nil {
+			logger.Err(err).Msg("got error, loop exits")
+			return err
+		}
+
 		// Relays events from Ethereum -> Cosmos
 		var currentBlock uint64
 		if err := retry.Do(func() (err error) {
-			currentBlock, err = p.CheckForEvents(ctx, lastCheckedBlock, getEthBlockDelay(gravityParams.BridgeChainId))
+			ethBlockDelay := getEthBlockDelay(gravityParams.BridgeChainId)
+			currentBlock, err = p.CheckForEvents(ctx, lastCheckedBlock, ethBlockDelay)
+			if err != nil {
+				lastCheckedBlock = p.rewindOnMissingClaims(ctx, err, lastCheckedBlock, ethBlockDelay)
+			}
+
 			return err
 		}, retry.Context(ctx), retry.OnRetry(func(n uint, err error) {
 			logger.Err(err).Uint("retry", n).Msg("error during Eth event checking; retrying...")
@@ -152,7 +194,9 @@ func (p *gravityOrchestrator) EthSignerMainLoop(ctx context.Context) (err error)
 
 	logger.Debug().Str("gravityID", gravityID).Msg("received gravityID")
 
-	return loops.RunLoop(ctx, p.logger, p.cosmosBlockTime*ethSignerLoopMultiplier, func() error {
+	wakeUp := p.eventScheduler.WakeUpOn(scheduler.CosmosValsetRequest, scheduler.CosmosBatchCreated)
+
+	return loops.RunLoopWithWakeUp(ctx, p.logger, p.cosmosBlockTime*ethSignerLoopMultiplier, wakeUp, func() error {
 		var oldestUnsignedValsets []types.Valset
 		if err := retry.Do(func() error {
 			oldestValsets, err := p.cosmosQueryClient.LastPendingValsetRequestByAddr(
@@ -186,13 +230,18 @@ func (p *gravityOrchestrator) EthSignerMainLoop(ctx context.Context) (err error)
 
 			if err := retry.Do(func() error {
 				return p.gravityBroadcastClient.SendValsetConfirm(ctx, p.ethFrom, gravityID, valset)
-			}, retry.Context(ctx), retry.OnRetry(func(n uint, err error) {
+			}, retry.Context(ctx), retry.Attempts(confirmRetryAttempts), retry.OnRetry(func(n uint, err error) {
 				logger.Err(err).
 					Uint("retry", n).
 					Msg("failed to sign and send Valset confirmation to Cosmos; retrying...")
 			})); err != nil {
-				logger.Err(err).Msg("got error, loop exits")
-				return err
+				if ctx.Err() != nil {
+					logger.Err(err).Msg("got error, loop exits")
+					return err
+				}
+
+				// the valset is still pending, we'll try again on the next iteration
+				logger.Err(err).Uint64("valset_nonce", valset.Nonce).Msg("giving up on Valset confirmation for now")
 			}
 		}
 
@@ -233,13 +282,18 @@ func (p *gravityOrchestrator) EthSignerMainLoop(ctx context.Context) (err error)
 				Msg("sending TransactionBatch confirm for BatchNonce")
 			if err := retry.Do(func() error {
 				return p.gravityBroadcastClient.SendBatchConfirm(ctx, p.ethFrom, gravityID, batch)
-			}, retry.Context(ctx), retry.OnRetry(func(n uint, err error) {
+			}, retry.Context(ctx), retry.Attempts(confirmRetryAttempts), retry.OnRetry(func(n uint, err error) {
 				logger.Err(err).
 					Uint("retry", n).
 					Msg("failed to sign and send TransactionBatch confirmation to Cosmos; retrying...")
 			})); err != nil {
-				logger.Err(err).Msg("got error, loop exits")
-				return err
+				if ctx.Err() != nil {
+					logger.Err(err).Msg("got error, loop exits")
+					return err
+				}
+
+				// the batch is still pending, we'll try again on the next iteration
+				logger.Err(err).Uint64("batch_nonce", batch.BatchNonce).Msg("giving up on TransactionBatch confirmation for now")
 			}
 		}
 
@@ -254,7 +308,7 @@ func (p *gravityOrchestrator) BatchRequesterLoop(ctx context.Context) (err error
 		// Each loop performs the following:
 		//
 		// - get All the denominations
-		// - broadcast Request batch
+		// - broadcast Request batch for the ones the batch request policy lets through
 		var pg loops.ParanoidGroup
 
 		pg.Go(func() error {
@@ -278,6 +332,8 @@ func (p *gravityOrchestrator) BatchRequesterLoop(ctx context.Context) (err error
 				return nil
 			}
 
+			pendingDenoms := make(map[string]struct{}, len(unbatchedTokensWithFees))
+
 			for _, unbatchedToken := range unbatchedTokensWithFees {
 				unbatchedToken := unbatchedToken
 				tokenAddr := ethcmn.HexToAddress(unbatchedToken.Token)
@@ -286,16 +342,27 @@ func (p *gravityOrchestrator) BatchRequesterLoop(ctx context.Context) (err error
 				if err != nil {
 					// do not return error, just continue with the next unbatched tx
 					logger.Err(err).Str("token_contract", tokenAddr.String()).Msg("failed to get denom; will not request a batch")
-					return nil
+					continue
+				}
+
+				pendingDenoms[denom] = struct{}{}
+
+				if !p.shouldRequestBatch(ctx, logger, unbatchedToken, denom) {
+					continue
 				}
 
 				logger.Info().Str("token_contract", tokenAddr.String()).Str("denom", denom).Msg("sending batch request")
 
 				if err := p.gravityBroadcastClient.SendRequestBatch(ctx, denom); err != nil {
 					logger.Err(err).Msg("failed to send batch request")
+					continue
 				}
+
+				p.batchRequested(denom)
 			}
 
+			p.forgetBatchedFees(pendingDenoms)
+
 			return nil
 		})
 
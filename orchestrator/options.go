@@ -0,0 +1,46 @@
+package orchestrator
+
+import (
+	"github.com/umee-network/peggo/orchestrator/coingecko"
+	"github.com/umee-network/peggo/orchestrator/scheduler"
+)
+
+func SetEthCatchUpParallelism(n int) func(GravityOrchestrator) {
+	return func(s GravityOrchestrator) { s.SetEthCatchUpParallelism(n) }
+}
+
+func (p *gravityOrchestrator) SetEthCatchUpParallelism(n int) {
+	p.ethCatchUpParallelism = n
+}
+
+func SetEventScheduler(es *scheduler.EventScheduler) func(GravityOrchestrator) {
+	return func(s GravityOrchestrator) { s.SetEventScheduler(es) }
+}
+
+func (p *gravityOrchestrator) SetEventScheduler(es *scheduler.EventScheduler) {
+	p.eventScheduler = es
+}
+
+func SetRoles(roles ...Role) func(GravityOrchestrator) {
+	return func(s GravityOrchestrator) { s.SetRoles(roles...) }
+}
+
+func (p *gravityOrchestrator) SetRoles(roles ...Role) {
+	p.roles = roles
+}
+
+func SetPriceFeeder(pf *coingecko.PriceFeed) func(GravityOrchestrator) {
+	return func(s GravityOrchestrator) { s.SetPriceFeeder(pf) }
+}
+
+func (p *gravityOrchestrator) SetPriceFeeder(pf *coingecko.PriceFeed) {
+	p.priceFeeder = pf
+}
+
+func SetBatchRequestPolicy(policy BatchRequestPolicy) func(GravityOrchestrator) {
+	return func(s GravityOrchestrator) { s.SetBatchRequestPolicy(policy) }
+}
+
+func (p *gravityOrchestrator) SetBatchRequestPolicy(policy BatchRequestPolicy) {
+	p.batchRequestPolicy = policy
+}
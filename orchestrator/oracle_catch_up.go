@@ -0,0 +1,111 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// CatchUp relays the events of the Ethereum blocks between startingBlock and the chain head when the oracle has fallen
+// behind by more than one loop's worth of blocks (e.g. after some downtime). Instead of advancing ethBlocksPerLoop
+// blocks per tick, it fetches several windows of blocks concurrently, reassembles their events in nonce order and
+// sends the claims right away. It returns once the remaining blocks fit in a single window, so the regular polling can
+// take over from there.
+func (p *gravityOrchestrator) CatchUp(
+	ctx context.Context,
+	startingBlock uint64,
+	ethBlockConfirmationDelay uint64,
+) (currentBlock uint64, err error) {
+	logger := p.logger.With().Str("process", "catch_up").Logger()
+	currentBlock = startingBlock
+
+	if p.ethCatchUpParallelism <= 1 || p.ethBlocksPerLoop == 0 {
+		return currentBlock, nil
+	}
+
+	// The first window overlaps with the last checked block, just like CheckForEvents does, the following ones start
+	// right after the last block we have sent the claims for.
+	fromBlock := startingBlock
+
+	for {
+		latestHeader, err := p.ethProvider.HeaderByNumber(ctx, nil)
+		if err != nil {
+			err = errors.Wrap(err, "failed to get latest header")
+			return currentBlock, err
+		}
+
+		// the chain hasn't produced enough blocks to confirm any yet, e.g. on a new testnet
+		if latestHeader.Number.Uint64() < ethBlockConfirmationDelay {
+			return currentBlock, nil
+		}
+
+		// add delay to ensure minimum confirmations are received and block is finalized
+		headBlock := latestHeader.Number.Uint64() - ethBlockConfirmationDelay
+
+		if headBlock <= currentBlock || headBlock-currentBlock <= p.ethBlocksPerLoop {
+			return currentBlock, nil
+		}
+
+		windows := catchUpWindows(fromBlock, headBlock, p.ethBlocksPerLoop, p.ethCatchUpParallelism)
+
+		logger.Info().
+			Uint64("start", windows[0][0]).
+			Uint64("end", windows[len(windows)-1][1]).
+			Uint64("head", headBlock).
+			Int("windows", len(windows)).
+			Msg("oracle is behind; fetching events concurrently")
+
+		results := make([]*ethereumEvents, len(windows))
+		g, gCtx := errgroup.WithContext(ctx)
+
+		for i, w := range windows {
+			i, w := i, w
+
+			g.Go(func() error {
+				events, err := p.getEthereumEvents(gCtx, w[0], w[1])
+				if err != nil {
+					return err
+				}
+
+				results[i] = events
+				return nil
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			return currentBlock, err
+		}
+
+		// Windows are in block order, SendEthereumClaims then sorts the events by nonce.
+		allEvents := &ethereumEvents{}
+		for _, events := range results {
+			allEvents.append(events)
+		}
+
+		if err := p.sendEthereumClaims(ctx, allEvents); err != nil {
+			return currentBlock, err
+		}
+
+		currentBlock = windows[len(windows)-1][1]
+		fromBlock = currentBlock + 1
+	}
+}
+
+// catchUpWindows splits the blocks from start to end in at most maxWindows consecutive windows of up to size blocks
+// each. Windows are returned as [first, last] pairs, both included.
+func catchUpWindows(start, end, size uint64, maxWindows int) [][2]uint64 {
+	windows := make([][2]uint64, 0, maxWindows)
+
+	for from := start; from <= end && len(windows) < maxWindows; {
+		to := from + size - 1
+		if to > end {
+			to = end
+		}
+
+		windows = append(windows, [2]uint64{from, to})
+		from = to + 1
+	}
+
+	return windows
+}
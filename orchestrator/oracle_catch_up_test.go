@@ -0,0 +1,163 @@
+package orchestrator
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/umee-network/peggo/mocks"
+	"github.com/umee-network/peggo/orchestrator/cosmos"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
+	gravity "github.com/umee-network/peggo/orchestrator/ethereum/gravity"
+)
+
+func TestCatchUp(t *testing.T) {
+	fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	newOrchestrator := func(
+		mockCtrl *gomock.Controller,
+		ethProvider *mocks.MockEVMProviderWithRet,
+		mockQClient *mocks.MockQueryClient,
+		parallelism int,
+	) GravityOrchestrator {
+		ethProvider.EXPECT().PendingNonceAt(gomock.Any(), fromAddress).Return(uint64(0), nil)
+		ethCommitter, _ := committer.NewEthCommitter(logger, fromAddress, 1.0, 1.0, nil, ethProvider)
+		gravityContract, _ := gravity.NewGravityContract(logger, ethCommitter, gravityAddress, nil)
+
+		mockCosmos := mocks.NewMockCosmosClient(mockCtrl)
+		mockCosmos.EXPECT().FromAddress().Return(sdk.AccAddress{}).AnyTimes()
+		gravityBroadcastClient := cosmos.NewGravityBroadcastClient(logger, nil, mockCosmos, nil, nil)
+
+		return NewGravityOrchestrator(
+			logger,
+			mockQClient,
+			gravityBroadcastClient,
+			gravityContract,
+			fromAddress,
+			nil,
+			nil,
+			nil,
+			time.Second,
+			time.Second,
+			time.Second,
+			100,
+			SetEthCatchUpParallelism(parallelism),
+		)
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{
+			Number: big.NewInt(1005),
+		}, nil).AnyTimes()
+
+		var (
+			mtx    sync.Mutex
+			ranges = map[[2]uint64]int{}
+		)
+
+		ethProvider.EXPECT().FilterLogs(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, q ethereum.FilterQuery) ([]ethtypes.Log, error) {
+				mtx.Lock()
+				defer mtx.Unlock()
+
+				ranges[[2]uint64{q.FromBlock.Uint64(), q.ToBlock.Uint64()}]++
+				return []ethtypes.Log{}, nil
+			}).AnyTimes()
+
+		mockQClient := mocks.NewMockQueryClient(mockCtrl)
+		mockQClient.EXPECT().LastEventNonceByAddr(gomock.Any(), gomock.Any()).
+			Return(&types.QueryLastEventNonceByAddrResponse{EventNonce: 1}, nil).
+			Times(3)
+
+		orch := newOrchestrator(mockCtrl, ethProvider, mockQClient, 4)
+
+		currentBlock, err := orch.CatchUp(context.Background(), 1, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1000), currentBlock)
+
+		// Every block from 1 to 1000 must be covered once, by windows of 100 blocks, for each one of the 4 events.
+		windows := make([][2]uint64, 0, len(ranges))
+		for r, n := range ranges {
+			assert.Equal(t, 4, n)
+			windows = append(windows, r)
+		}
+
+		sort.Slice(windows, func(i, j int) bool { return windows[i][0] < windows[j][0] })
+		assert.Len(t, windows, 10)
+
+		next := uint64(1)
+		for _, w := range windows {
+			assert.Equal(t, next, w[0])
+			assert.Equal(t, w[0]+99, w[1])
+			next = w[1] + 1
+		}
+	})
+
+	t.Run("close to head", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{
+			Number: big.NewInt(150),
+		}, nil)
+
+		orch := newOrchestrator(mockCtrl, ethProvider, mocks.NewMockQueryClient(mockCtrl), 4)
+
+		currentBlock, err := orch.CatchUp(context.Background(), 100, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100), currentBlock)
+	})
+
+	t.Run("head below the confirmation delay", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{
+			Number: big.NewInt(3),
+		}, nil)
+
+		orch := newOrchestrator(mockCtrl, ethProvider, mocks.NewMockQueryClient(mockCtrl), 4)
+
+		currentBlock, err := orch.CatchUp(context.Background(), 0, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), currentBlock)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		orch := newOrchestrator(mockCtrl, ethProvider, mocks.NewMockQueryClient(mockCtrl), 1)
+
+		currentBlock, err := orch.CatchUp(context.Background(), 1, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), currentBlock)
+	})
+}
+
+func TestCatchUpWindows(t *testing.T) {
+	assert.Equal(t, [][2]uint64{{1, 10}, {11, 20}, {21, 25}}, catchUpWindows(1, 25, 10, 4))
+	assert.Equal(t, [][2]uint64{{1, 10}, {11, 20}}, catchUpWindows(1, 100, 10, 2))
+	assert.Equal(t, [][2]uint64{{5, 5}}, catchUpWindows(5, 5, 10, 2))
+}
@@ -187,3 +187,35 @@ func (p *gravityOrchestrator) GetLastCheckedBlock(
 
 	return 0, errors.New("reached the end of block history without finding the Gravity contract deploy event")
 }
+
+// rewindOnMissingClaims returns the block the oracle should resume scanning from after err. If err reports that some of
+// our claims didn't make it on chain, it's the block of the last event this orchestrator got included, so the missing
+// claims are resubmitted right away instead of waiting for the next auto resync. Otherwise lastCheckedBlock is kept.
+func (p *gravityOrchestrator) rewindOnMissingClaims(
+	ctx context.Context,
+	err error,
+	lastCheckedBlock uint64,
+	ethBlockConfirmationDelay uint64,
+) uint64 {
+	if !errors.Is(err, ErrClaimsNotIncluded) {
+		return lastCheckedBlock
+	}
+
+	block, rErr := p.GetLastCheckedBlock(ctx, ethBlockConfirmationDelay)
+	if rErr != nil {
+		p.logger.Err(rErr).Msg("failed to get last checked block; can't rewind after missing claims")
+		return lastCheckedBlock
+	}
+
+	if block == 0 {
+		block = p.startingEthBlock
+	}
+
+	p.logger.Warn().
+		Err(err).
+		Uint64("from_block", lastCheckedBlock).
+		Uint64("to_block", block).
+		Msg("claims are missing on chain; rewinding to resubmit them")
+
+	return block
+}
@@ -8,21 +8,45 @@ import (
 	gravitytypes "github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/rs/zerolog"
+	"github.com/umee-network/peggo/orchestrator/coingecko"
 	sidechain "github.com/umee-network/peggo/orchestrator/cosmos"
 	gravity "github.com/umee-network/peggo/orchestrator/ethereum/gravity"
 	"github.com/umee-network/peggo/orchestrator/ethereum/keystore"
 	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
 	"github.com/umee-network/peggo/orchestrator/relayer"
+	"github.com/umee-network/peggo/orchestrator/scheduler"
 )
 
+// defaultEthCatchUpParallelism is the default number of Ethereum block windows fetched concurrently by the oracle
+// when catching up.
+const defaultEthCatchUpParallelism = 4
+
 type GravityOrchestrator interface {
 	Start(ctx context.Context) error
 	CheckForEvents(ctx context.Context, startingBlock, ethBlockConfirmationDelay uint64) (currentBlock uint64, err error)
+	CatchUp(ctx context.Context, startingBlock, ethBlockConfirmationDelay uint64) (currentBlock uint64, err error)
 	GetLastCheckedBlock(ctx context.Context, ethBlockConfirmationDelay uint64) (uint64, error)
 	EthOracleMainLoop(ctx context.Context) error
 	EthSignerMainLoop(ctx context.Context) error
 	BatchRequesterLoop(ctx context.Context) error
 	RelayerMainLoop(ctx context.Context) error
+
+	// SetEthCatchUpParallelism sets how many windows of Ethereum blocks are fetched concurrently when the oracle is
+	// catching up with the chain head. A value of 1 or lower disables the catch-up mode.
+	SetEthCatchUpParallelism(n int)
+
+	// SetEventScheduler sets the (optional) event scheduler used to wake up the oracle on new Ethereum blocks and the
+	// signer on new valset requests and batches, instead of only relying on timers.
+	SetEventScheduler(es *scheduler.EventScheduler)
+
+	// SetRoles sets the roles run by the orchestrator, all of them by default.
+	SetRoles(roles ...Role)
+
+	// SetPriceFeeder sets the (optional) price feeder used to value the unbatched fees of the tokens.
+	SetPriceFeeder(pf *coingecko.PriceFeed)
+
+	// SetBatchRequestPolicy sets the policy deciding which tokens batches are requested for, every token by default.
+	SetBatchRequestPolicy(policy BatchRequestPolicy)
 }
 
 type gravityOrchestrator struct {
@@ -40,9 +64,16 @@ type gravityOrchestrator struct {
 	batchRequesterLoopDuration time.Duration
 	startingEthBlock           uint64
 	ethBlocksPerLoop           uint64
+	ethCatchUpParallelism      int
+	eventScheduler             *scheduler.EventScheduler
+	roles                      []Role
+	priceFeeder                *coingecko.PriceFeed
+	batchRequestPolicy         BatchRequestPolicy
 
 	mtx             sync.Mutex
 	erc20DenomCache map[string]string
+	// pendingFeesSince is when the unbatched fees of each denom were first seen by the requester.
+	pendingFeesSince map[string]time.Time
 }
 
 func NewGravityOrchestrator(
@@ -76,6 +107,8 @@ func NewGravityOrchestrator(
 		batchRequesterLoopDuration: batchRequesterLoopDuration,
 		ethBlocksPerLoop:           uint64(ethBlocksPerLoop),
 		startingEthBlock:           uint64(6149808),
+		ethCatchUpParallelism:      defaultEthCatchUpParallelism,
+		roles:                      AllRoles,
 	}
 
 	for _, option := range options {
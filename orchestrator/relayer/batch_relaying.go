@@ -1,18 +1,24 @@
 package relayer
 
 import (
+	"bytes"
 	"context"
 	"math/big"
 	"sort"
 
 	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
 	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
 )
 
 type SubmittableBatch struct {
 	Batch      types.OutgoingTxBatch
 	Signatures []types.MsgConfirmBatch
+	// TxData is the encoded submitBatch call, the invalid signatures already dropped from it.
+	TxData []byte
 }
 
 // getBatchesAndSignatures retrieves the latest batches from the Cosmos module and then iterates through the signatures
@@ -37,10 +43,12 @@ func (s *gravityRelayer) getBatchesAndSignatures(
 		return possibleBatches, nil
 	}
 
+	s.trackBatches(outTxBatches.Batches)
+
 	for _, batch := range outTxBatches.Batches {
 
-		// We might have already sent this same batch. Skip it.
-		if s.lastSentBatchNonce >= batch.BatchNonce {
+		// We might have already sent this same batch or a newer one for the same token. Skip it.
+		if s.lastSentBatchNonces[ethcmn.HexToAddress(batch.TokenContract)] >= batch.BatchNonce {
 			continue
 		}
 
@@ -60,9 +68,9 @@ func (s *gravityRelayer) getBatchesAndSignatures(
 			continue
 		}
 
-		// This checks that the signatures for the batch are actually possible to submit to the chain.
-		// We only need to know if the signatures are good, we won't use the other returned value.
-		_, err = s.gravityContract.EncodeTransactionBatch(ctx, currentValset, batch, batchConfirms.Confirms)
+		// This checks that the signatures for the batch are actually possible to submit to the chain. The tx data is
+		// kept, so the signatures are only checked once.
+		txData, err := s.gravityContract.EncodeTransactionBatch(ctx, currentValset, batch, batchConfirms.Confirms)
 
 		if err != nil {
 			// this batch is not ready to be relayed
@@ -80,7 +88,7 @@ func (s *gravityRelayer) getBatchesAndSignatures(
 		// if the previous check didn't fail, we can add the batch to the list of possible batches
 		possibleBatches[ethcmn.HexToAddress(batch.TokenContract)] = append(
 			possibleBatches[ethcmn.HexToAddress(batch.TokenContract)],
-			SubmittableBatch{Batch: batch, Signatures: batchConfirms.Confirms},
+			SubmittableBatch{Batch: batch, Signatures: batchConfirms.Confirms, TxData: txData},
 		)
 	}
 
@@ -97,9 +105,10 @@ func (s *gravityRelayer) getBatchesAndSignatures(
 
 // RelayBatches attempts to submit batches with valid signatures, checking the state of the Ethereum chain to ensure
 // that it is valid to submit a given batch, more specifically that the correctly signed batch has not timed out or
-// already been submitted. The goal of this function is to submit batches in chronological order of their creation.
-// This function estimates the cost of submitting a batch before submitting it to Ethereum, if it is determined that
-// the ETH cost to submit is too high the batch will be skipped and a later, more profitable, batch may be submitted.
+// already been submitted. Relaying a batch invalidates the older batches of the same token, so every submittable batch
+// is scored first (the fees it pays minus the cost of relaying it, and how close it is to timing out) and only the
+// best one per token is considered. Then the set of batches across all tokens that maximizes the profit within the gas
+// budget of the loop is relayed, the most urgent ones first.
 // Keep in mind that many other relayers are making this same computation and some may have different standards for
 // their profit margin, therefore there may be a race not only to submit individual batches but also batches in
 // different orders.
@@ -117,12 +126,31 @@ func (s *gravityRelayer) RelayBatches(
 
 	ethBlockHeight := lastEthereumHeader.Number.Uint64()
 
-	for tokenContract, batches := range possibleBatches {
+	// the timeouts are only reported, so the batches are relayed anyway
+	if err := s.checkBatchTimeouts(ctx, ethBlockHeight); err != nil {
+		s.logger.Err(err).Msg("failed to check batch timeouts")
+	}
+
+	// Iterate the tokens in a deterministic order.
+	tokenContracts := make([]ethcmn.Address, 0, len(possibleBatches))
+	for tokenContract := range possibleBatches {
+		tokenContracts = append(tokenContracts, tokenContract)
+	}
+
+	sort.Slice(tokenContracts, func(i, j int) bool {
+		return bytes.Compare(tokenContracts[i].Bytes(), tokenContracts[j].Bytes()) < 0
+	})
 
-		// Requests data from Ethereum only once per token type, this is valid because we are
-		// iterating from oldest to newest, so submitting a batch earlier in the loop won't
-		// ever invalidate submitting a batch later in the loop. Another relayer could always
-		// do that though.
+	// learn from the batches mined since the last loop before predicting the gas of the new ones
+	s.batchGasModel.calibrate(ctx, s.ethProvider)
+
+	var (
+		candidates []*batchCandidate
+		gasPrices  = map[gasprice.Urgency]*big.Int{}
+	)
+
+	for _, tokenContract := range tokenContracts {
+		// Requests data from Ethereum only once per token type, we'll send at most one batch per token.
 		latestEthereumBatch, err := s.gravityContract.GetTxBatchNonce(
 			ctx,
 			tokenContract,
@@ -133,8 +161,9 @@ func (s *gravityRelayer) RelayBatches(
 			return err
 		}
 
-		// now we iterate through batches per token type
-		for _, batch := range batches {
+		var tokenCandidates []*batchCandidate
+
+		for _, batch := range possibleBatches[tokenContract] {
 			if batch.Batch.BatchTimeout < ethBlockHeight {
 				s.logger.Debug().
 					Uint64("batch_nonce", batch.Batch.BatchNonce).
@@ -150,47 +179,85 @@ func (s *gravityRelayer) RelayBatches(
 				continue
 			}
 
-			txData, err := s.gravityContract.EncodeTransactionBatch(ctx, currentValset, batch.Batch, batch.Signatures)
+			candidate, err := s.scoreBatch(ctx, currentValset, tokenContract, batch, ethBlockHeight, gasPrices)
 			if err != nil {
 				return err
 			}
 
-			estimatedGasCost, gasPrice, err := s.gravityContract.EstimateGas(ctx, s.gravityContract.Address(), txData)
-			if err != nil {
-				s.logger.Err(err).Msg("failed to estimate gas cost")
-				return err
-			}
-
 			// If the batch is not profitable, move on to the next one.
-			if !s.IsBatchProfitable(ctx, batch.Batch, estimatedGasCost, gasPrice, s.profitMultiplier) {
+			if candidate == nil {
 				continue
 			}
 
-			// Checking in pending txs(mempool) if tx with same input is already submitted
-			// We have to check this at the last moment because any other relayer could have submitted.
-			if s.gravityContract.IsPendingTxInput(txData, s.pendingTxWait) {
+			tokenCandidates = append(tokenCandidates, candidate)
+		}
+
+		if best := bestTokenBatch(tokenCandidates); best != nil {
+			if len(tokenCandidates) > 1 {
 				s.logger.Debug().
-					Msg("Transaction with same batch input data is already present in mempool")
-				continue
+					Str("token_contract", tokenContract.Hex()).
+					Uint64("batch_nonce", best.Batch.BatchNonce).
+					Int("superseded_batches", len(tokenCandidates)-1).
+					Msg("picked the best batch for token")
 			}
 
-			s.logger.Info().
-				Uint64("latest_batch", batch.Batch.BatchNonce).
-				Uint64("latest_ethereum_batch", latestEthereumBatch.Uint64()).
-				Msg("we have detected a newer profitable batch; sending an update")
+			candidates = append(candidates, best)
+		}
+	}
 
-			txHash, err := s.gravityContract.SendTx(ctx, s.gravityContract.Address(), txData, estimatedGasCost, gasPrice)
-			if err != nil {
-				s.logger.Err(err).Str("tx_hash", txHash.Hex()).Msg("failed to sign and submit (Gravity submitBatch) to EVM")
-				return err
+	for _, batch := range selectBatches(candidates, s.batchGasBudget) {
+		// Checking in pending txs(mempool) if tx with same input is already submitted
+		// We have to check this at the last moment because any other relayer could have submitted.
+		if s.gravityContract.IsPendingTxInput(batch.txData, s.pendingTxWait) {
+			s.logger.Debug().
+				Msg("Transaction with same batch input data is already present in mempool")
+			continue
+		}
+
+		s.logger.Info().
+			Uint64("latest_batch", batch.Batch.BatchNonce).
+			Str("token_contract", batch.tokenContract.Hex()).
+			Str("expected_profit", batch.profit.String()).
+			Uint64("blocks_to_timeout", batch.blocksToTimeout).
+			Msg("we have detected a newer profitable batch; sending an update")
+
+		txHash, err := s.gravityContract.SendTx(
+			ctx,
+			s.gravityContract.Address(),
+			batch.txData,
+			batch.gasLimit,
+			batch.gasPrice,
+		)
+		if isTxDeferred(err) {
+			// every other batch would be above the gas price ceiling too, but a cheaper one may still fit the budgets
+			if errors.Is(err, committer.ErrGasPriceCeiling) {
+				s.logger.Warn().Err(err).Msg("deferring batches; the gas price is above the ceiling")
+				return nil
 			}
 
-			s.logger.Info().Str("tx_hash", txHash.Hex()).Msg("sent Tx (Gravity submitBatch)")
+			s.logger.Warn().
+				Err(err).
+				Uint64("batch_nonce", batch.Batch.BatchNonce).
+				Str("token_contract", batch.tokenContract.Hex()).
+				Msg("deferring batch; the tx is beyond the spend limits")
 
-			// update our local tracker of the latest batch
-			s.lastSentBatchNonce = batch.Batch.BatchNonce
+			continue
 		}
+		if err != nil {
+			s.logger.Err(err).Str("tx_hash", txHash.Hex()).Msg("failed to sign and submit (Gravity submitBatch) to EVM")
+			return err
+		}
+
+		s.logger.Info().Str("tx_hash", txHash.Hex()).Msg("sent Tx (Gravity submitBatch)")
 
+		s.batchGasModel.track(txHash, batch.gasCall)
+
+		// update our local tracker of the latest batch
+		if s.lastSentBatchNonces == nil {
+			s.lastSentBatchNonces = map[ethcmn.Address]uint64{}
+		}
+
+		s.lastSentBatchNonces[batch.tokenContract] = batch.Batch.BatchNonce
 	}
 
 	return nil
@@ -210,17 +277,45 @@ func (s *gravityRelayer) IsBatchProfitable(
 		return true
 	}
 
+	totalFeeInUSDDec, gasCostInUSDDec, err := s.batchValueUSD(ctx, batch, ethGasCost, gasPrice)
+	if err != nil {
+		s.logger.Err(err).Str("token_contract", batch.TokenContract).Msg("failed to price batch")
+		return false
+	}
+
+	// Simplified: totalFee > (gasCost * profitMultiplier).
+	isProfitable := totalFeeInUSDDec.GreaterThanOrEqual(gasCostInUSDDec.Mul(decimal.NewFromFloat(profitMultiplier)))
+
+	s.logger.Debug().
+		Str("token_contract", batch.TokenContract).
+		Float64("total_fee_in_usd", totalFeeInUSDDec.InexactFloat64()).
+		Float64("gas_cost_in_usd", gasCostInUSDDec.InexactFloat64()).
+		Float64("profit_multiplier", profitMultiplier).
+		Bool("is_profitable", isProfitable).
+		Msg("checking if batch is profitable")
+
+	return isProfitable
+}
+
+// batchValueUSD gets the current prices in USD of ETH and the ERC20 token and returns the fees paid by the batch and
+// the estimated gas cost of relaying it, both in USD.
+func (s *gravityRelayer) batchValueUSD(
+	ctx context.Context,
+	batch types.OutgoingTxBatch,
+	ethGasCost uint64,
+	gasPrice *big.Int,
+) (totalFeeInUSDDec, gasCostInUSDDec decimal.Decimal, err error) {
 	// First we get the cost of the transaction in USD
 	usdEthPrice, err := s.priceFeeder.QueryETHUSDPrice()
 	if err != nil {
-		s.logger.Err(err).Msg("failed to get ETH price")
-		return false
+		err = errors.Wrap(err, "failed to get ETH price")
+		return decimal.Zero, decimal.Zero, err
 	}
 	usdEthPriceDec := decimal.NewFromFloat(usdEthPrice)
 	totalETHcost := big.NewInt(0).Mul(gasPrice, big.NewInt(int64(ethGasCost)))
 
 	// Ethereum decimals are 18 and that's a constant.
-	gasCostInUSDDec := decimal.NewFromBigInt(totalETHcost, -18).Mul(usdEthPriceDec)
+	gasCostInUSDDec = decimal.NewFromBigInt(totalETHcost, -18).Mul(usdEthPriceDec)
 
 	// Then we get the fees of the batch in USD
 	decimals, err := s.gravityContract.GetERC20Decimals(
@@ -229,8 +324,8 @@ func (s *gravityRelayer) IsBatchProfitable(
 		s.gravityContract.FromAddress(),
 	)
 	if err != nil {
-		s.logger.Err(err).Str("token_contract", batch.TokenContract).Msg("failed to get token decimals")
-		return false
+		err = errors.Wrap(err, "failed to get token decimals")
+		return decimal.Zero, decimal.Zero, err
 	}
 
 	s.logger.Debug().
@@ -240,32 +335,22 @@ func (s *gravityRelayer) IsBatchProfitable(
 
 	usdTokenPrice, err := s.priceFeeder.QueryUSDPrice(ethcmn.HexToAddress(batch.TokenContract))
 	if err != nil {
-		return false
+		err = errors.Wrap(err, "failed to get token price")
+		return decimal.Zero, decimal.Zero, err
 	}
 
 	// We calculate the total fee in ERC20 tokens
-	totalBatchFees := big.NewInt(0)
-	for _, tx := range batch.Transactions {
-		totalBatchFees = totalBatchFees.Add(tx.Erc20Fee.Amount.BigInt(), totalBatchFees)
-	}
+	totalBatchFees := batchTotalFees(batch)
 
 	usdTokenPriceDec := decimal.NewFromFloat(usdTokenPrice)
 	// Decimals (uint8) can be safely casted into int32 because the max uint8 is 255 and the max int32 is 2147483647.
-	totalFeeInUSDDec := decimal.NewFromBigInt(totalBatchFees, -int32(decimals)).Mul(usdTokenPriceDec)
-
-	// Simplified: totalFee > (gasCost * profitMultiplier).
-	isProfitable := totalFeeInUSDDec.GreaterThanOrEqual(gasCostInUSDDec.Mul(decimal.NewFromFloat(profitMultiplier)))
+	totalFeeInUSDDec = decimal.NewFromBigInt(totalBatchFees, -int32(decimals)).Mul(usdTokenPriceDec)
 
 	s.logger.Debug().
 		Str("token_contract", batch.TokenContract).
 		Float64("token_price_in_usd", usdTokenPrice).
 		Int64("total_fees", totalBatchFees.Int64()).
-		Float64("total_fee_in_usd", totalFeeInUSDDec.InexactFloat64()).
-		Float64("gas_cost_in_usd", gasCostInUSDDec.InexactFloat64()).
-		Float64("profit_multiplier", profitMultiplier).
-		Bool("is_profitable", isProfitable).
-		Msg("checking if batch is profitable")
-
-	return isProfitable
+		Msg("priced batch fees")
 
+	return totalFeeInUSDDec, gasCostInUSDDec, nil
 }
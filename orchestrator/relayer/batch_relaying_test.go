@@ -202,7 +202,7 @@ func TestGetBatchesAndSignatures(t *testing.T) {
 
 		mockGravityContract.EXPECT().
 			EncodeTransactionBatch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-			Return(nil, nil).Times(2)
+			Return([]byte{1}, nil).Times(2)
 
 		relayer := gravityRelayer{
 			logger:            logger,
@@ -214,6 +214,11 @@ func TestGetBatchesAndSignatures(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, submittableBatches[ethcmn.HexToAddress("0x0")], 2)
 
+		// the encoded batches are kept, so they aren't encoded again when relayed
+		for _, batch := range submittableBatches[ethcmn.HexToAddress("0x0")] {
+			assert.Equal(t, []byte{1}, batch.TxData)
+		}
+
 	})
 
 	t.Run("not ready to be relayed, no error", func(t *testing.T) {
@@ -393,9 +398,9 @@ func TestRelayBatches(t *testing.T) {
 
 		mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
 		mockGravityContract.EXPECT().GetTxBatchNonce(gomock.Any(), gomock.Any(), gomock.Any()).Return(big.NewInt(1), nil)
-		mockGravityContract.EXPECT().EncodeTransactionBatch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{}, nil)
 		mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
-		mockGravityContract.EXPECT().EstimateGas(gomock.Any(), gomock.Any(), gomock.Any()).Return(uint64(99999), big.NewInt(1), nil)
+		mockGravityContract.EXPECT().SimulateTx(gomock.Any(), []byte{}).Return(nil)
+		mockGravityContract.EXPECT().EstimateGas(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(uint64(99999), big.NewInt(1), nil)
 		mockGravityContract.EXPECT().IsPendingTxInput(gomock.Any(), gomock.Any()).Return(false)
 
 		mockGravityContract.EXPECT().SendTx(
@@ -421,13 +426,73 @@ func TestRelayBatches(t *testing.T) {
 						BatchNonce:   2,
 					},
 					Signatures: []types.MsgConfirmBatch{},
+					TxData:     []byte{},
+				},
+			},
+		}
+
+		err := relayer.RelayBatches(context.Background(), types.Valset{}, possibleBatches)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), relayer.lastSentBatchNonces[ethcmn.HexToAddress("0x0")])
+	})
+
+	t.Run("failed timeout check, still relayed", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+		ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+		mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+
+		gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+		fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+		trackedToken := ethcmn.HexToAddress("0x1")
+
+		ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{
+			Number: big.NewInt(112),
+		}, nil)
+
+		mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+		mockGravityContract.EXPECT().
+			GetTxBatchNonce(gomock.Any(), trackedToken, gomock.Any()).
+			Return(nil, errors.New("rpc error"))
+		mockGravityContract.EXPECT().
+			GetTxBatchNonce(gomock.Any(), ethcmn.HexToAddress("0x0"), gomock.Any()).
+			Return(big.NewInt(1), nil)
+		mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+		mockGravityContract.EXPECT().SimulateTx(gomock.Any(), []byte{}).Return(nil)
+		mockGravityContract.EXPECT().EstimateGas(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(uint64(99999), big.NewInt(1), nil)
+		mockGravityContract.EXPECT().IsPendingTxInput(gomock.Any(), gomock.Any()).Return(false)
+		mockGravityContract.EXPECT().
+			SendTx(gomock.Any(), gravityAddress, []byte{}, uint64(99999), big.NewInt(1)).
+			Return(ethcmn.HexToHash("0x01010101"), nil)
+
+		relayer := gravityRelayer{
+			logger:                    logger,
+			gravityContract:           mockGravityContract,
+			ethProvider:               ethProvider,
+			batchTimeoutWarningBlocks: 10,
+			trackedBatches: map[batchKey]*trackedBatch{
+				{tokenContract: trackedToken, nonce: 5}: {
+					batch: types.OutgoingTxBatch{TokenContract: trackedToken.Hex(), BatchNonce: 5, BatchTimeout: 115},
+				},
+			},
+		}
+
+		possibleBatches := map[ethcmn.Address][]SubmittableBatch{
+			ethcmn.HexToAddress("0x0"): {
+				{
+					Batch: types.OutgoingTxBatch{
+						BatchTimeout: 113,
+						BatchNonce:   2,
+					},
+					Signatures: []types.MsgConfirmBatch{},
+					TxData:     []byte{},
 				},
 			},
 		}
 
 		err := relayer.RelayBatches(context.Background(), types.Valset{}, possibleBatches)
 		assert.NoError(t, err)
-		assert.Equal(t, uint64(2), relayer.lastSentBatchNonce)
+		assert.Equal(t, uint64(2), relayer.lastSentBatchNonces[ethcmn.HexToAddress("0x0")])
 	})
 
 	t.Run("batch timeout, no error", func(t *testing.T) {
@@ -468,6 +533,6 @@ func TestRelayBatches(t *testing.T) {
 
 		err := relayer.RelayBatches(context.Background(), types.Valset{}, possibleBatches)
 		assert.NoError(t, err)
-		assert.Equal(t, uint64(0), relayer.lastSentBatchNonce)
+		assert.Empty(t, relayer.lastSentBatchNonces)
 	})
 }
@@ -0,0 +1,323 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
+)
+
+// maxExactBatchSelection is the maximum number of candidates for which the best set of batches under the gas budget is
+// searched exhaustively. Above it, batches are picked greedily by profit per unit of gas.
+const maxExactBatchSelection = 20
+
+// batchCandidate is a batch that can be relayed right now, along with what it would cost and earn us.
+type batchCandidate struct {
+	SubmittableBatch
+
+	tokenContract ethcmn.Address
+	txData        []byte
+	gasLimit      uint64
+	gasPrice      *big.Int
+
+	// totalFees is the sum of the fees of the batch txs, in token units.
+	totalFees *big.Int
+	// profit is the expected profit of relaying the batch in USD, i.e. the fees minus the gas cost (multiplied by the
+	// profit multiplier, lower for batches close to timing out). When no prices are available every batch is worth
+	// the same.
+	profit decimal.Decimal
+	// blocksToTimeout is the number of Ethereum blocks left before the batch times out.
+	blocksToTimeout uint64
+	// gasCall is what the gas used by the batch depends on, to calibrate the gas model once it's mined.
+	gasCall gasCall
+}
+
+// scoreBatch simulates relaying the batch and estimates its cost and profit. It returns a nil candidate if the batch
+// would revert or is not profitable. The gas prices fetched are kept in gasPrices, so they're fetched once per loop.
+func (s *gravityRelayer) scoreBatch(
+	ctx context.Context,
+	currentValset types.Valset,
+	tokenContract ethcmn.Address,
+	batch SubmittableBatch,
+	ethBlockHeight uint64,
+	gasPrices map[gasprice.Urgency]*big.Int,
+) (*batchCandidate, error) {
+	// the batch was encoded along with the check of its signatures
+	txData := batch.TxData
+
+	blocksToTimeout := batch.Batch.BatchTimeout - ethBlockHeight
+	call := gasCall{
+		validators: len(currentValset.Members),
+		signatures: len(batch.Signatures),
+		transfers:  len(batch.Batch.Transactions),
+	}
+
+	if !s.mayBeProfitable(ctx, batch.Batch, call, blocksToTimeout, gasPrices) {
+		return nil, nil
+	}
+
+	// Make sure the batch would go through before spending any gas on it.
+	if err := s.gravityContract.SimulateTx(ctx, txData); err != nil {
+		var simErr *gravity.SimulationError
+		if !errors.As(err, &simErr) {
+			return nil, err
+		}
+
+		s.logger.Info().
+			Uint64("batch_nonce", batch.Batch.BatchNonce).
+			Str("token_contract", batch.Batch.TokenContract).
+			Stringer("outcome", simErr.Outcome).
+			Str("reason", simErr.Reason).
+			Msg("batch simulation failed; skipping it")
+
+		return nil, nil
+	}
+
+	estimatedGasCost, gasPrice, err := s.gravityContract.EstimateGas(
+		ctx,
+		s.gravityContract.Address(),
+		txData,
+		s.batchGasUrgency(blocksToTimeout),
+	)
+	if err != nil {
+		s.logger.Err(err).Msg("failed to estimate gas cost")
+		return nil, err
+	}
+
+	c := &batchCandidate{
+		SubmittableBatch: batch,
+		tokenContract:    tokenContract,
+		txData:           txData,
+		gasLimit:         estimatedGasCost,
+		gasPrice:         gasPrice,
+		totalFees:        batchTotalFees(batch.Batch),
+		profit:           decimal.NewFromInt(1),
+		blocksToTimeout:  blocksToTimeout,
+		gasCall:          call,
+	}
+
+	if s.priceFeeder == nil || s.profitMultiplier == 0 {
+		return c, nil
+	}
+
+	feesUSD, gasCostUSD, err := s.batchValueUSD(ctx, batch.Batch, estimatedGasCost, gasPrice)
+	if err != nil {
+		s.logger.Err(err).Str("token_contract", batch.Batch.TokenContract).Msg("failed to price batch")
+		return nil, nil
+	}
+
+	profitMultiplier := s.batchProfitMultiplier(c.blocksToTimeout)
+	if profitMultiplier < s.profitMultiplier {
+		s.logger.Debug().
+			Uint64("batch_nonce", batch.Batch.BatchNonce).
+			Str("token_contract", batch.Batch.TokenContract).
+			Uint64("blocks_to_timeout", c.blocksToTimeout).
+			Float64("profit_multiplier", profitMultiplier).
+			Msg("batch is close to timing out; relaying it at a reduced profitability")
+	}
+
+	c.profit = feesUSD.Sub(gasCostUSD.Mul(decimal.NewFromFloat(profitMultiplier)))
+	if c.profit.IsNegative() {
+		return nil, nil
+	}
+
+	return c, nil
+}
+
+// mayBeProfitable is a first pass at the profitability of a batch, pricing the gas predicted by the gas model
+// instead of simulating and estimating the batch over RPC. It only returns false for the batches that aren't
+// profitable even at gasModelMargin of the predicted gas, so the model being off doesn't make us skip profitable
+// batches.
+func (s *gravityRelayer) mayBeProfitable(
+	ctx context.Context,
+	batch types.OutgoingTxBatch,
+	call gasCall,
+	blocksToTimeout uint64,
+	gasPrices map[gasprice.Urgency]*big.Int,
+) bool {
+	if s.priceFeeder == nil || s.profitMultiplier == 0 || s.batchGasModel == nil {
+		return true
+	}
+
+	urgency := s.batchGasUrgency(blocksToTimeout)
+
+	gasPrice, ok := gasPrices[urgency]
+	if !ok {
+		var err error
+		gasPrice, err = s.gravityContract.GasPrice(ctx, urgency)
+		if err != nil {
+			// the batch is still estimated over RPC
+			s.logger.Err(err).Msg("failed to get gas price")
+			return true
+		}
+
+		gasPrices[urgency] = gasPrice
+	}
+
+	predictedGas := uint64(float64(s.batchGasModel.predict(call)) * gasModelMargin)
+
+	feesUSD, gasCostUSD, err := s.batchValueUSD(ctx, batch, predictedGas, gasPrice)
+	if err != nil {
+		s.logger.Err(err).Str("token_contract", batch.TokenContract).Msg("failed to price batch")
+		return true
+	}
+
+	profit := feesUSD.Sub(gasCostUSD.Mul(decimal.NewFromFloat(s.batchProfitMultiplier(blocksToTimeout))))
+	if profit.IsNegative() {
+		s.logger.Debug().
+			Uint64("batch_nonce", batch.BatchNonce).
+			Str("token_contract", batch.TokenContract).
+			Uint64("predicted_gas", predictedGas).
+			Str("expected_profit", profit.String()).
+			Msg("batch isn't profitable at the predicted gas; skipping it")
+
+		return false
+	}
+
+	return true
+}
+
+// bestTokenBatch returns the candidate worth relaying for a single token. Relaying a batch invalidates all the older
+// batches of the same token, so only one of them can be relayed: the most profitable one, and among equally profitable
+// ones, the one paying the most fees and then the one closest to timing out.
+func bestTokenBatch(candidates []*batchCandidate) *batchCandidate {
+	var best *batchCandidate
+
+	for _, c := range candidates {
+		switch {
+		case best == nil:
+			best = c
+		case !c.profit.Equal(best.profit):
+			if c.profit.GreaterThan(best.profit) {
+				best = c
+			}
+		case c.totalFees.Cmp(best.totalFees) != 0:
+			if c.totalFees.Cmp(best.totalFees) > 0 {
+				best = c
+			}
+		case c.blocksToTimeout != best.blocksToTimeout:
+			if c.blocksToTimeout < best.blocksToTimeout {
+				best = c
+			}
+		case c.Batch.BatchNonce > best.Batch.BatchNonce:
+			best = c
+		}
+	}
+
+	return best
+}
+
+// selectBatches returns the set of candidates (at most one per token) that maximizes the total profit without using
+// more than gasBudget gas. A zero gasBudget means there's no limit. The selected batches are sorted by urgency, the
+// ones closest to timing out first.
+func selectBatches(candidates []*batchCandidate, gasBudget uint64) []*batchCandidate {
+	var selected []*batchCandidate
+
+	switch {
+	case gasBudget == 0:
+		selected = append(selected, candidates...)
+	case len(candidates) <= maxExactBatchSelection:
+		selected = selectBatchesExact(candidates, gasBudget)
+	default:
+		selected = selectBatchesGreedy(candidates, gasBudget)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		if selected[i].blocksToTimeout != selected[j].blocksToTimeout {
+			return selected[i].blocksToTimeout < selected[j].blocksToTimeout
+		}
+
+		return selected[i].Batch.BatchNonce < selected[j].Batch.BatchNonce
+	})
+
+	return selected
+}
+
+// selectBatchesExact solves the 0/1 knapsack problem with a depth-first search, pruning the branches that can't beat
+// the best solution found so far even if all their remaining candidates fit in the budget.
+func selectBatchesExact(candidates []*batchCandidate, gasBudget uint64) []*batchCandidate {
+	sorted := append([]*batchCandidate{}, candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].profit.GreaterThan(sorted[j].profit) })
+
+	// remaining[i] is the total profit of the candidates from i onwards
+	remaining := make([]decimal.Decimal, len(sorted)+1)
+	remaining[len(sorted)] = decimal.Zero
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1].Add(sorted[i].profit)
+	}
+
+	var (
+		best       []*batchCandidate
+		bestProfit = decimal.Zero
+		chosen     = make([]*batchCandidate, 0, len(sorted))
+	)
+
+	var search func(i int, gas uint64, profit decimal.Decimal)
+	search = func(i int, gas uint64, profit decimal.Decimal) {
+		if profit.GreaterThan(bestProfit) {
+			bestProfit = profit
+			best = append(best[:0], chosen...)
+		}
+
+		if i == len(sorted) || !profit.Add(remaining[i]).GreaterThan(bestProfit) {
+			return
+		}
+
+		if c := sorted[i]; gas+c.gasLimit <= gasBudget {
+			chosen = append(chosen, c)
+			search(i+1, gas+c.gasLimit, profit.Add(c.profit))
+			chosen = chosen[:len(chosen)-1]
+		}
+
+		search(i+1, gas, profit)
+	}
+
+	search(0, 0, decimal.Zero)
+
+	return best
+}
+
+// selectBatchesGreedy picks the candidates with the highest profit per unit of gas while they fit in the budget.
+func selectBatchesGreedy(candidates []*batchCandidate, gasBudget uint64) []*batchCandidate {
+	sorted := append([]*batchCandidate{}, candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return profitPerGas(sorted[i]).GreaterThan(profitPerGas(sorted[j]))
+	})
+
+	var (
+		selected []*batchCandidate
+		gas      uint64
+	)
+
+	for _, c := range sorted {
+		if gas+c.gasLimit <= gasBudget {
+			selected = append(selected, c)
+			gas += c.gasLimit
+		}
+	}
+
+	return selected
+}
+
+func profitPerGas(c *batchCandidate) decimal.Decimal {
+	if c.gasLimit == 0 {
+		return c.profit
+	}
+
+	return c.profit.Div(decimal.NewFromInt(int64(c.gasLimit)))
+}
+
+func batchTotalFees(batch types.OutgoingTxBatch) *big.Int {
+	totalFees := big.NewInt(0)
+	for _, tx := range batch.Transactions {
+		totalFees = totalFees.Add(tx.Erc20Fee.Amount.BigInt(), totalFees)
+	}
+
+	return totalFees
+}
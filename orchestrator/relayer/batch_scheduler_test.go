@@ -0,0 +1,254 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/umee-network/peggo/mocks"
+	gravityMocks "github.com/umee-network/peggo/mocks/gravity"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
+)
+
+func newCandidate(nonce uint64, profit int64, gas uint64, blocksToTimeout uint64) *batchCandidate {
+	return &batchCandidate{
+		SubmittableBatch: SubmittableBatch{Batch: types.OutgoingTxBatch{BatchNonce: nonce}},
+		gasLimit:         gas,
+		totalFees:        big.NewInt(0),
+		profit:           decimal.NewFromInt(profit),
+		blocksToTimeout:  blocksToTimeout,
+	}
+}
+
+func nonces(candidates []*batchCandidate) []uint64 {
+	res := make([]uint64, len(candidates))
+	for i, c := range candidates {
+		res[i] = c.Batch.BatchNonce
+	}
+
+	return res
+}
+
+func TestBestTokenBatch(t *testing.T) {
+	assert.Nil(t, bestTokenBatch(nil))
+
+	// highest profit wins
+	best := bestTokenBatch([]*batchCandidate{newCandidate(1, 10, 0, 5), newCandidate(2, 20, 0, 50)})
+	assert.Equal(t, uint64(2), best.Batch.BatchNonce)
+
+	// same profit, the highest fees win
+	a, b := newCandidate(1, 10, 0, 5), newCandidate(2, 10, 0, 50)
+	b.totalFees = big.NewInt(100)
+	assert.Equal(t, uint64(2), bestTokenBatch([]*batchCandidate{a, b}).Batch.BatchNonce)
+
+	// same profit and fees, the most urgent wins
+	best = bestTokenBatch([]*batchCandidate{newCandidate(1, 10, 0, 50), newCandidate(2, 10, 0, 5)})
+	assert.Equal(t, uint64(2), best.Batch.BatchNonce)
+}
+
+func TestSelectBatches(t *testing.T) {
+	candidates := []*batchCandidate{
+		newCandidate(1, 60, 100, 30),
+		newCandidate(2, 50, 60, 10),
+		newCandidate(3, 50, 60, 20),
+		newCandidate(4, 5, 10, 1),
+	}
+
+	// no budget, everything sorted by urgency
+	assert.Equal(t, []uint64{4, 2, 3, 1}, nonces(selectBatches(candidates, 0)))
+
+	// the two batches of 50 beat the single one of 60
+	assert.Equal(t, []uint64{2, 3}, nonces(selectBatches(candidates, 120)))
+
+	// the cheap one fits too
+	assert.Equal(t, []uint64{4, 2, 3}, nonces(selectBatches(candidates, 130)))
+
+	// nothing fits
+	assert.Empty(t, selectBatches(candidates, 5))
+
+	// greedy picks by profit per gas, unsorted
+	assert.Equal(t, []uint64{2, 3, 4}, nonces(selectBatchesGreedy(candidates, 130)))
+}
+
+func TestRelayBatchesAcrossTokens(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+	ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+
+	gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+	fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	tokenA := ethcmn.HexToAddress("0xa")
+	tokenB := ethcmn.HexToAddress("0xb")
+
+	ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{
+		Number: big.NewInt(100),
+	}, nil)
+
+	mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+	mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+	mockGravityContract.EXPECT().GetTxBatchNonce(gomock.Any(), tokenA, gomock.Any()).Return(big.NewInt(1), nil)
+	mockGravityContract.EXPECT().GetTxBatchNonce(gomock.Any(), tokenB, gomock.Any()).Return(big.NewInt(3), nil)
+
+	mockGravityContract.EXPECT().SimulateTx(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+	mockGravityContract.EXPECT().EstimateGas(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uint64(1000), big.NewInt(1), nil).Times(3)
+	mockGravityContract.EXPECT().IsPendingTxInput(gomock.Any(), gomock.Any()).Return(false).Times(2)
+
+	// only the newest batch of token A is sent, and the only live one of token B
+	mockGravityContract.EXPECT().SendTx(gomock.Any(), gravityAddress, []byte{5}, uint64(1000), big.NewInt(1)).
+		Return(ethcmn.HexToHash("0x01"), nil)
+	mockGravityContract.EXPECT().SendTx(gomock.Any(), gravityAddress, []byte{4}, uint64(1000), big.NewInt(1)).
+		Return(ethcmn.HexToHash("0x02"), nil)
+
+	relayer := gravityRelayer{
+		logger:          logger,
+		gravityContract: mockGravityContract,
+		ethProvider:     ethProvider,
+	}
+
+	// the batch data is its nonce so we can tell them apart
+	possibleBatches := map[ethcmn.Address][]SubmittableBatch{
+		tokenA: {
+			{Batch: types.OutgoingTxBatch{BatchNonce: 5, BatchTimeout: 200, TokenContract: tokenA.Hex()}, TxData: []byte{5}},
+			{Batch: types.OutgoingTxBatch{BatchNonce: 2, BatchTimeout: 300, TokenContract: tokenA.Hex()}, TxData: []byte{2}},
+		},
+		tokenB: {
+			// already relayed
+			{Batch: types.OutgoingTxBatch{BatchNonce: 3, BatchTimeout: 150, TokenContract: tokenB.Hex()}, TxData: []byte{3}},
+			{Batch: types.OutgoingTxBatch{BatchNonce: 4, BatchTimeout: 250, TokenContract: tokenB.Hex()}, TxData: []byte{4}},
+		},
+	}
+
+	err := relayer.RelayBatches(context.Background(), types.Valset{}, possibleBatches)
+	assert.NoError(t, err)
+	assert.Equal(t, map[ethcmn.Address]uint64{tokenA: 5, tokenB: 4}, relayer.lastSentBatchNonces)
+}
+
+func TestRelayBatchesBalanceReserve(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+	ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+
+	gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+	fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	token := ethcmn.HexToAddress("0xa")
+
+	ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{
+		Number: big.NewInt(100),
+	}, nil)
+
+	mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+	mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+	mockGravityContract.EXPECT().GetTxBatchNonce(gomock.Any(), token, gomock.Any()).Return(big.NewInt(1), nil)
+	mockGravityContract.EXPECT().SimulateTx(gomock.Any(), gomock.Any()).Return(nil)
+	mockGravityContract.EXPECT().EstimateGas(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uint64(1000), big.NewInt(1), nil)
+	mockGravityContract.EXPECT().IsPendingTxInput(gomock.Any(), gomock.Any()).Return(false)
+	mockGravityContract.EXPECT().SendTx(gomock.Any(), gravityAddress, []byte{2}, uint64(1000), big.NewInt(1)).
+		Return(ethcmn.Hash{}, errors.Wrap(committer.ErrBalanceReserve, "balance too low"))
+
+	relayer := gravityRelayer{
+		logger:              logger,
+		gravityContract:     mockGravityContract,
+		ethProvider:         ethProvider,
+		lastSentBatchNonces: map[ethcmn.Address]uint64{},
+	}
+
+	possibleBatches := map[ethcmn.Address][]SubmittableBatch{
+		token: {{Batch: types.OutgoingTxBatch{BatchNonce: 2, BatchTimeout: 200, TokenContract: token.Hex()}, TxData: []byte{2}}},
+	}
+
+	// the batch is left for later rather than failing the loop
+	err := relayer.RelayBatches(context.Background(), types.Valset{}, possibleBatches)
+	assert.NoError(t, err)
+	assert.Empty(t, relayer.lastSentBatchNonces)
+}
+
+func TestRelayBatchesSpendLimits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		deferErr error
+	}{
+		{
+			name:     "a cheaper batch may fit the budgets",
+			deferErr: errors.Wrap(committer.ErrSpendLimit, "over the hourly budget"),
+		},
+		{
+			name:     "every batch is above the gas price ceiling",
+			deferErr: errors.Wrap(committer.ErrGasPriceCeiling, "gas price too high"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+			ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+			mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+
+			gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+			fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+			tokenA := ethcmn.HexToAddress("0xa")
+			tokenB := ethcmn.HexToAddress("0xb")
+
+			ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{
+				Number: big.NewInt(100),
+			}, nil)
+
+			mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+			mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+			mockGravityContract.EXPECT().GetTxBatchNonce(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(big.NewInt(1), nil).Times(2)
+			mockGravityContract.EXPECT().SimulateTx(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			mockGravityContract.EXPECT().EstimateGas(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(uint64(1000), big.NewInt(1), nil).Times(2)
+			mockGravityContract.EXPECT().IsPendingTxInput(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
+			// the batch of token A is the most urgent, so it's sent first
+			mockGravityContract.EXPECT().SendTx(gomock.Any(), gravityAddress, []byte{2}, uint64(1000), big.NewInt(1)).
+				Return(ethcmn.Hash{}, tc.deferErr)
+
+			sent := map[ethcmn.Address]uint64{}
+			if !errors.Is(tc.deferErr, committer.ErrGasPriceCeiling) {
+				mockGravityContract.EXPECT().
+					SendTx(gomock.Any(), gravityAddress, []byte{3}, uint64(1000), big.NewInt(1)).
+					Return(ethcmn.HexToHash("0x01"), nil)
+				sent[tokenB] = 3
+			}
+
+			relayer := gravityRelayer{
+				logger:              logger,
+				gravityContract:     mockGravityContract,
+				ethProvider:         ethProvider,
+				lastSentBatchNonces: map[ethcmn.Address]uint64{},
+			}
+
+			possibleBatches := map[ethcmn.Address][]SubmittableBatch{
+				tokenA: {{Batch: types.OutgoingTxBatch{BatchNonce: 2, BatchTimeout: 200, TokenContract: tokenA.Hex()}, TxData: []byte{2}}},
+				tokenB: {{Batch: types.OutgoingTxBatch{BatchNonce: 3, BatchTimeout: 300, TokenContract: tokenB.Hex()}, TxData: []byte{3}}},
+			}
+
+			err := relayer.RelayBatches(context.Background(), types.Valset{}, possibleBatches)
+			assert.NoError(t, err)
+			assert.Equal(t, sent, relayer.lastSentBatchNonces)
+		})
+	}
+}
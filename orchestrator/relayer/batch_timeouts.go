@@ -0,0 +1,184 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
+)
+
+// defaultBatchTimeoutWarningBlocks is the number of blocks before its timeout from which a batch that hasn't been
+// relayed yet is reported, around 2h with 12s blocks.
+const defaultBatchTimeoutWarningBlocks = 600
+
+type batchKey struct {
+	tokenContract ethcmn.Address
+	nonce         uint64
+}
+
+// trackedBatch is an outgoing batch watched until it's relayed or it times out.
+type trackedBatch struct {
+	batch types.OutgoingTxBatch
+	// warned is set once the batch has been reported as close to timing out.
+	warned bool
+}
+
+// trackBatches starts watching the outgoing batches that weren't tracked yet. Batches are only dropped once their
+// timeout has passed, as they leave the Cosmos store both when they are relayed and when they time out.
+func (s *gravityRelayer) trackBatches(batches []types.OutgoingTxBatch) {
+	if s.trackedBatches == nil {
+		s.trackedBatches = map[batchKey]*trackedBatch{}
+	}
+
+	for _, batch := range batches {
+		key := batchKey{tokenContract: ethcmn.HexToAddress(batch.TokenContract), nonce: batch.BatchNonce}
+		if _, ok := s.trackedBatches[key]; !ok {
+			s.trackedBatches[key] = &trackedBatch{batch: batch}
+		}
+	}
+}
+
+// checkBatchTimeouts goes through the tracked batches, warning about the ones getting close to their timeout without
+// having been relayed, and reporting along with their transfers the ones that timed out. The transfers of a timed out
+// batch are returned to the pool on Cosmos, so they'll be in a later batch or refunded if their senders cancel them.
+func (s *gravityRelayer) checkBatchTimeouts(ctx context.Context, ethBlockHeight uint64) error {
+	keys := make([]batchKey, 0, len(s.trackedBatches))
+	for key := range s.trackedBatches {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].tokenContract != keys[j].tokenContract {
+			return bytes.Compare(keys[i].tokenContract.Bytes(), keys[j].tokenContract.Bytes()) < 0
+		}
+
+		return keys[i].nonce < keys[j].nonce
+	})
+
+	// the latest batch nonces on Ethereum, only requested for the tokens with a batch close to or past its timeout
+	ethBatchNonces := map[ethcmn.Address]uint64{}
+
+	relayed := func(key batchKey) (bool, error) {
+		nonce, ok := ethBatchNonces[key.tokenContract]
+		if !ok {
+			latestEthereumBatch, err := s.gravityContract.GetTxBatchNonce(
+				ctx,
+				key.tokenContract,
+				s.gravityContract.FromAddress(),
+			)
+			if err != nil {
+				err = errors.Wrap(err, "failed to get latest Ethereum batch")
+				return false, err
+			}
+
+			nonce = latestEthereumBatch.Uint64()
+			ethBatchNonces[key.tokenContract] = nonce
+		}
+
+		// relaying a batch also invalidates the older batches of the same token
+		return nonce >= key.nonce, nil
+	}
+
+	for _, key := range keys {
+		tracked := s.trackedBatches[key]
+		batch := tracked.batch
+
+		if batch.BatchTimeout >= ethBlockHeight {
+			blocksToTimeout := batch.BatchTimeout - ethBlockHeight
+			if tracked.warned || blocksToTimeout > s.batchTimeoutWarningBlocks {
+				continue
+			}
+
+			ok, err := relayed(key)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				delete(s.trackedBatches, key)
+				continue
+			}
+
+			s.logger.Warn().
+				Uint64("batch_nonce", batch.BatchNonce).
+				Str("token_contract", batch.TokenContract).
+				Int("transfers", len(batch.Transactions)).
+				Uint64("batch_timeout", batch.BatchTimeout).
+				Uint64("blocks_to_timeout", blocksToTimeout).
+				Msg("batch is close to timing out and hasn't been relayed yet")
+
+			tracked.warned = true
+			continue
+		}
+
+		ok, err := relayed(key)
+		if err != nil {
+			return err
+		}
+
+		delete(s.trackedBatches, key)
+
+		if ok {
+			continue
+		}
+
+		s.reportTimedOutBatch(batch, ethBlockHeight)
+	}
+
+	return nil
+}
+
+func (s *gravityRelayer) reportTimedOutBatch(batch types.OutgoingTxBatch, ethBlockHeight uint64) {
+	s.logger.Error().
+		Uint64("batch_nonce", batch.BatchNonce).
+		Str("token_contract", batch.TokenContract).
+		Int("transfers", len(batch.Transactions)).
+		Uint64("batch_timeout", batch.BatchTimeout).
+		Uint64("eth_block_height", ethBlockHeight).
+		Msg("batch timed out without being relayed")
+
+	for _, tx := range batch.Transactions {
+		s.logger.Warn().
+			Uint64("batch_nonce", batch.BatchNonce).
+			Uint64("transfer_id", tx.Id).
+			Str("sender", tx.Sender).
+			Str("dest_address", tx.DestAddress).
+			Str("amount", tx.Erc20Token.Amount.String()).
+			Str("fee", tx.Erc20Fee.Amount.String()).
+			Str("token_contract", tx.Erc20Token.Contract).
+			Msg("transfer of timed out batch returned to the pool")
+	}
+}
+
+// batchProfitMultiplier returns the profit multiplier required to relay a batch. Within the urgency window before its
+// timeout, it goes down linearly from the configured profit multiplier to the urgent one, so batches about to time out
+// get relayed at a reduced profitability.
+func (s *gravityRelayer) batchProfitMultiplier(blocksToTimeout uint64) float64 {
+	if s.batchUrgencyBlocks == 0 || blocksToTimeout >= s.batchUrgencyBlocks ||
+		s.urgentProfitMultiplier >= s.profitMultiplier {
+		return s.profitMultiplier
+	}
+
+	progress := float64(blocksToTimeout) / float64(s.batchUrgencyBlocks)
+
+	return s.urgentProfitMultiplier + (s.profitMultiplier-s.urgentProfitMultiplier)*progress
+}
+
+// batchGasUrgency returns how urgent relaying a batch is: high once it's within the urgency window, or close enough to
+// its timeout to be reported if there's no such window.
+func (s *gravityRelayer) batchGasUrgency(blocksToTimeout uint64) gasprice.Urgency {
+	window := s.batchUrgencyBlocks
+	if window == 0 {
+		window = s.batchTimeoutWarningBlocks
+	}
+
+	if blocksToTimeout < window {
+		return gasprice.UrgencyHigh
+	}
+
+	return gasprice.UrgencyNormal
+}
@@ -0,0 +1,116 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	gravityMocks "github.com/umee-network/peggo/mocks/gravity"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
+)
+
+func TestCheckBatchTimeouts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	tokenA := ethcmn.HexToAddress("0x000000000000000000000000000000000000000A")
+	tokenB := ethcmn.HexToAddress("0x000000000000000000000000000000000000000B")
+
+	transfer := types.OutgoingTransferTx{
+		Id:          7,
+		Sender:      "umee1sender",
+		DestAddress: "0x000000000000000000000000000000000000dEaD",
+		Erc20Token:  types.ERC20Token{Contract: tokenA.Hex(), Amount: sdk.NewInt(1000)},
+		Erc20Fee:    types.ERC20Token{Contract: tokenA.Hex(), Amount: sdk.NewInt(10)},
+	}
+
+	batches := []types.OutgoingTxBatch{
+		// far from its timeout
+		{BatchNonce: 5, TokenContract: tokenA.Hex(), BatchTimeout: 2000},
+		// close to its timeout and not relayed
+		{BatchNonce: 4, TokenContract: tokenA.Hex(), BatchTimeout: 1050},
+		// timed out unrelayed
+		{BatchNonce: 3, TokenContract: tokenA.Hex(), BatchTimeout: 900, Transactions: []types.OutgoingTransferTx{transfer}},
+		// timed out, but relayed before
+		{BatchNonce: 8, TokenContract: tokenB.Hex(), BatchTimeout: 900},
+	}
+
+	mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+	mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+	// the nonces are requested once per token on each check that needs them
+	mockGravityContract.EXPECT().GetTxBatchNonce(gomock.Any(), tokenA, fromAddress).Return(big.NewInt(2), nil).Times(2)
+	mockGravityContract.EXPECT().GetTxBatchNonce(gomock.Any(), tokenB, fromAddress).Return(big.NewInt(8), nil)
+
+	relayer := gravityRelayer{
+		logger:                    zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}),
+		gravityContract:           mockGravityContract,
+		batchTimeoutWarningBlocks: 100,
+	}
+
+	relayer.trackBatches(batches)
+	assert.Len(t, relayer.trackedBatches, 4)
+
+	assert.NoError(t, relayer.checkBatchTimeouts(context.Background(), 1000))
+	assert.Len(t, relayer.trackedBatches, 2)
+	assert.False(t, relayer.trackedBatches[batchKey{tokenContract: tokenA, nonce: 5}].warned)
+	assert.True(t, relayer.trackedBatches[batchKey{tokenContract: tokenA, nonce: 4}].warned)
+
+	// already tracked batches keep their state, and the warning isn't repeated
+	relayer.trackBatches(batches[:2])
+	assert.NoError(t, relayer.checkBatchTimeouts(context.Background(), 1010))
+	assert.Len(t, relayer.trackedBatches, 2)
+	assert.True(t, relayer.trackedBatches[batchKey{tokenContract: tokenA, nonce: 4}].warned)
+
+	// batch 4 times out
+	assert.NoError(t, relayer.checkBatchTimeouts(context.Background(), 1100))
+	assert.Len(t, relayer.trackedBatches, 1)
+	assert.Contains(t, relayer.trackedBatches, batchKey{tokenContract: tokenA, nonce: 5})
+}
+
+func TestBatchProfitMultiplier(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		urgencyBlocks          uint64
+		urgentProfitMultiplier float64
+		blocksToTimeout        uint64
+		expected               float64
+	}{
+		{name: "urgency disabled", urgencyBlocks: 0, blocksToTimeout: 10, expected: 1.5},
+		{name: "out of the urgency window", urgencyBlocks: 100, blocksToTimeout: 100, expected: 1.5},
+		{name: "halfway", urgencyBlocks: 100, urgentProfitMultiplier: 0.5, blocksToTimeout: 50, expected: 1},
+		{name: "at the timeout", urgencyBlocks: 100, urgentProfitMultiplier: 0.5, blocksToTimeout: 0, expected: 0.5},
+		{name: "urgent multiplier higher", urgencyBlocks: 100, urgentProfitMultiplier: 2, blocksToTimeout: 0, expected: 1.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			relayer := gravityRelayer{
+				profitMultiplier:       1.5,
+				batchUrgencyBlocks:     tc.urgencyBlocks,
+				urgentProfitMultiplier: tc.urgentProfitMultiplier,
+			}
+
+			assert.InDelta(t, tc.expected, relayer.batchProfitMultiplier(tc.blocksToTimeout), 1e-9)
+		})
+	}
+}
+
+func TestBatchGasUrgency(t *testing.T) {
+	relayer := gravityRelayer{batchTimeoutWarningBlocks: 600}
+
+	assert.Equal(t, gasprice.UrgencyNormal, relayer.batchGasUrgency(600))
+	assert.Equal(t, gasprice.UrgencyHigh, relayer.batchGasUrgency(599))
+
+	// the urgency window takes precedence over the timeout warning
+	relayer.batchUrgencyBlocks = 100
+	assert.Equal(t, gasprice.UrgencyNormal, relayer.batchGasUrgency(599))
+	assert.Equal(t, gasprice.UrgencyHigh, relayer.batchGasUrgency(99))
+}
@@ -13,11 +13,10 @@ import (
 
 const defaultBlocksToSearch = 2000
 
-// FindLatestValset finds the latest valset on the Gravity contract by looking back through the event
-// history and finding the most recent ValsetUpdatedEvent. Most of the time this will be very fast
-// as the latest update will be in recent blockchain history and the search moves from the present
-// backwards in time. In the case that the validator set has not been updated for a very long time
-// this will take longer.
+// FindLatestValset returns the latest valset on the Gravity contract. The first time it's called, the valset is found
+// by scanning the event history, and from then on it's kept up to date from the ValsetUpdatedEvent logs emitted since
+// the last call. The valset is always checked against the contract's valset checkpoint, and an error wrapping
+// ErrValsetCheckpointMismatch is returned if they don't match.
 func (s *gravityRelayer) FindLatestValset(ctx context.Context) (*types.Valset, error) {
 	latestHeader, err := s.ethProvider.HeaderByNumber(ctx, nil)
 	if err != nil {
@@ -26,6 +25,36 @@ func (s *gravityRelayer) FindLatestValset(ctx context.Context) (*types.Valset, e
 	}
 	currentBlock := latestHeader.Number.Uint64()
 
+	if s.latestValset != nil {
+		if err := s.updateLatestValset(ctx, currentBlock); err != nil {
+			return nil, err
+		}
+	}
+
+	// bootstrapping, or the tracked valset didn't match the contract's
+	if s.latestValset == nil {
+		valset, err := s.scanLatestValset(ctx, currentBlock)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.verifyValsetCheckpoint(ctx, valset, currentBlock); err != nil {
+			return nil, err
+		}
+
+		s.latestValset = valset
+		s.latestValsetBlock = currentBlock
+	}
+
+	return copyValset(s.latestValset), nil
+}
+
+// scanLatestValset finds the latest valset on the Gravity contract by looking back through the event
+// history and finding the most recent ValsetUpdatedEvent. Most of the time this will be very fast
+// as the latest update will be in recent blockchain history and the search moves from the present
+// backwards in time. In the case that the validator set has not been updated for a very long time
+// this will take longer.
+func (s *gravityRelayer) scanLatestValset(ctx context.Context, currentBlock uint64) (*types.Valset, error) {
 	gravityFilterer, err := wrappers.NewGravityFilterer(s.gravityContract.Address(), s.ethProvider)
 	if err != nil {
 		err = errors.Wrap(err, "failed to init Gravity events filterer")
@@ -86,22 +115,8 @@ func (s *gravityRelayer) FindLatestValset(ctx context.Context) (*types.Valset, e
 
 		// we take only the first event if we find any at all.
 		if len(valsetUpdatedEvents) > 0 {
-			event := valsetUpdatedEvents[0]
-			valset := &types.Valset{
-				Nonce:        event.NewValsetNonce.Uint64(),
-				Members:      make([]types.BridgeValidator, 0, len(event.Powers)),
-				RewardAmount: sdk.NewIntFromBigInt(event.RewardAmount),
-				RewardToken:  event.RewardToken.Hex(),
-			}
-
-			for idx, p := range event.Powers {
-				valset.Members = append(valset.Members, types.BridgeValidator{
-					Power:           p.Uint64(),
-					EthereumAddress: event.Validators[idx].Hex(),
-				})
-			}
-
-			s.checkIfValsetsDiffer(cosmosValset.Valset, valset)
+			valset := valsetFromEvent(valsetUpdatedEvents[0])
+			s.checkIfValsetsDiffer(cosmosValset.Valset, copyValset(valset))
 			return valset, nil
 		}
 
@@ -111,6 +126,32 @@ func (s *gravityRelayer) FindLatestValset(ctx context.Context) (*types.Valset, e
 	return nil, ErrNotFound
 }
 
+// valsetFromEvent returns the valset set in the contract by a ValsetUpdatedEvent, with the members in the same order.
+func valsetFromEvent(event *wrappers.GravityValsetUpdatedEvent) *types.Valset {
+	valset := &types.Valset{
+		Nonce:        event.NewValsetNonce.Uint64(),
+		Members:      make([]types.BridgeValidator, 0, len(event.Powers)),
+		RewardAmount: sdk.NewIntFromBigInt(event.RewardAmount),
+		RewardToken:  event.RewardToken.Hex(),
+	}
+
+	for idx, p := range event.Powers {
+		valset.Members = append(valset.Members, types.BridgeValidator{
+			Power:           p.Uint64(),
+			EthereumAddress: event.Validators[idx].Hex(),
+		})
+	}
+
+	return valset
+}
+
+func copyValset(valset *types.Valset) *types.Valset {
+	c := *valset
+	c.Members = append([]types.BridgeValidator{}, valset.Members...)
+
+	return &c
+}
+
 var ErrNotFound = errors.New("not found")
 
 type GravityValsetUpdatedEvents []*wrappers.GravityValsetUpdatedEvent
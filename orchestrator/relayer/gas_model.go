@@ -0,0 +1,174 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
+)
+
+const (
+	// gasModelCalibrationWeight is the weight of every new receipt in the calibration of a gas model.
+	gasModelCalibrationWeight = 0.2
+	// gasModelMargin is the fraction of the predicted gas a batch must be profitable at to be estimated over RPC, so
+	// the model being off doesn't make us skip profitable batches.
+	gasModelMargin = 0.8
+	// gasModelReceiptTimeout is how long we wait for the receipt of a tx before forgetting about it.
+	gasModelReceiptTimeout = time.Hour
+)
+
+// gasCall holds what the gas used by a call to the Gravity contract depends on.
+type gasCall struct {
+	// validators is the number of validators passed to the contract, whose checkpoint is recomputed.
+	validators int
+	// signatures is the number of signatures the contract may have to check.
+	signatures int
+	// transfers is the number of ERC20 transfers made, for batches.
+	transfers int
+}
+
+type trackedGasCall struct {
+	gasCall
+	sentAt time.Time
+}
+
+// gasModel predicts the gas used by a call to the Gravity contract from its size, linearly. It's calibrated from the
+// receipts of the txs we sent, so a prediction is good enough to tell the batches clearly not worth relaying apart
+// without estimating their gas over RPC. A nil *gasModel predicts nothing.
+type gasModel struct {
+	logger zerolog.Logger
+
+	base         uint64
+	perValidator uint64
+	perSignature uint64
+	perTransfer  uint64
+
+	mtx sync.Mutex
+	// calibration is what the predictions are multiplied by, the moving average of the gas used by our txs over
+	// their predictions.
+	calibration float64
+	sent        map[ethcmn.Hash]trackedGasCall
+}
+
+// newBatchGasModel returns the gas model of submitBatch: every transfer is an ERC20 transfer, mostly to a new holder.
+func newBatchGasModel(logger zerolog.Logger) *gasModel {
+	return &gasModel{
+		logger:       logger.With().Str("gas_model", "submitBatch").Logger(),
+		base:         100000,
+		perValidator: 3000,
+		perSignature: 6500,
+		perTransfer:  35000,
+		calibration:  1,
+		sent:         map[ethcmn.Hash]trackedGasCall{},
+	}
+}
+
+// newValsetGasModel returns the gas model of updateValset, which gets both the current and the new validators.
+func newValsetGasModel(logger zerolog.Logger) *gasModel {
+	return &gasModel{
+		logger:       logger.With().Str("gas_model", "updateValset").Logger(),
+		base:         80000,
+		perValidator: 3000,
+		perSignature: 6500,
+		calibration:  1,
+		sent:         map[ethcmn.Hash]trackedGasCall{},
+	}
+}
+
+// predict returns the gas a call is expected to use, zero for a nil model.
+func (m *gasModel) predict(call gasCall) uint64 {
+	if m == nil {
+		return 0
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return uint64(float64(m.uncalibrated(call)) * m.calibration)
+}
+
+func (m *gasModel) uncalibrated(call gasCall) uint64 {
+	return m.base +
+		m.perValidator*uint64(call.validators) +
+		m.perSignature*uint64(call.signatures) +
+		m.perTransfer*uint64(call.transfers)
+}
+
+// track keeps the call made by a tx we sent, to calibrate the model once it's mined.
+func (m *gasModel) track(txHash ethcmn.Hash, call gasCall) {
+	if m == nil {
+		return
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.sent[txHash] = trackedGasCall{gasCall: call, sentAt: time.Now()}
+}
+
+// observe calibrates the model with the gas a call actually used.
+func (m *gasModel) observe(call gasCall, gasUsed uint64) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	uncalibrated := m.uncalibrated(call)
+
+	ratio := float64(gasUsed) / float64(uncalibrated)
+	m.calibration += gasModelCalibrationWeight * (ratio - m.calibration)
+
+	m.logger.Debug().
+		Uint64("gas_used", gasUsed).
+		Uint64("predicted_gas", uint64(float64(uncalibrated)*m.calibration)).
+		Float64("calibration", m.calibration).
+		Msg("calibrated gas model")
+}
+
+// calibrate fetches the receipts of the tracked txs that were mined since the last call, calibrating the model with
+// them. The txs that aren't mined after gasModelReceiptTimeout are forgotten.
+func (m *gasModel) calibrate(ctx context.Context, ethProvider provider.EVMProvider) {
+	if m == nil {
+		return
+	}
+
+	m.mtx.Lock()
+	sent := make(map[ethcmn.Hash]trackedGasCall, len(m.sent))
+	for txHash, call := range m.sent {
+		sent[txHash] = call
+	}
+	m.mtx.Unlock()
+
+	for txHash, call := range sent {
+		receipt, err := ethProvider.TransactionReceipt(ctx, txHash)
+		if errors.Is(err, ethereum.NotFound) {
+			if time.Since(call.sentAt) > gasModelReceiptTimeout {
+				m.forget(txHash)
+			}
+
+			continue
+		}
+		if err != nil {
+			m.logger.Err(err).Str("tx_hash", txHash.Hex()).Msg("failed to get tx receipt")
+			continue
+		}
+
+		m.forget(txHash)
+
+		// a reverted tx stops short of what the call normally uses
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			m.observe(call.gasCall, receipt.GasUsed)
+		}
+	}
+}
+
+func (m *gasModel) forget(txHash ethcmn.Hash) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.sent, txHash)
+}
@@ -0,0 +1,73 @@
+package relayer
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/umee-network/peggo/mocks"
+)
+
+func TestGasModelPredict(t *testing.T) {
+	m := newBatchGasModel(zerolog.Nop())
+
+	assert.Equal(t, uint64(100000+3000*4+6500*3+35000*10), m.predict(gasCall{validators: 4, signatures: 3, transfers: 10}))
+
+	m = newValsetGasModel(zerolog.Nop())
+	assert.Equal(t, uint64(80000+3000*8+6500*3), m.predict(gasCall{validators: 8, signatures: 3}))
+
+	// a nil model predicts nothing
+	var nilModel *gasModel
+	assert.Zero(t, nilModel.predict(gasCall{validators: 4}))
+	nilModel.track(ethcmn.HexToHash("0x01"), gasCall{})
+	nilModel.calibrate(context.Background(), nil)
+}
+
+func TestGasModelCalibrate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	m := newValsetGasModel(zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}))
+	call := gasCall{validators: 10, signatures: 4}
+	predicted := m.predict(call)
+
+	var (
+		minedTx    = ethcmn.HexToHash("0x01")
+		revertedTx = ethcmn.HexToHash("0x02")
+		pendingTx  = ethcmn.HexToHash("0x03")
+		droppedTx  = ethcmn.HexToHash("0x04")
+	)
+
+	m.track(minedTx, call)
+	m.track(revertedTx, call)
+	m.track(pendingTx, call)
+	m.track(droppedTx, call)
+	m.sent[droppedTx] = trackedGasCall{gasCall: call, sentAt: time.Now().Add(-2 * gasModelReceiptTimeout)}
+
+	evmProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	evmProvider.EXPECT().TransactionReceipt(gomock.Any(), minedTx).Return(&ethtypes.Receipt{
+		Status:  ethtypes.ReceiptStatusSuccessful,
+		GasUsed: 2 * predicted,
+	}, nil)
+	evmProvider.EXPECT().TransactionReceipt(gomock.Any(), revertedTx).Return(&ethtypes.Receipt{
+		Status:  ethtypes.ReceiptStatusFailed,
+		GasUsed: predicted / 2,
+	}, nil)
+	evmProvider.EXPECT().TransactionReceipt(gomock.Any(), pendingTx).Return(nil, ethereum.NotFound)
+	evmProvider.EXPECT().TransactionReceipt(gomock.Any(), droppedTx).Return(nil, ethereum.NotFound)
+
+	m.calibrate(context.Background(), evmProvider)
+
+	// only the mined tx calibrates the model, and only the pending one is still tracked
+	assert.InDelta(t, 1+gasModelCalibrationWeight, m.calibration, 1e-9)
+	assert.Equal(t, uint64(float64(predicted)*(1+gasModelCalibrationWeight)), m.predict(call))
+	assert.Len(t, m.sent, 1)
+	assert.Contains(t, m.sent, pendingTx)
+}
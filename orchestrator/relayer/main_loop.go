@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
 	"github.com/umee-network/peggo/orchestrator/loops"
 )
 
@@ -24,11 +25,17 @@ func (s *gravityRelayer) Start(ctx context.Context) error {
 	return loops.RunLoop(ctx, s.logger, s.loopDuration, func() error {
 		var (
 			currentValset *types.Valset
+			checkpointErr error
 			err           error
 		)
 
 		err = retry.Do(func() error {
 			currentValset, err = s.FindLatestValset(ctx)
+			if errors.Is(err, ErrValsetCheckpointMismatch) {
+				// retrying won't help, the contract state has to change
+				checkpointErr = err
+				return nil
+			}
 			if err != nil {
 				return errors.New("failed to find latest valset")
 			}
@@ -42,11 +49,34 @@ func (s *gravityRelayer) Start(ctx context.Context) error {
 			s.logger.Panic().Err(err).Msg("exhausted retries to get latest valset")
 		}
 
-		var pg loops.ParanoidGroup
+		// Every tx relayed against a valset that doesn't match the contract would revert.
+		if checkpointErr != nil {
+			logger.Error().Err(checkpointErr).Msg("refusing to relay; valset found on Ethereum doesn't match the contract")
+			return nil
+		}
+
+		// Forget the pending txs that were mined or dropped since, we only skip relaying what's still pending.
+		if err := s.gravityContract.EvictPendingTxs(ctx, s.pendingTxWait); err != nil {
+			logger.Err(err).Msg("failed to evict pending txs")
+		}
+
+		// Retrying won't help either when we run out of funds to pay for the txs.
+		var (
+			pg             loops.ParanoidGroup
+			valsetFundsErr error
+			batchFundsErr  error
+		)
+
 		if s.valsetRelayEnabled {
 			pg.Go(func() error {
 				return retry.Do(func() error {
-					return s.RelayValsets(ctx, *currentValset)
+					err := s.RelayValsets(ctx, *currentValset)
+					if errors.Is(err, committer.ErrInsufficientFunds) {
+						valsetFundsErr = err
+						return nil
+					}
+
+					return err
 				}, retry.Context(ctx), retry.OnRetry(func(n uint, err error) {
 					logger.Err(err).Uint("retry", n).Msg("failed to relay valsets; retrying...")
 				}))
@@ -62,7 +92,13 @@ func (s *gravityRelayer) Start(ctx context.Context) error {
 						return err
 					}
 
-					return s.RelayBatches(ctx, *currentValset, possibleBatches)
+					err = s.RelayBatches(ctx, *currentValset, possibleBatches)
+					if errors.Is(err, committer.ErrInsufficientFunds) {
+						batchFundsErr = err
+						return nil
+					}
+
+					return err
 				}, retry.Context(ctx), retry.OnRetry(func(n uint, err error) {
 					logger.Err(err).Uint("retry", n).Msg("failed to relay tx batches; retrying...")
 				}))
@@ -75,6 +111,18 @@ func (s *gravityRelayer) Start(ctx context.Context) error {
 				return err
 			}
 		}
+
+		for _, fundsErr := range []error{valsetFundsErr, batchFundsErr} {
+			if fundsErr != nil {
+				logger.Error().Err(fundsErr).Msg("every account is out of funds to relay; stopping the relayer")
+				if s.insufficientFundsHandler != nil {
+					s.insufficientFundsHandler(fundsErr)
+				}
+
+				return loops.ErrGracefulStop
+			}
+		}
+
 		return nil
 	})
 }
@@ -9,3 +9,36 @@ func SetPriceFeeder(pf *coingecko.PriceFeed) func(GravityRelayer) {
 func (s *gravityRelayer) SetPriceFeeder(pf *coingecko.PriceFeed) {
 	s.priceFeeder = pf
 }
+
+func SetBatchGasBudget(gas uint64) func(GravityRelayer) {
+	return func(s GravityRelayer) { s.SetBatchGasBudget(gas) }
+}
+
+func (s *gravityRelayer) SetBatchGasBudget(gas uint64) {
+	s.batchGasBudget = gas
+}
+
+func SetBatchTimeoutWarning(blocks uint64) func(GravityRelayer) {
+	return func(s GravityRelayer) { s.SetBatchTimeoutWarning(blocks) }
+}
+
+func (s *gravityRelayer) SetBatchTimeoutWarning(blocks uint64) {
+	s.batchTimeoutWarningBlocks = blocks
+}
+
+func SetBatchUrgency(blocks uint64, urgentProfitMultiplier float64) func(GravityRelayer) {
+	return func(s GravityRelayer) { s.SetBatchUrgency(blocks, urgentProfitMultiplier) }
+}
+
+func (s *gravityRelayer) SetBatchUrgency(blocks uint64, urgentProfitMultiplier float64) {
+	s.batchUrgencyBlocks = blocks
+	s.urgentProfitMultiplier = urgentProfitMultiplier
+}
+
+func SetInsufficientFundsHandler(handler func(error)) func(GravityRelayer) {
+	return func(s GravityRelayer) { s.SetInsufficientFundsHandler(handler) }
+}
+
+func (s *gravityRelayer) SetInsufficientFundsHandler(handler func(error)) {
+	s.insufficientFundsHandler = handler
+}
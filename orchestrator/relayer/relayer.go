@@ -5,8 +5,10 @@ import (
 	"time"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/umee-network/peggo/orchestrator/coingecko"
+	"github.com/umee-network/peggo/orchestrator/ethereum/committer"
 	gravity "github.com/umee-network/peggo/orchestrator/ethereum/gravity"
 	"github.com/umee-network/peggo/orchestrator/ethereum/provider"
 
@@ -29,6 +31,20 @@ type GravityRelayer interface {
 	// SetPriceFeeder sets the (optional) price feeder used when performing profitable
 	// batch calculations.
 	SetPriceFeeder(*coingecko.PriceFeed)
+
+	// SetBatchGasBudget sets the maximum gas spent relaying batches on each loop.
+	SetBatchGasBudget(gas uint64)
+
+	// SetBatchTimeoutWarning sets how many blocks before its timeout a batch that hasn't been relayed is reported.
+	SetBatchTimeoutWarning(blocks uint64)
+
+	// SetBatchUrgency sets the window before their timeout in which batches are relayed at a profit multiplier
+	// going down to urgentProfitMultiplier.
+	SetBatchUrgency(blocks uint64, urgentProfitMultiplier float64)
+
+	// SetInsufficientFundsHandler sets a function called with the error when the relayer stops because it ran out of
+	// funds to pay for the txs.
+	SetInsufficientFundsHandler(handler func(error))
 }
 
 type gravityRelayer struct {
@@ -43,10 +59,34 @@ type gravityRelayer struct {
 	pendingTxWait      time.Duration
 	profitMultiplier   float64
 
+	// batchGasBudget is the maximum gas spent relaying batches per loop, zero means there's no limit.
+	batchGasBudget uint64
+
+	// batchTimeoutWarningBlocks is how many blocks before its timeout a batch that hasn't been relayed is reported.
+	batchTimeoutWarningBlocks uint64
+	// batchUrgencyBlocks is the window before their timeout in which batches are relayed at a lower profit
+	// multiplier, down to urgentProfitMultiplier. Zero disables it.
+	batchUrgencyBlocks     uint64
+	urgentProfitMultiplier float64
+	trackedBatches         map[batchKey]*trackedBatch
+
+	// batchGasModel and valsetGasModel predict the gas used by submitBatch and updateValset, they may be nil.
+	batchGasModel  *gasModel
+	valsetGasModel *gasModel
+
+	// insufficientFundsHandler is called when the relayer stops because it ran out of funds, it may be nil.
+	insufficientFundsHandler func(error)
+
 	// Store locally the last tx this validator made to avoid sending duplicates
 	// or invalid txs.
-	lastSentBatchNonce  uint64
+	lastSentBatchNonces map[ethcmn.Address]uint64
 	lastSentValsetNonce uint64
+
+	// latestValset is the valset on the Gravity contract as of latestValsetBlock, kept up to date from the
+	// ValsetUpdatedEvent logs. It's nil until it's bootstrapped from the event history.
+	latestValset      *gravitytypes.Valset
+	latestValsetBlock uint64
+	gravityID         string
 }
 
 func NewGravityRelayer(
@@ -70,11 +110,24 @@ func NewGravityRelayer(
 		loopDuration:       loopDuration,
 		pendingTxWait:      pendingTxWait,
 		profitMultiplier:   profitMultiplier,
+
+		batchTimeoutWarningBlocks: defaultBatchTimeoutWarningBlocks,
+		trackedBatches:            map[batchKey]*trackedBatch{},
+		lastSentBatchNonces:       map[ethcmn.Address]uint64{},
 	}
 
+	relayer.batchGasModel = newBatchGasModel(relayer.logger)
+	relayer.valsetGasModel = newValsetGasModel(relayer.logger)
+
 	for _, option := range options {
 		option(relayer)
 	}
 
 	return relayer
 }
+
+// isTxDeferred returns true if the committer didn't send a tx so it's relayed later, e.g. because it would spend the
+// balance reserve or its gas price is above the ceiling.
+func isTxDeferred(err error) bool {
+	return errors.Is(err, committer.ErrBalanceReserve) || errors.Is(err, committer.ErrSpendLimit)
+}
@@ -0,0 +1,158 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
+)
+
+// maxValsetPathNonces caps how many valsets after the one on Ethereum are considered when looking for a path to the
+// latest one.
+const maxValsetPathNonces = 100
+
+// confirmedValset is a valset along with the confirmations (signatures over its checkpoint) gathered for it.
+type confirmedValset struct {
+	valset   types.Valset
+	confirms []types.MsgValsetConfirm
+}
+
+// newConfirmedValset returns the valset along with the confirmations whose signature is valid, the contract rejects
+// an update with any other one.
+func newConfirmedValset(gravityID string, valset types.Valset, confirms []types.MsgValsetConfirm) confirmedValset {
+	return confirmedValset{valset: valset, confirms: gravity.ValidValsetConfirms(gravityID, valset, confirms)}
+}
+
+// signedPower returns the power of the members of valset that have a confirmation in confirms. The signatures of the
+// confirmations are checked once when they're gathered with newConfirmedValset, not on every call.
+func signedPower(valset types.Valset, confirms []types.MsgValsetConfirm) *big.Int {
+	signers := make(map[ethcmn.Address]struct{}, len(confirms))
+	for _, c := range confirms {
+		signers[ethcmn.HexToAddress(c.EthAddress)] = struct{}{}
+	}
+
+	power := new(big.Int)
+	for _, m := range valset.Members {
+		if _, ok := signers[ethcmn.HexToAddress(m.EthereumAddress)]; ok {
+			power.Add(power, new(big.Int).SetUint64(m.Power))
+		}
+	}
+
+	return power
+}
+
+// canUpdateValset returns true if the members of from signed enough of the confirmations of to for the contract to
+// accept the update.
+func canUpdateValset(from types.Valset, to confirmedValset) bool {
+	return gravity.HasEnoughPower(signedPower(from, to.confirms))
+}
+
+// findValsetPath returns the shortest sequence of valset updates from current to the most recent of the candidates it
+// can reach, where each valset was signed by enough power of the previous one. It returns nil if none of the candidates
+// can be reached.
+func findValsetPath(current types.Valset, candidates []confirmedValset) []confirmedValset {
+	sorted := append([]confirmedValset{}, candidates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].valset.Nonce < sorted[j].valset.Nonce })
+
+	// Breadth-first search over the candidates, where the node -1 is the current valset. Updates can only go forward,
+	// so the candidates are only linked to the ones with a higher nonce.
+	prev := make([]int, len(sorted))
+	reached := make([]bool, len(sorted))
+	queue := []int{-1}
+
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+
+		fromValset, fromNonce := current, current.Nonce
+		if from >= 0 {
+			fromValset, fromNonce = sorted[from].valset, sorted[from].valset.Nonce
+		}
+
+		for to := range sorted {
+			if reached[to] || sorted[to].valset.Nonce <= fromNonce || !canUpdateValset(fromValset, sorted[to]) {
+				continue
+			}
+
+			reached[to] = true
+			prev[to] = from
+			queue = append(queue, to)
+		}
+	}
+
+	target := -1
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if reached[i] {
+			target = i
+			break
+		}
+	}
+
+	if target < 0 {
+		return nil
+	}
+
+	var path []confirmedValset
+	for i := target; i >= 0; i = prev[i] {
+		path = append([]confirmedValset{sorted[i]}, path...)
+	}
+
+	return path
+}
+
+// getConfirmedValsets returns the valsets with a nonce in (fromNonce, toNonce] along with their valid confirmations,
+// skipping the ones that have none. At most maxValsetPathNonces nonces are queried, the path to the newer ones will
+// be searched for once the older ones are relayed.
+func (s *gravityRelayer) getConfirmedValsets(
+	ctx context.Context,
+	fromNonce uint64,
+	toNonce uint64,
+) ([]confirmedValset, error) {
+	if toNonce-fromNonce > maxValsetPathNonces {
+		toNonce = fromNonce + maxValsetPathNonces
+	}
+
+	gravityID, err := s.getGravityID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var valsets []confirmedValset
+
+	for nonce := fromNonce + 1; nonce <= toNonce; nonce++ {
+		valsetRes, err := s.cosmosQueryClient.ValsetRequest(ctx, &types.QueryValsetRequestRequest{Nonce: nonce})
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get valset at nonce %d", nonce)
+			return nil, err
+		}
+
+		if valsetRes == nil || valsetRes.Valset == nil {
+			continue
+		}
+
+		confirmsRes, err := s.cosmosQueryClient.ValsetConfirmsByNonce(ctx, &types.QueryValsetConfirmsByNonceRequest{
+			Nonce: nonce,
+		})
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get valset confirms at nonce %d", nonce)
+			return nil, err
+		}
+
+		if confirmsRes == nil {
+			continue
+		}
+
+		valset := newConfirmedValset(gravityID, *valsetRes.Valset, confirmsRes.Confirms)
+		if len(valset.confirms) == 0 {
+			continue
+		}
+
+		valsets = append(valsets, valset)
+	}
+
+	return valsets, nil
+}
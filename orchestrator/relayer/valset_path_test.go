@@ -0,0 +1,226 @@
+package relayer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/umee-network/peggo/mocks"
+	gravityMocks "github.com/umee-network/peggo/mocks/gravity"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
+)
+
+const testGravityID = "defaultgravityid"
+
+var (
+	valsetKeys = map[string]*ecdsa.PrivateKey{}
+
+	valsetMemberA = testValsetMember(0x0a)
+	valsetMemberB = testValsetMember(0x0b)
+	valsetMemberC = testValsetMember(0x0c)
+	valsetMemberD = testValsetMember(0x0d)
+)
+
+// testValsetMember returns the Ethereum address of a deterministic key, kept to sign its confirmations.
+func testValsetMember(i byte) string {
+	key, err := crypto.ToECDSA(ethcmn.LeftPadBytes([]byte{i}, 32))
+	if err != nil {
+		panic(err)
+	}
+
+	addr := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	valsetKeys[addr] = key
+
+	return addr
+}
+
+// testValsetConfirm returns the confirmation of valset by signer, signed the same way orchestrators do.
+func testValsetConfirm(valset types.Valset, signer string) types.MsgValsetConfirm {
+	checkpoint := gravity.EncodeValsetConfirm(testGravityID, valset)
+
+	sig, err := crypto.Sign(accounts.TextHash(checkpoint.Bytes()), valsetKeys[signer])
+	if err != nil {
+		panic(err)
+	}
+
+	return types.MsgValsetConfirm{Nonce: valset.Nonce, EthAddress: signer, Signature: ethcmn.Bytes2Hex(sig)}
+}
+
+func testValset(nonce uint64, members map[string]float64) types.Valset {
+	valset := types.Valset{Nonce: nonce, RewardAmount: sdk.ZeroInt()}
+	for addr, share := range members {
+		valset.Members = append(valset.Members, types.BridgeValidator{
+			EthereumAddress: addr,
+			Power:           uint64(share * math.MaxUint32),
+		})
+	}
+
+	return valset
+}
+
+func testConfirmedValset(valset types.Valset, signers ...string) confirmedValset {
+	c := confirmedValset{valset: valset}
+	for _, signer := range signers {
+		c.confirms = append(c.confirms, testValsetConfirm(valset, signer))
+	}
+
+	return c
+}
+
+func valsetNonces(path []confirmedValset) []uint64 {
+	var nonces []uint64
+	for _, v := range path {
+		nonces = append(nonces, v.valset.Nonce)
+	}
+
+	return nonces
+}
+
+func TestNewConfirmedValset(t *testing.T) {
+	current := testValset(1, map[string]float64{valsetMemberA: 0.5, valsetMemberB: 0.5})
+	next := testValset(2, map[string]float64{valsetMemberC: 1})
+
+	// B's confirmations are signed by another key, and over another valset
+	forged := testValsetConfirm(next, valsetMemberC)
+	forged.EthAddress = valsetMemberB
+
+	confirms := []types.MsgValsetConfirm{
+		testValsetConfirm(next, valsetMemberA),
+		forged,
+		testValsetConfirm(current, valsetMemberB),
+	}
+
+	confirmed := newConfirmedValset(testGravityID, next, confirms)
+	assert.Equal(t, confirms[:1], confirmed.confirms)
+
+	// half of the power isn't enough once the invalid signatures are dropped
+	assert.True(t, canUpdateValset(current, confirmedValset{valset: next, confirms: confirms}))
+	assert.False(t, canUpdateValset(current, confirmed))
+}
+
+func TestFindValsetPath(t *testing.T) {
+	// A and B hold the power on Ethereum, C takes over from them in valset 2 and shares it with D in valset 3.
+	current := testValset(1, map[string]float64{valsetMemberA: 0.5, valsetMemberB: 0.5})
+	v2 := testConfirmedValset(testValset(2, map[string]float64{valsetMemberB: 0.3, valsetMemberC: 0.7}),
+		valsetMemberA, valsetMemberB)
+	v3 := testConfirmedValset(testValset(3, map[string]float64{valsetMemberC: 0.5, valsetMemberD: 0.5}),
+		valsetMemberC, valsetMemberD)
+	v3SignedByAB := testConfirmedValset(v3.valset, valsetMemberA, valsetMemberB)
+	v4 := testConfirmedValset(testValset(4, map[string]float64{valsetMemberD: 1}), valsetMemberD)
+
+	testCases := []struct {
+		name       string
+		candidates []confirmedValset
+		expected   []uint64
+	}{
+		{
+			name:       "direct update",
+			candidates: []confirmedValset{v2, v3SignedByAB},
+			expected:   []uint64{3},
+		},
+		{
+			name:       "update through an intermediate valset",
+			candidates: []confirmedValset{v3, v2},
+			expected:   []uint64{2, 3},
+		},
+		{
+			name:       "latest not reachable",
+			candidates: []confirmedValset{v2, v4},
+			expected:   []uint64{2},
+		},
+		{
+			name:       "nothing reachable",
+			candidates: []confirmedValset{v3, v4},
+			expected:   nil,
+		},
+		{
+			name:       "no candidates",
+			candidates: nil,
+			expected:   nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, valsetNonces(findValsetPath(current, tc.candidates)))
+		})
+	}
+}
+
+func TestRelayValsetsThroughIntermediateValset(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	current := testValset(1, map[string]float64{valsetMemberA: 0.5, valsetMemberB: 0.5})
+	v2 := testConfirmedValset(testValset(2, map[string]float64{valsetMemberB: 0.3, valsetMemberC: 0.7}),
+		valsetMemberA, valsetMemberB)
+	v3 := testConfirmedValset(testValset(3, map[string]float64{valsetMemberC: 0.5, valsetMemberD: 0.5}),
+		valsetMemberC, valsetMemberD)
+
+	gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+	fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+
+	// the path is looked up on both runs
+	mockQClient := mocks.NewMockQueryClient(mockCtrl)
+	mockQClient.EXPECT().
+		LastValsetRequests(gomock.Any(), &types.QueryLastValsetRequestsRequest{}).
+		Return(&types.QueryLastValsetRequestsResponse{Valsets: []types.Valset{v3.valset}}, nil).
+		Times(2)
+	mockQClient.EXPECT().
+		ValsetConfirmsByNonce(gomock.Any(), &types.QueryValsetConfirmsByNonceRequest{Nonce: 3}).
+		Return(&types.QueryValsetConfirmsByNonceResponse{Confirms: v3.confirms}, nil).
+		Times(4)
+	mockQClient.EXPECT().
+		ValsetConfirmsByNonce(gomock.Any(), &types.QueryValsetConfirmsByNonceRequest{Nonce: 2}).
+		Return(&types.QueryValsetConfirmsByNonceResponse{Confirms: v2.confirms}, nil).
+		Times(2)
+	mockQClient.EXPECT().
+		ValsetRequest(gomock.Any(), &types.QueryValsetRequestRequest{Nonce: 2}).
+		Return(&types.QueryValsetRequestResponse{Valset: &v2.valset}, nil).
+		Times(2)
+	mockQClient.EXPECT().
+		ValsetRequest(gomock.Any(), &types.QueryValsetRequestRequest{Nonce: 3}).
+		Return(&types.QueryValsetRequestResponse{Valset: &v3.valset}, nil).
+		Times(2)
+
+	mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+	mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+	mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+	mockGravityContract.EXPECT().GetValsetNonce(gomock.Any(), fromAddress).Return(big.NewInt(1), nil).Times(2)
+
+	// only the update to the intermediate valset is sent, and only once
+	mockGravityContract.EXPECT().
+		EncodeValsetUpdate(gomock.Any(), current, v2.valset, v2.confirms).
+		Return([]byte{2}, nil)
+	mockGravityContract.EXPECT().SimulateTx(gomock.Any(), []byte{2}).Return(nil)
+	mockGravityContract.EXPECT().
+		EstimateGas(gomock.Any(), gravityAddress, []byte{2}, gasprice.UrgencyHigh).
+		Return(uint64(1000), big.NewInt(100), nil)
+	mockGravityContract.EXPECT().IsPendingTxInput([]byte{2}, gomock.Any()).Return(false)
+	mockGravityContract.EXPECT().
+		SendTx(gomock.Any(), gravityAddress, []byte{2}, uint64(1000), big.NewInt(100)).
+		Return(ethcmn.HexToHash("0x01"), nil)
+
+	relayer := gravityRelayer{
+		gravityContract:   mockGravityContract,
+		cosmosQueryClient: mockQClient,
+		gravityID:         testGravityID,
+	}
+
+	assert.Nil(t, relayer.RelayValsets(context.Background(), current))
+	assert.Equal(t, uint64(2), relayer.lastSentValsetNonce)
+
+	// the update is still pending, so the Ethereum valset didn't change
+	assert.Nil(t, relayer.RelayValsets(context.Background(), current))
+	assert.Equal(t, uint64(2), relayer.lastSentValsetNonce)
+}
@@ -5,10 +5,14 @@ import (
 
 	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
 	"github.com/pkg/errors"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
 )
 
 // RelayValsets checks the last validator set on Ethereum, if it's lower than our latest validator
-// set then we should package and submit the update as an Ethereum transaction
+// set then we should package and submit the update as an Ethereum transaction. If the validator set on
+// Ethereum doesn't have enough power over the latest one's signatures, the update is done in several
+// steps through intermediate validator sets, one per call.
 func (s *gravityRelayer) RelayValsets(ctx context.Context, currentValset types.Valset) error {
 	// we should determine if we need to relay one
 	// to Ethereum for that we will find the latest confirmed valset and compare it to the ethereum chain
@@ -73,22 +77,72 @@ func (s *gravityRelayer) RelayValsets(ctx context.Context, currentValset types.V
 				Uint64("latest_ethereum_valset_nonce", latestEthereumValsetNonce.Uint64()).
 				Msg("detected latest cosmos valset nonce, but latest valset on Ethereum is different. Sending update to Ethereum")
 
+			gravityID, err := s.getGravityID(ctx)
+			if err != nil {
+				return err
+			}
+
+			latest := newConfirmedValset(gravityID, *latestCosmosConfirmed, latestCosmosSigs)
+
+			next, err := s.nextValsetUpdate(ctx, currentValset, latest)
+			if err != nil || next == nil {
+				return err
+			}
+
+			// learn from the valset updates mined since the last one before predicting the gas of this one
+			s.valsetGasModel.calibrate(ctx, s.ethProvider)
+
+			call := gasCall{
+				validators: len(currentValset.Members) + len(next.valset.Members),
+				signatures: len(next.confirms),
+			}
+
 			txData, err := s.gravityContract.EncodeValsetUpdate(
 				ctx,
 				currentValset,
-				*latestCosmosConfirmed,
-				latestCosmosSigs,
+				next.valset,
+				next.confirms,
 			)
 			if err != nil {
 				return err
 			}
 
-			estimatedGasCost, gasPrice, err := s.gravityContract.EstimateGas(ctx, s.gravityContract.Address(), txData)
+			// Make sure the update would go through before estimating its gas, or spending any on it.
+			if err := s.gravityContract.SimulateTx(ctx, txData); err != nil {
+				var simErr *gravity.SimulationError
+				if !errors.As(err, &simErr) {
+					return err
+				}
+
+				s.logger.Info().
+					Uint64("valset_nonce", next.valset.Nonce).
+					Stringer("outcome", simErr.Outcome).
+					Str("reason", simErr.Reason).
+					Uint64("predicted_gas", s.valsetGasModel.predict(call)).
+					Msg("valset update simulation failed; skipping it")
+
+				return nil
+			}
+
+			// every other tx against the contract waits for the valset to be updated
+			estimatedGasCost, gasPrice, err := s.gravityContract.EstimateGas(
+				ctx,
+				s.gravityContract.Address(),
+				txData,
+				gasprice.UrgencyHigh,
+			)
 			if err != nil {
 				s.logger.Err(err).Msg("failed to estimate gas cost")
 				return err
 			}
 
+			if s.valsetGasModel != nil {
+				s.logger.Debug().
+					Uint64("estimated_gas", estimatedGasCost).
+					Uint64("predicted_gas", s.valsetGasModel.predict(call)).
+					Msg("estimated valset update gas")
+			}
+
 			// TODO: Estimate profitability using "valset reward" param.
 			//
 			// Ref: https://github.com/umee-network/peggo/issues/56
@@ -103,6 +157,10 @@ func (s *gravityRelayer) RelayValsets(ctx context.Context, currentValset types.V
 
 			// Send Valset Update to Ethereum
 			txHash, err := s.gravityContract.SendTx(ctx, s.gravityContract.Address(), txData, estimatedGasCost, gasPrice)
+			if isTxDeferred(err) {
+				s.logger.Warn().Err(err).Msg("deferring valset update; the tx is beyond the spend limits")
+				return nil
+			}
 			if err != nil {
 				s.logger.Err(err).
 					Str("tx_hash", txHash.Hex()).
@@ -110,13 +168,62 @@ func (s *gravityRelayer) RelayValsets(ctx context.Context, currentValset types.V
 				return err
 			}
 
-			s.logger.Info().Str("tx_hash", txHash.Hex()).Msg("sent Tx (Gravity updateValset)")
+			s.logger.Info().
+				Str("tx_hash", txHash.Hex()).
+				Uint64("valset_nonce", next.valset.Nonce).
+				Msg("sent Tx (Gravity updateValset)")
+
+			s.valsetGasModel.track(txHash, call)
 
 			// update our local tracker of the latest valset
-			s.lastSentValsetNonce = latestCosmosConfirmed.Nonce
+			s.lastSentValsetNonce = next.valset.Nonce
 		}
 
 	}
 
 	return nil
 }
+
+// nextValsetUpdate returns the valset to update the contract to from currentValset: the latest one if its signatures
+// carry enough power of the current valset, or else the first step of the shortest path of updates towards it. It
+// returns nil if there's nothing to relay right now.
+func (s *gravityRelayer) nextValsetUpdate(
+	ctx context.Context,
+	currentValset types.Valset,
+	latest confirmedValset,
+) (*confirmedValset, error) {
+	if canUpdateValset(currentValset, latest) {
+		return &latest, nil
+	}
+
+	candidates, err := s.getConfirmedValsets(ctx, currentValset.Nonce, latest.valset.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	path := findValsetPath(currentValset, candidates)
+	if len(path) == 0 {
+		s.logger.Warn().
+			Uint64("current_eth_valset_nonce", currentValset.Nonce).
+			Uint64("latest_cosmos_confirmed_nonce", latest.valset.Nonce).
+			Msg("no valset update has enough power from the current valset signatures; waiting for more confirmations")
+		return nil, nil
+	}
+
+	nonces := make([]uint64, len(path))
+	for i, v := range path {
+		nonces[i] = v.valset.Nonce
+	}
+
+	s.logger.Info().
+		Uint64("current_eth_valset_nonce", currentValset.Nonce).
+		Uints64("path", nonces).
+		Msg("latest valset lacks signing power from the current one; relaying through intermediate valsets")
+
+	if s.lastSentValsetNonce >= path[0].valset.Nonce {
+		s.logger.Debug().Uint64("valset_nonce", path[0].valset.Nonce).Msg("already relayed this valset; skipping")
+		return nil, nil
+	}
+
+	return &path[0], nil
+}
@@ -3,15 +3,47 @@ package relayer
 import (
 	"context"
 	"errors"
+	"math"
 	"math/big"
 	"testing"
 
 	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/umee-network/peggo/mocks"
 	gravityMocks "github.com/umee-network/peggo/mocks/gravity"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gasprice"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
+)
+
+var (
+	// testEthValset is the valset found on Ethereum in the tests. Its only member signed all the confirmations.
+	testEthValset = types.Valset{
+		Members: []types.BridgeValidator{
+			{
+				Power:           math.MaxUint32,
+				EthereumAddress: valsetMemberA,
+			},
+		},
+	}
+
+	// testCosmosValset is the latest valset on Cosmos in the tests.
+	testCosmosValset = types.Valset{
+		Nonce: 3,
+		Members: []types.BridgeValidator{
+			{
+				Power:           1000,
+				EthereumAddress: valsetMemberA,
+			},
+			{
+				Power:           1000,
+				EthereumAddress: "0x1000000000000000000000000000000000000000",
+			},
+		},
+		RewardAmount: sdk.ZeroInt(),
+	}
 )
 
 func TestRelayValsets(t *testing.T) {
@@ -24,22 +56,7 @@ func TestRelayValsets(t *testing.T) {
 		mockQClient.EXPECT().
 			LastValsetRequests(gomock.Any(), &types.QueryLastValsetRequestsRequest{}).
 			Return(&types.QueryLastValsetRequestsResponse{
-				Valsets: []types.Valset{
-					{
-						Nonce: 3,
-						Members: []types.BridgeValidator{
-							{
-								Power:           1000,
-								EthereumAddress: "0x0000000000000000000000000000000000000000",
-							},
-							{
-								Power:           1000,
-								EthereumAddress: "0x1000000000000000000000000000000000000000",
-							},
-						},
-						Height: 0,
-					},
-				},
+				Valsets: []types.Valset{testCosmosValset},
 			}, nil)
 
 		mockQClient.EXPECT().ValsetConfirmsByNonce(
@@ -47,14 +64,7 @@ func TestRelayValsets(t *testing.T) {
 			&types.QueryValsetConfirmsByNonceRequest{
 				Nonce: 3,
 			}).Return(&types.QueryValsetConfirmsByNonceResponse{
-			Confirms: []types.MsgValsetConfirm{
-				{
-					Nonce:        0,
-					Orchestrator: "aaa",
-					EthAddress:   "0x0000000000000000000000000000000000000000",
-					Signature:    "0x000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
-				},
-			},
+			Confirms: []types.MsgValsetConfirm{testValsetConfirm(testCosmosValset, valsetMemberA)},
 		}, nil)
 
 		gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
@@ -67,8 +77,9 @@ func TestRelayValsets(t *testing.T) {
 			EncodeValsetUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return([]byte{1, 2, 3}, nil)
 		mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+		mockGravityContract.EXPECT().SimulateTx(gomock.Any(), []byte{1, 2, 3}).Return(nil)
 		mockGravityContract.EXPECT().
-			EstimateGas(gomock.Any(), gravityAddress, []byte{1, 2, 3}).
+			EstimateGas(gomock.Any(), gravityAddress, []byte{1, 2, 3}, gasprice.UrgencyHigh).
 			Return(uint64(1000), big.NewInt(100), nil)
 
 		mockGravityContract.EXPECT().IsPendingTxInput([]byte{1, 2, 3}, gomock.Any()).Return(false)
@@ -84,9 +95,54 @@ func TestRelayValsets(t *testing.T) {
 		relayer := gravityRelayer{
 			gravityContract:   mockGravityContract,
 			cosmosQueryClient: mockQClient,
+			gravityID:         testGravityID,
 		}
 
-		assert.Nil(t, relayer.RelayValsets(context.Background(), types.Valset{}))
+		assert.Nil(t, relayer.RelayValsets(context.Background(), testEthValset))
+	})
+
+	t.Run("simulation reverts, tx not sent", func(t *testing.T) {
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockQClient := mocks.NewMockQueryClient(mockCtrl)
+		mockQClient.EXPECT().
+			LastValsetRequests(gomock.Any(), &types.QueryLastValsetRequestsRequest{}).
+			Return(&types.QueryLastValsetRequestsResponse{
+				Valsets: []types.Valset{testCosmosValset},
+			}, nil)
+
+		mockQClient.EXPECT().ValsetConfirmsByNonce(
+			gomock.Any(),
+			&types.QueryValsetConfirmsByNonceRequest{
+				Nonce: 3,
+			}).Return(&types.QueryValsetConfirmsByNonceResponse{
+			Confirms: []types.MsgValsetConfirm{testValsetConfirm(testCosmosValset, valsetMemberA)},
+		}, nil)
+
+		gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+		fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+
+		mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+		mockGravityContract.EXPECT().GetValsetNonce(gomock.Any(), fromAddress).Return(big.NewInt(2), nil)
+		mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+		mockGravityContract.EXPECT().
+			EncodeValsetUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]byte{1, 2, 3}, nil)
+		mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+		mockGravityContract.EXPECT().SimulateTx(gomock.Any(), []byte{1, 2, 3}).Return(&gravity.SimulationError{
+			Outcome: gravity.SimulationAlreadyRelayed,
+			Reason:  "InvalidValsetNonce(3, 3)",
+		})
+
+		relayer := gravityRelayer{
+			gravityContract:   mockGravityContract,
+			cosmosQueryClient: mockQClient,
+			gravityID:         testGravityID,
+		}
+
+		assert.Nil(t, relayer.RelayValsets(context.Background(), testEthValset))
 	})
 
 	t.Run("error. no valsets found", func(t *testing.T) {
@@ -104,9 +160,10 @@ func TestRelayValsets(t *testing.T) {
 		relayer := gravityRelayer{
 			gravityContract:   mockGravityContract,
 			cosmosQueryClient: mockQClient,
+			gravityID:         testGravityID,
 		}
 
-		err := relayer.RelayValsets(context.Background(), types.Valset{})
+		err := relayer.RelayValsets(context.Background(), testEthValset)
 		assert.EqualError(t, err, "no valsets found")
 	})
 
@@ -125,9 +182,10 @@ func TestRelayValsets(t *testing.T) {
 		relayer := gravityRelayer{
 			gravityContract:   mockGravityContract,
 			cosmosQueryClient: mockQClient,
+			gravityID:         testGravityID,
 		}
 
-		err := relayer.RelayValsets(context.Background(), types.Valset{})
+		err := relayer.RelayValsets(context.Background(), testEthValset)
 		assert.EqualError(t, err, "failed to fetch latest valsets from cosmos: some error")
 	})
 
@@ -140,22 +198,7 @@ func TestRelayValsets(t *testing.T) {
 		mockQClient.EXPECT().
 			LastValsetRequests(gomock.Any(), &types.QueryLastValsetRequestsRequest{}).
 			Return(&types.QueryLastValsetRequestsResponse{
-				Valsets: []types.Valset{
-					{
-						Nonce: 3,
-						Members: []types.BridgeValidator{
-							{
-								Power:           1000,
-								EthereumAddress: "0x0000000000000000000000000000000000000000",
-							},
-							{
-								Power:           1000,
-								EthereumAddress: "0x1000000000000000000000000000000000000000",
-							},
-						},
-						Height: 0,
-					},
-				},
+				Valsets: []types.Valset{testCosmosValset},
 			}, nil)
 
 		mockQClient.EXPECT().ValsetConfirmsByNonce(
@@ -163,14 +206,7 @@ func TestRelayValsets(t *testing.T) {
 			&types.QueryValsetConfirmsByNonceRequest{
 				Nonce: 3,
 			}).Return(&types.QueryValsetConfirmsByNonceResponse{
-			Confirms: []types.MsgValsetConfirm{
-				{
-					Nonce:        0,
-					Orchestrator: "aaa",
-					EthAddress:   "0x0000000000000000000000000000000000000000",
-					Signature:    "0x000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
-				},
-			},
+			Confirms: []types.MsgValsetConfirm{testValsetConfirm(testCosmosValset, valsetMemberA)},
 		}, errors.New("some error"))
 
 		mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
@@ -178,9 +214,10 @@ func TestRelayValsets(t *testing.T) {
 		relayer := gravityRelayer{
 			gravityContract:   mockGravityContract,
 			cosmosQueryClient: mockQClient,
+			gravityID:         testGravityID,
 		}
 
-		err := relayer.RelayValsets(context.Background(), types.Valset{})
+		err := relayer.RelayValsets(context.Background(), testEthValset)
 		assert.EqualError(t, err, "failed to get valset confirms at nonce 3: some error")
 	})
 
@@ -193,22 +230,7 @@ func TestRelayValsets(t *testing.T) {
 		mockQClient.EXPECT().
 			LastValsetRequests(gomock.Any(), &types.QueryLastValsetRequestsRequest{}).
 			Return(&types.QueryLastValsetRequestsResponse{
-				Valsets: []types.Valset{
-					{
-						Nonce: 3,
-						Members: []types.BridgeValidator{
-							{
-								Power:           1000,
-								EthereumAddress: "0x0000000000000000000000000000000000000000",
-							},
-							{
-								Power:           1000,
-								EthereumAddress: "0x1000000000000000000000000000000000000000",
-							},
-						},
-						Height: 0,
-					},
-				},
+				Valsets: []types.Valset{testCosmosValset},
 			}, nil)
 
 		mockQClient.EXPECT().ValsetConfirmsByNonce(
@@ -222,9 +244,10 @@ func TestRelayValsets(t *testing.T) {
 		relayer := gravityRelayer{
 			gravityContract:   mockGravityContract,
 			cosmosQueryClient: mockQClient,
+			gravityID:         testGravityID,
 		}
 
-		err := relayer.RelayValsets(context.Background(), types.Valset{})
+		err := relayer.RelayValsets(context.Background(), testEthValset)
 		assert.EqualError(t, err, "no valset confirms found")
 	})
 
@@ -237,22 +260,7 @@ func TestRelayValsets(t *testing.T) {
 		mockQClient.EXPECT().
 			LastValsetRequests(gomock.Any(), &types.QueryLastValsetRequestsRequest{}).
 			Return(&types.QueryLastValsetRequestsResponse{
-				Valsets: []types.Valset{
-					{
-						Nonce: 3,
-						Members: []types.BridgeValidator{
-							{
-								Power:           1000,
-								EthereumAddress: "0x0000000000000000000000000000000000000000",
-							},
-							{
-								Power:           1000,
-								EthereumAddress: "0x1000000000000000000000000000000000000000",
-							},
-						},
-						Height: 0,
-					},
-				},
+				Valsets: []types.Valset{testCosmosValset},
 			}, nil)
 
 		mockQClient.EXPECT().ValsetConfirmsByNonce(
@@ -260,14 +268,7 @@ func TestRelayValsets(t *testing.T) {
 			&types.QueryValsetConfirmsByNonceRequest{
 				Nonce: 3,
 			}).Return(&types.QueryValsetConfirmsByNonceResponse{
-			Confirms: []types.MsgValsetConfirm{
-				{
-					Nonce:        0,
-					Orchestrator: "aaa",
-					EthAddress:   "0x0000000000000000000000000000000000000000",
-					Signature:    "0x000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
-				},
-			},
+			Confirms: []types.MsgValsetConfirm{testValsetConfirm(testCosmosValset, valsetMemberA)},
 		}, nil)
 
 		gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
@@ -280,8 +281,9 @@ func TestRelayValsets(t *testing.T) {
 			EncodeValsetUpdate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return([]byte{1, 2, 3}, nil)
 		mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+		mockGravityContract.EXPECT().SimulateTx(gomock.Any(), []byte{1, 2, 3}).Return(nil)
 		mockGravityContract.EXPECT().
-			EstimateGas(gomock.Any(), gravityAddress, []byte{1, 2, 3}).
+			EstimateGas(gomock.Any(), gravityAddress, []byte{1, 2, 3}, gasprice.UrgencyHigh).
 			Return(uint64(1000), big.NewInt(100), nil)
 
 		mockGravityContract.EXPECT().IsPendingTxInput([]byte{1, 2, 3}, gomock.Any()).Return(false)
@@ -297,9 +299,10 @@ func TestRelayValsets(t *testing.T) {
 		relayer := gravityRelayer{
 			gravityContract:   mockGravityContract,
 			cosmosQueryClient: mockQClient,
+			gravityID:         testGravityID,
 		}
 
-		err := relayer.RelayValsets(context.Background(), types.Valset{})
+		err := relayer.RelayValsets(context.Background(), testEthValset)
 		assert.EqualError(t, err, "some error while sending tx")
 	})
 
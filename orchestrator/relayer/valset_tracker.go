@@ -0,0 +1,223 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
+	wrappers "github.com/umee-network/peggo/solwrappers/Gravity.sol"
+)
+
+// ErrValsetCheckpointMismatch is returned when the valset found on Ethereum doesn't hash to the checkpoint stored in
+// the Gravity contract, so any tx relayed against it would revert.
+var ErrValsetCheckpointMismatch = errors.New("valset doesn't match the contract checkpoint")
+
+// updateLatestValset applies the ValsetUpdatedEvent logs emitted since the last tracked block to the tracked valset,
+// and checks the result against the valset checkpoint stored in the contract. If they don't match (e.g. after a
+// reorg), the tracked valset is dropped so it's bootstrapped again from the event history.
+func (s *gravityRelayer) updateLatestValset(ctx context.Context, currentBlock uint64) error {
+	if currentBlock <= s.latestValsetBlock {
+		return nil
+	}
+
+	gravityFilterer, err := wrappers.NewGravityFilterer(s.gravityContract.Address(), s.ethProvider)
+	if err != nil {
+		err = errors.Wrap(err, "failed to init Gravity events filterer")
+		return err
+	}
+
+	iter, err := gravityFilterer.FilterValsetUpdatedEvent(&bind.FilterOpts{
+		Start: s.latestValsetBlock + 1,
+		End:   &currentBlock,
+	}, nil)
+	if err != nil {
+		err = errors.Wrap(err, "failed to filter new ValsetUpdated events from Ethereum")
+		return err
+	}
+
+	var latestEvent *wrappers.GravityValsetUpdatedEvent
+	for iter.Next() {
+		if latestEvent == nil || iter.Event.NewValsetNonce.Cmp(latestEvent.NewValsetNonce) > 0 {
+			latestEvent = iter.Event
+		}
+	}
+
+	iter.Close()
+
+	valset := s.latestValset
+	if latestEvent != nil && latestEvent.NewValsetNonce.Uint64() > valset.Nonce {
+		valset = valsetFromEvent(latestEvent)
+	}
+
+	ok, err := s.matchesValsetCheckpoint(ctx, valset, currentBlock)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		s.logger.Warn().
+			Uint64("valset_nonce", valset.Nonce).
+			Uint64("eth_block", currentBlock).
+			Msg("tracked valset doesn't match the contract's checkpoint; searching the event history again")
+
+		s.latestValset = nil
+		return nil
+	}
+
+	if valset != s.latestValset {
+		s.logger.Info().Uint64("valset_nonce", valset.Nonce).Msg("valset updated on Ethereum")
+
+		cosmosValset, err := s.cosmosQueryClient.ValsetRequest(ctx, &types.QueryValsetRequestRequest{
+			Nonce: valset.Nonce,
+		})
+		if err != nil {
+			err = errors.Wrap(err, "failed to get cosmos Valset")
+			return err
+		} else if cosmosValset == nil {
+			return errors.New("failed to get cosmos Valset, empty response")
+		}
+
+		s.checkIfValsetsDiffer(cosmosValset.Valset, copyValset(valset))
+	}
+
+	s.latestValset = valset
+	s.latestValsetBlock = currentBlock
+
+	return nil
+}
+
+// matchesValsetCheckpoint returns true if the checkpoint of the valset is the one stored in the contract at the given
+// block.
+func (s *gravityRelayer) matchesValsetCheckpoint(
+	ctx context.Context,
+	valset *types.Valset,
+	blockNumber uint64,
+) (bool, error) {
+	gravityID, err := s.getGravityID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	checkpoint, err := s.gravityContract.GetValsetCheckpoint(
+		ctx,
+		s.gravityContract.FromAddress(),
+		new(big.Int).SetUint64(blockNumber),
+	)
+	if err != nil {
+		err = errors.Wrap(err, "failed to get valset checkpoint")
+		return false, err
+	}
+
+	return gravity.EncodeValsetConfirm(gravityID, *valset) == checkpoint, nil
+}
+
+// getGravityID returns the gravityID, only querying the contract the first time as it never changes.
+func (s *gravityRelayer) getGravityID(ctx context.Context) (string, error) {
+	if s.gravityID != "" {
+		return s.gravityID, nil
+	}
+
+	gravityID, err := s.gravityContract.GetGravityID(ctx, s.gravityContract.FromAddress())
+	if err != nil {
+		err = errors.Wrap(err, "failed to get gravityID")
+		return "", err
+	}
+
+	s.gravityID = gravityID
+	return gravityID, nil
+}
+
+// verifyValsetCheckpoint checks that the valset hashes to the checkpoint stored in the contract at the given block. If
+// it doesn't, it returns an error wrapping ErrValsetCheckpointMismatch that describes how the valset differs from the
+// Cosmos one with the same nonce.
+func (s *gravityRelayer) verifyValsetCheckpoint(ctx context.Context, valset *types.Valset, blockNumber uint64) error {
+	ok, err := s.matchesValsetCheckpoint(ctx, valset, blockNumber)
+	if err != nil || ok {
+		return err
+	}
+
+	diff := "no Cosmos valset to compare with"
+
+	cosmosValset, err := s.cosmosQueryClient.ValsetRequest(ctx, &types.QueryValsetRequestRequest{
+		Nonce: valset.Nonce,
+	})
+	if err == nil && cosmosValset != nil && cosmosValset.Valset != nil {
+		diff = "same as the Cosmos valset"
+		if d := diffValsets(valset, cosmosValset.Valset); len(d) > 0 {
+			diff = strings.Join(d, "; ")
+		}
+	}
+
+	return errors.Wrapf(ErrValsetCheckpointMismatch, "valset %d (%s)", valset.Nonce, diff)
+}
+
+// diffValsets describes the differences between the valset found on Ethereum and the Cosmos one.
+func diffValsets(ethValset, cosmosValset *types.Valset) []string {
+	var diff []string
+
+	ethPowers := make(map[ethcmn.Address]uint64, len(ethValset.Members))
+	for _, m := range ethValset.Members {
+		ethPowers[ethcmn.HexToAddress(m.EthereumAddress)] = m.Power
+	}
+
+	cosmosPowers := make(map[ethcmn.Address]uint64, len(cosmosValset.Members))
+	for _, m := range cosmosValset.Members {
+		addr := ethcmn.HexToAddress(m.EthereumAddress)
+		cosmosPowers[addr] = m.Power
+
+		ethPower, ok := ethPowers[addr]
+		switch {
+		case !ok:
+			diff = append(diff, fmt.Sprintf("member %s missing on Ethereum", addr.Hex()))
+		case ethPower != m.Power:
+			diff = append(diff, fmt.Sprintf(
+				"member %s has power %d on Ethereum, %d on Cosmos",
+				addr.Hex(),
+				ethPower,
+				m.Power,
+			))
+		}
+	}
+
+	for _, m := range ethValset.Members {
+		addr := ethcmn.HexToAddress(m.EthereumAddress)
+		if _, ok := cosmosPowers[addr]; !ok {
+			diff = append(diff, fmt.Sprintf("member %s missing on Cosmos", addr.Hex()))
+		}
+	}
+
+	// the members are hashed in order, so the same members in another order give another checkpoint
+	if len(diff) == 0 && len(ethValset.Members) == len(cosmosValset.Members) {
+		for i := range ethValset.Members {
+			if ethcmn.HexToAddress(ethValset.Members[i].EthereumAddress) !=
+				ethcmn.HexToAddress(cosmosValset.Members[i].EthereumAddress) {
+				diff = append(diff, fmt.Sprintf("members in a different order from position %d", i))
+				break
+			}
+		}
+	}
+
+	if ethValset.RewardAmount.String() != cosmosValset.RewardAmount.String() {
+		diff = append(diff, fmt.Sprintf(
+			"reward amount %s on Ethereum, %s on Cosmos",
+			ethValset.RewardAmount,
+			cosmosValset.RewardAmount,
+		))
+	}
+
+	if ethcmn.HexToAddress(ethValset.RewardToken) != ethcmn.HexToAddress(cosmosValset.RewardToken) {
+		diff = append(diff, fmt.Sprintf(
+			"reward token %s on Ethereum, %s on Cosmos",
+			ethValset.RewardToken,
+			cosmosValset.RewardToken,
+		))
+	}
+
+	return diff
+}
@@ -0,0 +1,304 @@
+package relayer
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umee-network/peggo/mocks"
+	gravityMocks "github.com/umee-network/peggo/mocks/gravity"
+	"github.com/umee-network/peggo/orchestrator/ethereum/gravity"
+	wrappers "github.com/umee-network/peggo/solwrappers/Gravity.sol"
+)
+
+// valsetUpdatedLog returns the ValsetUpdatedEvent log the contract emits when it's updated to the given valset.
+func valsetUpdatedLog(t *testing.T, gravityAddress ethcmn.Address, valset types.Valset) ethtypes.Log {
+	gravityABI, err := abi.JSON(strings.NewReader(wrappers.GravityABI))
+	require.NoError(t, err)
+
+	event := gravityABI.Events["ValsetUpdatedEvent"]
+
+	var (
+		validators []ethcmn.Address
+		powers     []*big.Int
+	)
+	for _, m := range valset.Members {
+		validators = append(validators, ethcmn.HexToAddress(m.EthereumAddress))
+		powers = append(powers, new(big.Int).SetUint64(m.Power))
+	}
+
+	data, err := event.Inputs.NonIndexed().Pack(
+		big.NewInt(1),
+		valset.RewardAmount.BigInt(),
+		ethcmn.HexToAddress(valset.RewardToken),
+		validators,
+		powers,
+	)
+	require.NoError(t, err)
+
+	return ethtypes.Log{
+		Address: gravityAddress,
+		Topics:  []ethcmn.Hash{event.ID, ethcmn.BigToHash(new(big.Int).SetUint64(valset.Nonce))},
+		Data:    data,
+	}
+}
+
+func TestFindLatestValsetIncremental(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+	mockQClient := mocks.NewMockQueryClient(mockCtrl)
+	ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+
+	gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+	fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	gravityID := "defaultgravityid"
+	valsetUpdatedTopic := ethcmn.HexToHash("0x76d08978c024a4bf8cbb30c67fd78fcaa1827cbc533e4e175f36d07e64ccf96a")
+
+	valset2 := types.Valset{
+		Nonce: 2,
+		Members: []types.BridgeValidator{
+			{Power: 4294967295, EthereumAddress: "0x05a64fe82628217900ced80BF3747B5Ef88bFa21"},
+		},
+		RewardAmount: sdk.NewInt(0),
+		RewardToken:  "0x0000000000000000000000000000000000000000",
+	}
+	valset3 := types.Valset{
+		Nonce: 3,
+		Members: []types.BridgeValidator{
+			{Power: 3000000000, EthereumAddress: "0x05a64fe82628217900ced80BF3747B5Ef88bFa21"},
+			{Power: 1294967295, EthereumAddress: "0x1F71F2A59D19030BF79961E3E57C82922fEEcCA0"},
+		},
+		RewardAmount: sdk.NewInt(0),
+		RewardToken:  "0x0000000000000000000000000000000000000000",
+	}
+
+	filterQuery := func(from, to int64) gomock.Matcher {
+		return MatchFilterQuery(ethereum.FilterQuery{
+			FromBlock: big.NewInt(from),
+			ToBlock:   big.NewInt(to),
+			Addresses: []ethcmn.Address{gravityAddress},
+			Topics:    [][]ethcmn.Hash{{valsetUpdatedTopic}, {}},
+		})
+	}
+
+	mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+	mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+	mockGravityContract.EXPECT().GetGravityID(gomock.Any(), fromAddress).Return(gravityID, nil)
+
+	relayer := gravityRelayer{
+		logger:            logger,
+		cosmosQueryClient: mockQClient,
+		gravityContract:   mockGravityContract,
+		ethProvider:       ethProvider,
+	}
+
+	// bootstrap from the event history
+	ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{Number: big.NewInt(112)}, nil)
+	mockGravityContract.EXPECT().GetValsetNonce(gomock.Any(), fromAddress).Return(big.NewInt(2), nil)
+	mockQClient.EXPECT().
+		ValsetRequest(gomock.Any(), &types.QueryValsetRequestRequest{Nonce: 2}).
+		Return(&types.QueryValsetRequestResponse{Valset: &valset2}, nil)
+	ethProvider.EXPECT().
+		FilterLogs(gomock.Any(), filterQuery(0, 112)).
+		Return([]ethtypes.Log{valsetUpdatedLog(t, gravityAddress, valset2)}, nil)
+
+	mockGravityContract.EXPECT().
+		GetValsetCheckpoint(gomock.Any(), fromAddress, big.NewInt(112)).
+		Return(gravity.EncodeValsetConfirm(gravityID, valset2), nil)
+
+	valset, err := relayer.FindLatestValset(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), valset.Nonce)
+
+	// no new events, only the new blocks are queried
+	ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{Number: big.NewInt(120)}, nil)
+	ethProvider.EXPECT().FilterLogs(gomock.Any(), filterQuery(113, 120)).Return(nil, nil)
+	mockGravityContract.EXPECT().
+		GetValsetCheckpoint(gomock.Any(), fromAddress, big.NewInt(120)).
+		Return(gravity.EncodeValsetConfirm(gravityID, valset2), nil)
+
+	valset, err = relayer.FindLatestValset(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), valset.Nonce)
+
+	// the valset is updated on Ethereum
+	ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{Number: big.NewInt(130)}, nil)
+	ethProvider.EXPECT().
+		FilterLogs(gomock.Any(), filterQuery(121, 130)).
+		Return([]ethtypes.Log{valsetUpdatedLog(t, gravityAddress, valset3)}, nil)
+	mockGravityContract.EXPECT().
+		GetValsetCheckpoint(gomock.Any(), fromAddress, big.NewInt(130)).
+		Return(gravity.EncodeValsetConfirm(gravityID, valset3), nil)
+	mockQClient.EXPECT().
+		ValsetRequest(gomock.Any(), &types.QueryValsetRequestRequest{Nonce: 3}).
+		Return(&types.QueryValsetRequestResponse{Valset: &valset3}, nil)
+
+	valset, err = relayer.FindLatestValset(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), valset.Nonce)
+	assert.Len(t, valset.Members, 2)
+	assert.Equal(t, uint64(130), relayer.latestValsetBlock)
+
+	// the checkpoint doesn't match anymore, so the event history is searched again
+	ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{Number: big.NewInt(140)}, nil)
+	ethProvider.EXPECT().FilterLogs(gomock.Any(), filterQuery(131, 140)).Return(nil, nil)
+	mockGravityContract.EXPECT().
+		GetValsetCheckpoint(gomock.Any(), fromAddress, big.NewInt(140)).
+		Return(gravity.EncodeValsetConfirm(gravityID, valset2), nil).
+		Times(2)
+	mockGravityContract.EXPECT().GetValsetNonce(gomock.Any(), fromAddress).Return(big.NewInt(2), nil)
+	mockQClient.EXPECT().
+		ValsetRequest(gomock.Any(), &types.QueryValsetRequestRequest{Nonce: 2}).
+		Return(&types.QueryValsetRequestResponse{Valset: &valset2}, nil)
+	ethProvider.EXPECT().
+		FilterLogs(gomock.Any(), filterQuery(0, 140)).
+		Return([]ethtypes.Log{valsetUpdatedLog(t, gravityAddress, valset2)}, nil)
+
+	valset, err = relayer.FindLatestValset(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), valset.Nonce)
+	assert.Equal(t, uint64(140), relayer.latestValsetBlock)
+}
+
+func TestFindLatestValsetCheckpointMismatch(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+	mockQClient := mocks.NewMockQueryClient(mockCtrl)
+	ethProvider := mocks.NewMockEVMProviderWithRet(mockCtrl)
+	mockGravityContract := gravityMocks.NewMockContract(mockCtrl)
+
+	gravityAddress := ethcmn.HexToAddress("0x3bdf8428734244c9e5d82c95d125081939d6d42d")
+	fromAddress := ethcmn.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	gravityID := "defaultgravityid"
+
+	ethValset := types.Valset{
+		Nonce: 2,
+		Members: []types.BridgeValidator{
+			{Power: 4294967295, EthereumAddress: "0x05a64fe82628217900ced80BF3747B5Ef88bFa21"},
+		},
+		RewardAmount: sdk.NewInt(0),
+		RewardToken:  "0x0000000000000000000000000000000000000000",
+	}
+	cosmosValset := types.Valset{
+		Nonce: 2,
+		Members: []types.BridgeValidator{
+			{Power: 4294967295, EthereumAddress: "0x05a64fe82628217900ced80BF3747B5Ef88bFa21"},
+		},
+		RewardAmount: sdk.NewInt(10),
+		RewardToken:  "0x0000000000000000000000000000000000000000",
+	}
+
+	ethProvider.EXPECT().HeaderByNumber(gomock.Any(), nil).Return(&ethtypes.Header{Number: big.NewInt(112)}, nil)
+	mockGravityContract.EXPECT().FromAddress().Return(fromAddress).AnyTimes()
+	mockGravityContract.EXPECT().Address().Return(gravityAddress).AnyTimes()
+	mockGravityContract.EXPECT().GetGravityID(gomock.Any(), fromAddress).Return(gravityID, nil)
+	mockGravityContract.EXPECT().GetValsetNonce(gomock.Any(), fromAddress).Return(big.NewInt(2), nil)
+	mockQClient.EXPECT().
+		ValsetRequest(gomock.Any(), &types.QueryValsetRequestRequest{Nonce: 2}).
+		Return(&types.QueryValsetRequestResponse{Valset: &cosmosValset}, nil).
+		Times(2)
+	ethProvider.EXPECT().
+		FilterLogs(gomock.Any(), gomock.Any()).
+		Return([]ethtypes.Log{valsetUpdatedLog(t, gravityAddress, ethValset)}, nil)
+
+	// the contract was updated to the Cosmos valset, but we rebuilt another one from the events
+	mockGravityContract.EXPECT().
+		GetValsetCheckpoint(gomock.Any(), fromAddress, big.NewInt(112)).
+		Return(gravity.EncodeValsetConfirm(gravityID, cosmosValset), nil)
+
+	relayer := gravityRelayer{
+		logger:            logger,
+		cosmosQueryClient: mockQClient,
+		gravityContract:   mockGravityContract,
+		ethProvider:       ethProvider,
+	}
+
+	valset, err := relayer.FindLatestValset(context.Background())
+	assert.Nil(t, valset)
+	assert.True(t, errors.Is(err, ErrValsetCheckpointMismatch))
+	assert.EqualError(t, err, "valset 2 (reward amount 0 on Ethereum, 10 on Cosmos): "+
+		"valset doesn't match the contract checkpoint")
+}
+
+func TestDiffValsets(t *testing.T) {
+	memberA := "0x000000000000000000000000000000000000000A"
+	memberB := "0x000000000000000000000000000000000000000B"
+	memberC := "0x000000000000000000000000000000000000000C"
+
+	cosmosValset := &types.Valset{
+		Members: []types.BridgeValidator{
+			{Power: 200, EthereumAddress: memberA},
+			{Power: 100, EthereumAddress: memberB},
+		},
+		RewardAmount: sdk.NewInt(0),
+		RewardToken:  "0x0000000000000000000000000000000000000000",
+	}
+
+	testCases := []struct {
+		name      string
+		ethValset *types.Valset
+		expected  []string
+	}{
+		{
+			name:      "same valset",
+			ethValset: copyValset(cosmosValset),
+			expected:  nil,
+		},
+		{
+			name: "different members and powers",
+			ethValset: &types.Valset{
+				Members: []types.BridgeValidator{
+					{Power: 150, EthereumAddress: memberA},
+					{Power: 100, EthereumAddress: memberC},
+				},
+				RewardAmount: sdk.NewInt(0),
+				RewardToken:  "0x0000000000000000000000000000000000000000",
+			},
+			expected: []string{
+				"member 0x000000000000000000000000000000000000000A has power 150 on Ethereum, 200 on Cosmos",
+				"member 0x000000000000000000000000000000000000000b missing on Ethereum",
+				"member 0x000000000000000000000000000000000000000C missing on Cosmos",
+			},
+		},
+		{
+			name: "different order and reward token",
+			ethValset: &types.Valset{
+				Members: []types.BridgeValidator{
+					{Power: 100, EthereumAddress: memberB},
+					{Power: 200, EthereumAddress: memberA},
+				},
+				RewardAmount: sdk.NewInt(0),
+				RewardToken:  memberC,
+			},
+			expected: []string{
+				"members in a different order from position 0",
+				"reward token 0x000000000000000000000000000000000000000C on Ethereum, " +
+					"0x0000000000000000000000000000000000000000 on Cosmos",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, diffValsets(tc.ethValset, cosmosValset))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package orchestrator
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// endpointDialTimeout is how long an endpoint is given to accept a connection when it's checked.
+const endpointDialTimeout = 5 * time.Second
+
+// Role is one of the duties of the orchestrator, each one run by its own loop. Splitting them lets different
+// processes or hosts share the work of a single validator.
+type Role string
+
+const (
+	// RoleOracle relays the Gravity events on Ethereum to Cosmos as claims.
+	RoleOracle Role = "oracle"
+	// RoleSigner signs the valset updates and batches requested on Cosmos.
+	RoleSigner Role = "signer"
+	// RoleRequester requests batches of the pending transfers on Cosmos.
+	RoleRequester Role = "requester"
+	// RoleRelayer relays the signed valset updates and batches to Ethereum.
+	RoleRelayer Role = "relayer"
+)
+
+// AllRoles are all the orchestrator roles, the ones it runs by default.
+var AllRoles = []Role{RoleOracle, RoleSigner, RoleRequester, RoleRelayer}
+
+// ParseRoles parses a comma separated list of roles, e.g. "oracle,signer".
+func ParseRoles(s string) ([]Role, error) {
+	var roles []Role
+
+	for _, r := range strings.Split(s, ",") {
+		role := Role(strings.TrimSpace(r))
+		if role == "" {
+			continue
+		}
+
+		if !HasRole(AllRoles, role) {
+			return nil, errors.Errorf("invalid role: %s", role)
+		}
+
+		if !HasRole(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	if len(roles) == 0 {
+		return nil, errors.New("no role selected")
+	}
+
+	return roles, nil
+}
+
+// HasRole returns true if any of the given roles is in roles.
+func HasRole(roles []Role, role ...Role) bool {
+	for _, r := range roles {
+		for _, other := range role {
+			if r == other {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CheckEndpoint checks that something listens at the endpoint needed by a role: a URL, a unix socket URL
+// (unix:///tmp/test.sock) or the path of an IPC socket.
+func CheckEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case u.Scheme == "unix":
+		_, err := os.Stat(u.Path)
+		return err
+
+	case u.Host == "":
+		_, err := os.Stat(endpoint)
+		return err
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "https", "wss":
+			host = net.JoinHostPort(u.Hostname(), "443")
+		default:
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	conn, err := net.DialTimeout("tcp", host, endpointDialTimeout)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
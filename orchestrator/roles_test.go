@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umee-network/peggo/orchestrator/relayer"
+)
+
+func TestParseRoles(t *testing.T) {
+	roles, err := ParseRoles("oracle, signer,oracle")
+	require.NoError(t, err)
+	assert.Equal(t, []Role{RoleOracle, RoleSigner}, roles)
+
+	roles, err = ParseRoles("oracle,signer,requester,relayer")
+	require.NoError(t, err)
+	assert.Equal(t, AllRoles, roles)
+
+	_, err = ParseRoles("oracle,validator")
+	assert.EqualError(t, err, "invalid role: validator")
+
+	_, err = ParseRoles(" , ")
+	assert.EqualError(t, err, "no role selected")
+}
+
+func TestHasRole(t *testing.T) {
+	roles := []Role{RoleOracle, RoleRequester}
+
+	assert.True(t, HasRole(roles, RoleOracle))
+	assert.True(t, HasRole(roles, RoleSigner, RoleRequester))
+	assert.False(t, HasRole(roles, RoleSigner, RoleRelayer))
+	assert.False(t, HasRole(nil, RoleOracle))
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listener.Addr().String()
+	assert.NoError(t, CheckEndpoint("tcp://"+addr))
+	assert.NoError(t, CheckEndpoint("http://"+addr))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, CheckEndpoint("http://"+addr))
+
+	// unix sockets, as accepted by the Cosmos clients, and IPC paths
+	sockPath := filepath.Join(t.TempDir(), "test.sock")
+	assert.Error(t, CheckEndpoint("unix://"+sockPath))
+
+	sock, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+	defer sock.Close()
+
+	assert.NoError(t, CheckEndpoint("unix://"+sockPath))
+	assert.NoError(t, CheckEndpoint(sockPath))
+}
+
+// fakeRelayer only records that it was started.
+type fakeRelayer struct {
+	relayer.GravityRelayer
+
+	started bool
+}
+
+func (r *fakeRelayer) Start(ctx context.Context) error {
+	r.started = true
+	return nil
+}
+
+func TestStartRoles(t *testing.T) {
+	fake := &fakeRelayer{}
+
+	// the other loops can't run without any Cosmos or Ethereum client
+	orch := &gravityOrchestrator{relayer: fake}
+	orch.SetRoles(RoleRelayer)
+
+	require.NoError(t, orch.Start(context.Background()))
+	assert.True(t, fake.started)
+
+	orch.SetRoles()
+	assert.EqualError(t, orch.Start(context.Background()), "no role selected")
+}
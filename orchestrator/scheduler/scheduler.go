@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gravitytypes "github.com/Gravity-Bridge/Gravity-Bridge/module/x/gravity/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	tmclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/umee-network/peggo/orchestrator/loops"
+)
+
+// Event is something that happened on either chain and that a loop may want to react to right away.
+type Event int
+
+const (
+	// EthereumNewHead is triggered every time a new Ethereum block is received.
+	EthereumNewHead Event = iota
+	// CosmosValsetRequest is triggered when the Gravity module creates a new valset request.
+	CosmosValsetRequest
+	// CosmosBatchCreated is triggered when the Gravity module creates a new outgoing tx batch.
+	CosmosBatchCreated
+)
+
+const (
+	subscriberName  = "peggo"
+	resubscribeWait = 5 * time.Second
+	eventsCapacity  = 100
+)
+
+var (
+	// valsetRequestQuery matches the blocks in which the Gravity module created a new valset request (this happens in
+	// the EndBlocker).
+	valsetRequestQuery = fmt.Sprintf(
+		"tm.event='NewBlock' AND %s.%s EXISTS",
+		gravitytypes.EventTypeMultisigUpdateRequest,
+		gravitytypes.AttributeKeyNonce,
+	)
+
+	// batchCreatedQuery matches the txs in which a new outgoing tx batch has been created.
+	batchCreatedQuery = fmt.Sprintf(
+		"tm.event='Tx' AND %s.%s EXISTS",
+		gravitytypes.EventTypeOutgoingBatch,
+		gravitytypes.AttributeKeyNonce,
+	)
+)
+
+// EventScheduler subscribes to new Ethereum heads and Gravity events on Tendermint and wakes up the loops interested in
+// them, so they don't have to wait until their next tick. A nil *EventScheduler is valid and never wakes anything up.
+type EventScheduler struct {
+	logger zerolog.Logger
+
+	// resubscribeWait is how long to wait before re-establishing a subscription that dropped.
+	resubscribeWait time.Duration
+
+	mtx     sync.RWMutex
+	wakeUps map[Event][]loops.WakeUp
+}
+
+func NewEventScheduler(logger zerolog.Logger) *EventScheduler {
+	return &EventScheduler{
+		logger:          logger.With().Str("module", "event_scheduler").Logger(),
+		resubscribeWait: resubscribeWait,
+		wakeUps:         map[Event][]loops.WakeUp{},
+	}
+}
+
+// WakeUpOn returns a WakeUp that is notified every time any of the given events happens. It returns nil (which
+// never fires) if s is nil.
+func (s *EventScheduler) WakeUpOn(events ...Event) loops.WakeUp {
+	if s == nil {
+		return nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	wakeUp := loops.NewWakeUp()
+	for _, ev := range events {
+		s.wakeUps[ev] = append(s.wakeUps[ev], wakeUp)
+	}
+
+	return wakeUp
+}
+
+// Notify wakes up all the loops interested in the given event.
+func (s *EventScheduler) Notify(ev Event) {
+	if s == nil {
+		return
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	for _, wakeUp := range s.wakeUps[ev] {
+		wakeUp.Notify()
+	}
+}
+
+// SubscribeEthereumHeads subscribes to newHeads on the given Ethereum websocket endpoint and notifies EthereumNewHead
+// for every block received. The subscription is re-established if it drops, it only returns when ctx is done.
+func (s *EventScheduler) SubscribeEthereumHeads(ctx context.Context, ethWSURL string) error {
+	logger := s.logger.With().Str("endpoint", ethWSURL).Logger()
+
+	for {
+		if err := s.subscribeEthereumHeads(ctx, ethWSURL); err != nil {
+			logger.Err(err).Msg("Ethereum heads subscription failed; resubscribing...")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.resubscribeWait):
+		}
+	}
+}
+
+func (s *EventScheduler) subscribeEthereumHeads(ctx context.Context, ethWSURL string) error {
+	rc, err := ethrpc.DialContext(ctx, ethWSURL)
+	if err != nil {
+		return err
+	}
+
+	defer rc.Close()
+
+	heads := make(chan *ethtypes.Header, eventsCapacity)
+	sub, err := ethclient.NewClient(rc).SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+
+	defer sub.Unsubscribe()
+
+	s.logger.Info().Msg("subscribed to Ethereum heads")
+
+	for {
+		select {
+		case head := <-heads:
+			s.logger.Debug().Str("block", head.Number.String()).Msg("new Ethereum head")
+			s.Notify(EthereumNewHead)
+
+		case err := <-sub.Err():
+			return err
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// SubscribeCosmosEvents subscribes to the creation of valset requests and outgoing batches on the Tendermint
+// websocket and notifies CosmosValsetRequest and CosmosBatchCreated respectively. The client must have been started
+// already. The subscriptions are re-established if they can't be created or if they drop, it only returns when ctx
+// is done.
+func (s *EventScheduler) SubscribeCosmosEvents(ctx context.Context, client tmclient.EventsClient) error {
+	for {
+		if err := s.subscribeCosmosEvents(ctx, client); err != nil {
+			s.logger.Err(err).Msg("Tendermint events subscription failed; resubscribing...")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.resubscribeWait):
+		}
+	}
+}
+
+func (s *EventScheduler) subscribeCosmosEvents(ctx context.Context, client tmclient.EventsClient) error {
+	defer func() {
+		// ctx may be done by now, so we need a fresh one to unsubscribe
+		unsubCtx, cancel := context.WithTimeout(context.Background(), resubscribeWait)
+		defer cancel()
+
+		if err := client.UnsubscribeAll(unsubCtx, subscriberName); err != nil {
+			s.logger.Err(err).Msg("failed to unsubscribe from Tendermint events")
+		}
+	}()
+
+	valsetRequests, err := client.Subscribe(ctx, subscriberName, valsetRequestQuery, eventsCapacity)
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe to valset requests")
+	}
+
+	batchesCreated, err := client.Subscribe(ctx, subscriberName, batchCreatedQuery, eventsCapacity)
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe to batch creation")
+	}
+
+	s.logger.Info().Msg("subscribed to Tendermint events")
+
+	for {
+		select {
+		case _, ok := <-valsetRequests:
+			if !ok {
+				return errors.New("valset requests subscription closed")
+			}
+
+			s.logger.Debug().Msg("new valset request")
+			s.Notify(CosmosValsetRequest)
+
+		case _, ok := <-batchesCreated:
+			if !ok {
+				return errors.New("batch creation subscription closed")
+			}
+
+			s.logger.Debug().Msg("new outgoing batch")
+			s.Notify(CosmosBatchCreated)
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
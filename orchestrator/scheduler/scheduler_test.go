@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+type fakeEventsClient struct {
+	mtx          sync.Mutex
+	subs         map[string]chan ctypes.ResultEvent
+	unsubscribed int
+	// subErr is returned by the next failures calls to Subscribe.
+	subErr   error
+	failures int
+}
+
+func (c *fakeEventsClient) Subscribe(
+	ctx context.Context,
+	subscriber, query string,
+	outCapacity ...int,
+) (<-chan ctypes.ResultEvent, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.failures > 0 {
+		c.failures--
+		return nil, c.subErr
+	}
+
+	ch := make(chan ctypes.ResultEvent, 1)
+	c.subs[query] = ch
+	return ch, nil
+}
+
+func (c *fakeEventsClient) sub(query string) chan ctypes.ResultEvent {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.subs[query]
+}
+
+func (c *fakeEventsClient) Unsubscribe(ctx context.Context, subscriber, query string) error {
+	return nil
+}
+
+func (c *fakeEventsClient) UnsubscribeAll(ctx context.Context, subscriber string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.subs = map[string]chan ctypes.ResultEvent{}
+	c.unsubscribed++
+	return nil
+}
+
+func (c *fakeEventsClient) unsubscriptions() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.unsubscribed
+}
+
+func TestEventScheduler(t *testing.T) {
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	t.Run("nil scheduler", func(t *testing.T) {
+		var s *EventScheduler
+		assert.Nil(t, s.WakeUpOn(EthereumNewHead))
+		assert.NotPanics(t, func() { s.Notify(EthereumNewHead) })
+	})
+
+	t.Run("notify", func(t *testing.T) {
+		s := NewEventScheduler(logger)
+		oracle := s.WakeUpOn(EthereumNewHead)
+		signer := s.WakeUpOn(CosmosValsetRequest, CosmosBatchCreated)
+
+		// notifications are coalesced
+		s.Notify(CosmosValsetRequest)
+		s.Notify(CosmosBatchCreated)
+
+		assert.Len(t, signer, 1)
+		assert.Len(t, oracle, 0)
+
+		s.Notify(EthereumNewHead)
+		assert.Len(t, oracle, 1)
+	})
+
+	t.Run("cosmos events", func(t *testing.T) {
+		s := NewEventScheduler(logger)
+		signer := s.WakeUpOn(CosmosBatchCreated)
+
+		client := &fakeEventsClient{subs: map[string]chan ctypes.ResultEvent{}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- s.SubscribeCosmosEvents(ctx, client)
+		}()
+
+		assert.Eventually(t, func() bool { return client.sub(batchCreatedQuery) != nil }, time.Second, time.Millisecond)
+
+		client.sub(batchCreatedQuery) <- ctypes.ResultEvent{}
+
+		select {
+		case <-signer:
+		case <-time.After(time.Second):
+			t.Fatal("signer wasn't woken up")
+		}
+
+		cancel()
+		assert.NoError(t, <-errCh)
+		assert.Equal(t, 1, client.unsubscriptions())
+	})
+
+	t.Run("cosmos resubscription", func(t *testing.T) {
+		s := NewEventScheduler(logger)
+		s.resubscribeWait = time.Millisecond
+		signer := s.WakeUpOn(CosmosValsetRequest)
+
+		// the first subscription can't be created
+		client := &fakeEventsClient{
+			subs:     map[string]chan ctypes.ResultEvent{},
+			subErr:   errors.New("client not running"),
+			failures: 1,
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- s.SubscribeCosmosEvents(ctx, client)
+		}()
+
+		assert.Eventually(t, func() bool { return client.sub(batchCreatedQuery) != nil }, time.Second, time.Millisecond)
+		assert.Equal(t, 1, client.unsubscriptions())
+
+		// the subscription drops
+		close(client.sub(valsetRequestQuery))
+
+		assert.Eventually(t, func() bool { return client.unsubscriptions() == 2 }, time.Second, time.Millisecond)
+		assert.Eventually(t, func() bool { return client.sub(valsetRequestQuery) != nil }, time.Second, time.Millisecond)
+
+		client.sub(valsetRequestQuery) <- ctypes.ResultEvent{}
+
+		select {
+		case <-signer:
+		case <-time.After(time.Second):
+			t.Fatal("signer wasn't woken up")
+		}
+
+		cancel()
+		assert.NoError(t, <-errCh)
+	})
+}